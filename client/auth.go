@@ -20,8 +20,9 @@ import (
 //
 // The available commands are "login" and "unique". The "login" command
 // takes a username and private key as arguments, and performs a login
-// operation with the server. The "unique" command takes a unique key as
-// an argument, and performs a login operation with the unique key.
+// operation with the server. The "unique" command takes the unique key of
+// the server, a username and a public key as arguments, and bootstraps the
+// server with an administrator with that username and public key.
 //
 // If the login is successful, the function sets the
 // OPHELIA_CI_CLIENT_TOKEN environment variable to the token returned by the
@@ -48,13 +49,15 @@ func handleAuthCommands(ctx context.Context, client pb.AuthServiceClient, comman
 		}
 
 	case "unique":
-		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci auth unique --key <unique-key>")
+		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci auth unique --key <unique-key> --username <username> --public-key <public-key>")
 
 		uniqueCmd := flag.NewFlagSet("unique", flag.ExitOnError)
 		uniqueKey := uniqueCmd.String("key", "", "Unique Key")
+		username := uniqueCmd.String("username", "", "Administrator Username")
+		publicKey := uniqueCmd.String("public-key", "", "Administrator Public Key")
 		uniqueCmd.Parse(args)
 
-		token, err = uniqueKeyLogin(ctx, client, *uniqueKey)
+		token, err = uniqueKeyLogin(ctx, client, *uniqueKey, *username, *publicKey)
 		if err != nil {
 			log.Fatalf("Unique key login failed: %v", err)
 		}
@@ -75,7 +78,7 @@ func printAuthHelp() {
 	fmt.Println("Usage: ophelia-ci auth <command>")
 	fmt.Println("Commands:")
 	fmt.Println("	login	Authenticate a user using their username and private key")
-	fmt.Println("	unique	Authenticate a user using the server unique key")
+	fmt.Println("	unique	Create the first administrator using the server unique key")
 }
 
 func setToken(token string) {
@@ -140,23 +143,31 @@ func login(ctx context.Context, client pb.AuthServiceClient, username, privateKe
 	return authResponse.Token, nil
 }
 
-// uniqueKeyLogin authenticates a user using a unique key, and returns a JWT token if the authentication is successful.
+// uniqueKeyLogin bootstraps the server using its unique key, creating an administrator
+// with the given username and public key, and returns the JWT token of the administrator
+// if the authentication is successful.
 //
 // Parameters:
 //   - ctx: The context for the request, which carries deadlines, cancellation signals,
 //     and other request-scoped values.
 //   - client: The client to use for the authentication request.
 //   - uniqueKey: The unique key for authentication.
+//   - username: The username of the administrator.
+//   - publicKey: The path to the public key file of the administrator.
 //
 // Returns:
 // - string: The JWT token if the authentication is successful, or an empty string if the authentication fails.
 // - error: An error if the authentication fails.
-func uniqueKeyLogin(ctx context.Context, client pb.AuthServiceClient, uniqueKey string) (string, error) {
-	if uniqueKey == "" {
-		return "", fmt.Errorf("unique key is required")
+func uniqueKeyLogin(ctx context.Context, client pb.AuthServiceClient, uniqueKey, username, publicKey string) (string, error) {
+	if uniqueKey == "" || username == "" || publicKey == "" {
+		return "", fmt.Errorf("unique key, username and public key are required")
+	}
+	publicKeyString, err := readPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read public key: %w", err)
 	}
 
-	authResponse, err := client.UniqueKeyLogin(ctx, &pb.UniqueKeyLoginRequest{UniqueKey: uniqueKey})
+	authResponse, err := client.UniqueKeyLogin(ctx, &pb.UniqueKeyLoginRequest{UniqueKey: uniqueKey, Username: username, PublicKey: publicKeyString})
 	if err != nil {
 		return "", fmt.Errorf("unique key login failed: %w", err)
 	}
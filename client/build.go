@@ -0,0 +1,422 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// logStreamRetries is the number of times an interrupted log stream is
+	// resumed before giving up.
+	logStreamRetries = 5
+	// logStreamRetryDelay is the time waited before resuming a log stream.
+	logStreamRetryDelay = 2 * time.Second
+)
+
+// handleBuildCommands parses command line arguments for the build command and makes the right call to the BuildServiceClient.
+// The commands available are:
+// - list: Retrieves a list of the builds of a repository
+// - show: Retrieves a build by ID, with its jobs
+// - logs: Prints the logs of a build, optionally following them
+// - cancel: Cancels a build by ID
+// - retry: Starts a new build of the same commit as a build
+// - trigger: Starts a build of a repository branch, tag or commit, with parameters
+// - artifacts: Downloads the artifacts of a build into a directory
+func handleBuildCommands(ctx context.Context, client pb.BuildServiceClient, command string, args []string) {
+	switch command {
+	case "--help":
+		printBuildHelp()
+		return
+	case "logs", "artifacts":
+		// Following logs and downloading artifacts may take longer than the
+		// default request timeout.
+		ctx = context.WithoutCancel(ctx)
+	}
+	ctx = authenticateContext(ctx)
+
+	switch command {
+	case "list":
+		listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+		listRepo := listCmd.String("repo", "", "Repository Name")
+		listCmd.Parse(args)
+		ListBuilds(ctx, client, *listRepo)
+	case "show":
+		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci build show --id <id>")
+		showCmd := flag.NewFlagSet("show", flag.ExitOnError)
+		showID := showCmd.String("id", "", "Build ID")
+		showCmd.Parse(args)
+		GetBuild(ctx, client, *showID)
+	case "logs":
+		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci build logs --id <id> [--follow] [--offset <offset>]")
+		logsCmd := flag.NewFlagSet("logs", flag.ExitOnError)
+		logsID := logsCmd.String("id", "", "Build ID")
+		logsFollow := logsCmd.Bool("follow", false, "Follow the logs until the build finishes")
+		logsOffset := logsCmd.Int64("offset", 0, "Offset of the first line to print")
+		logsCmd.Parse(args)
+		StreamBuildLogs(ctx, client, *logsID, *logsOffset, *logsFollow)
+	case "cancel":
+		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci build cancel --id <id>")
+		cancelCmd := flag.NewFlagSet("cancel", flag.ExitOnError)
+		cancelID := cancelCmd.String("id", "", "Build ID")
+		cancelCmd.Parse(args)
+		CancelBuild(ctx, client, *cancelID)
+	case "retry":
+		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci build retry --id <id>")
+		retryCmd := flag.NewFlagSet("retry", flag.ExitOnError)
+		retryID := retryCmd.String("id", "", "Build ID")
+		retryCmd.Parse(args)
+		RetryBuild(ctx, client, *retryID)
+	case "trigger":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci build trigger --repo <repo> --ref <branch, tag or commit> [--param <name>=<value>]...")
+		triggerCmd := flag.NewFlagSet("trigger", flag.ExitOnError)
+		triggerRepo := triggerCmd.String("repo", "", "Repository Name")
+		triggerRef := triggerCmd.String("ref", "", "Branch, tag or commit hash")
+		triggerBranch := triggerCmd.String("branch", "", "Branch (same as --ref)")
+		triggerParams := parameterFlag{}
+		triggerCmd.Var(triggerParams, "param", "Build parameter as name=value, may be repeated")
+		triggerCmd.Parse(args)
+		TriggerBuild(ctx, client, *triggerRepo, cmp.Or(*triggerRef, *triggerBranch), triggerParams)
+	case "artifacts":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci build artifacts --id <id> --out <directory> [--job <job>]")
+		artifactsCmd := flag.NewFlagSet("artifacts", flag.ExitOnError)
+		artifactsID := artifactsCmd.String("id", "", "Build ID")
+		artifactsOut := artifactsCmd.String("out", "", "Directory the artifacts are saved to")
+		artifactsJob := artifactsCmd.String("job", "", "Name of the job whose artifacts are downloaded")
+		artifactsCmd.Parse(args)
+		DownloadArtifacts(ctx, client, *artifactsID, *artifactsJob, *artifactsOut)
+	default:
+		fmt.Println("Invalid build command. Use: list, show, logs, cancel, retry, trigger, artifacts")
+		os.Exit(1)
+	}
+}
+
+func printBuildHelp() {
+	fmt.Println("Usage: ophelia-ci build <command> [arguments]")
+	fmt.Println("Commands:")
+	fmt.Println("	list	List the builds of a repository")
+	fmt.Println("	show	Show information about a build and its jobs by ID")
+	fmt.Println("	logs	Print the logs of a build by ID")
+	fmt.Println("	cancel	Cancel a build by ID")
+	fmt.Println("	retry	Retry a build by ID")
+	fmt.Println("	trigger	Trigger a build of a repository branch, tag or commit")
+	fmt.Println("	artifacts	Download the artifacts of a build by ID")
+}
+
+// ListBuilds retrieves and prints the builds of a repository, newest first.
+//
+// If the repository name is empty, the builds of every repository are printed.
+// If there is an error during the request, the function logs the error and
+// terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - repo: The name of the repository whose builds are listed.
+func ListBuilds(ctx context.Context, client pb.BuildServiceClient, repo string) {
+	res, err := client.ListBuilds(ctx, &pb.ListBuildsRequest{Repository: repo})
+	if err != nil {
+		log.Fatalf("failed to list builds: %v", err)
+	}
+	fmt.Println("Builds:")
+	for _, build := range res.Builds {
+		printBuild(build)
+	}
+	fmt.Println("")
+}
+
+// GetBuild retrieves and prints a build by its ID, along with its jobs.
+//
+// If the ID is empty, the function prints an error message and exits the
+// program. If there is an error during the request, the function logs the
+// error and terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - id: The ID of the build to retrieve.
+func GetBuild(ctx context.Context, client pb.BuildServiceClient, id string) {
+	if id == "" {
+		fmt.Println("Missing ID")
+		os.Exit(1)
+		return
+	}
+	res, err := client.GetBuild(ctx, &pb.GetBuildRequest{Id: id})
+	if err != nil {
+		log.Fatalf("failed to get build: %v", err)
+	}
+	fmt.Println("Build:")
+	printBuild(res)
+	if len(res.Parameters) > 0 {
+		fmt.Println("Parameters:")
+		names := slices.Sorted(maps.Keys(res.Parameters))
+		for _, name := range names {
+			fmt.Printf("%s: %s\n", name, res.Parameters[name])
+		}
+	}
+	fmt.Println("Jobs:")
+	for _, job := range res.Jobs {
+		fmt.Printf("ID: %s, Stage: %s, Name: %s, Status: %s, Exit Code: %d, Started: %s, Finished: %s",
+			job.Id, job.Stage, job.Name, job.Status, job.ExitCode, formatTimestamp(job.StartedAt), formatTimestamp(job.FinishedAt))
+		if len(job.Matrix) > 0 {
+			fmt.Printf(", Matrix: %s", formatMatrix(job.Matrix))
+		}
+		fmt.Println()
+	}
+	fmt.Println("")
+}
+
+// StreamBuildLogs prints the logs of a build, starting at the given line offset.
+//
+// If follow is true, new lines are printed as they are produced until the build
+// finishes. If the stream is interrupted because the server is unavailable, it
+// is resumed from the last printed line.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - id: The ID of the build whose logs are printed.
+// - offset: The offset of the first line to print.
+// - follow: Whether to keep printing new lines until the build finishes.
+func StreamBuildLogs(ctx context.Context, client pb.BuildServiceClient, id string, offset int64, follow bool) {
+	if id == "" {
+		fmt.Println("Missing ID")
+		os.Exit(1)
+		return
+	}
+
+	for retries := 0; ; retries++ {
+		err := streamBuildLogs(ctx, client, id, &offset, follow)
+		if err == nil {
+			return
+		}
+		if status.Code(err) != codes.Unavailable || retries >= logStreamRetries {
+			log.Fatalf("failed to stream build logs: %v", err)
+		}
+		log.Printf("Build log stream interrupted, resuming from line %d: %v", offset, err)
+		time.Sleep(logStreamRetryDelay)
+	}
+}
+
+// streamBuildLogs prints the lines of a single log stream, advancing offset
+// past each printed line so that the stream can be resumed.
+func streamBuildLogs(ctx context.Context, client pb.BuildServiceClient, id string, offset *int64, follow bool) error {
+	stream, err := client.StreamBuildLogs(ctx, &pb.StreamBuildLogsRequest{Id: id, Offset: *offset, Follow: follow})
+	if err != nil {
+		return err
+	}
+	for {
+		line, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if line.Job == "" {
+			fmt.Println(line.Text)
+		} else {
+			fmt.Printf("[%s] %s\n", line.Job, line.Text)
+		}
+		*offset = line.Offset + 1
+	}
+}
+
+// CancelBuild cancels a queued or running build by its ID.
+//
+// If the ID is empty, the function prints an error message and exits the
+// program. If the cancellation fails, it logs the error and terminates the
+// program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - id: The ID of the build to be cancelled.
+func CancelBuild(ctx context.Context, client pb.BuildServiceClient, id string) {
+	if id == "" {
+		fmt.Println("Missing ID")
+		os.Exit(1)
+		return
+	}
+	res, err := client.CancelBuild(ctx, &pb.CancelBuildRequest{Id: id})
+	if err != nil {
+		log.Fatalf("failed to cancel build: %v", err)
+	}
+	fmt.Printf("Cancelled Build: ID: %s, Status: %s\n\n", res.Id, res.Status)
+}
+
+// RetryBuild starts a new build of the same commit as the build with the given ID.
+//
+// If the ID is empty, the function prints an error message and exits the
+// program. If the retry fails, it logs the error and terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - id: The ID of the build to be retried.
+func RetryBuild(ctx context.Context, client pb.BuildServiceClient, id string) {
+	if id == "" {
+		fmt.Println("Missing ID")
+		os.Exit(1)
+		return
+	}
+	res, err := client.RetryBuild(ctx, &pb.RetryBuildRequest{Id: id})
+	if err != nil {
+		log.Fatalf("failed to retry build: %v", err)
+	}
+	fmt.Println("Build started:")
+	printBuild(res)
+	fmt.Println("")
+}
+
+// TriggerBuild starts a build of a repository branch, tag or commit.
+//
+// If the repository or ref is empty, the function prints an error message
+// and exits the program. If the trigger fails, it logs the error and terminates
+// the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - repo: The name of the repository to be built.
+// - ref: The branch, tag or commit hash to be built.
+// - parameters: The values of the parameters declared by the pipeline.
+func TriggerBuild(ctx context.Context, client pb.BuildServiceClient, repo, ref string, parameters map[string]string) {
+	if repo == "" || ref == "" {
+		fmt.Println("Missing Repository or Ref")
+		os.Exit(1)
+		return
+	}
+	res, err := client.TriggerBuild(ctx, &pb.TriggerBuildRequest{Repository: repo, Ref: ref, Parameters: parameters})
+	if err != nil {
+		log.Fatalf("failed to trigger build: %v", err)
+	}
+	fmt.Println("Build started:")
+	printBuild(res)
+	fmt.Println("")
+}
+
+// parameterFlag collects the repeated name=value flags of build parameters.
+type parameterFlag map[string]string
+
+func (p parameterFlag) String() string {
+	return fmt.Sprint(map[string]string(p))
+}
+
+func (p parameterFlag) Set(value string) error {
+	name, parameter, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("parameter must be given as name=value")
+	}
+	p[name] = parameter
+	return nil
+}
+
+// DownloadArtifacts downloads the artifacts of the jobs of a build into a
+// directory, as one gzipped tar archive per job named after the job.
+//
+// If there is an error during the request, the function logs the error and
+// terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The BuildServiceClient used to access the build service.
+// - id: The ID of the build whose artifacts are downloaded.
+// - job: The name of the job whose artifacts are downloaded, or empty for every job.
+// - out: The directory the archives are saved to.
+func DownloadArtifacts(ctx context.Context, client pb.BuildServiceClient, id, job, out string) {
+	if id == "" || out == "" {
+		fmt.Println("Missing ID or output directory")
+		os.Exit(1)
+		return
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		log.Fatalf("failed to create output directory: %v", err)
+	}
+
+	stream, err := client.DownloadArtifact(ctx, &pb.DownloadArtifactRequest{BuildId: id, Job: job})
+	if err != nil {
+		log.Fatalf("failed to download artifacts: %v", err)
+	}
+
+	var file *os.File
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("failed to download artifacts: %v", err)
+		}
+		if file == nil || file.Name() != artifactPath(out, chunk.Job) {
+			if file != nil {
+				file.Close()
+				fmt.Printf("Saved %s\n", file.Name())
+			}
+			if file, err = os.Create(artifactPath(out, chunk.Job)); err != nil {
+				log.Fatalf("failed to save artifacts: %v", err)
+			}
+		}
+		if _, err := file.Write(chunk.Data); err != nil {
+			log.Fatalf("failed to save artifacts: %v", err)
+		}
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			log.Fatalf("failed to save artifacts: %v", err)
+		}
+		fmt.Printf("Saved %s\n", file.Name())
+	}
+}
+
+// artifactPath returns the path of the artifacts archive of a job in the out
+// directory, replacing the path separators of the job name.
+func artifactPath(out, job string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, job)
+	return filepath.Join(out, name+".tar.gz")
+}
+
+// printBuild prints the summary of a build in a single line.
+func printBuild(build *pb.BuildResponse) {
+	fmt.Printf("ID: %s, Commit: %s, Branch: %s, Tag: %s, Status: %s, Triggered By: %s, Created: %s, Finished: %s\n",
+		build.Id, build.CommitHash, build.Branch, build.Tag, build.Status, build.TriggerUser, formatTimestamp(build.CreatedAt), formatTimestamp(build.FinishedAt))
+}
+
+// formatTimestamp formats an optional timestamp in the local time zone, or
+// returns "-" if it is not set.
+func formatTimestamp(timestamp *timestamppb.Timestamp) string {
+	if timestamp == nil {
+		return "-"
+	}
+	return timestamp.AsTime().Local().Format(time.DateTime)
+}
+
+// formatMatrix formats the matrix values of a job as name=value pairs sorted by
+// name.
+func formatMatrix(matrix map[string]string) string {
+	var values []string
+	for _, name := range slices.Sorted(maps.Keys(matrix)) {
+		values = append(values, name+"="+matrix[name])
+	}
+	return strings.Join(values, ", ")
+}
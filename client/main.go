@@ -17,7 +17,7 @@ import (
 //
 // The client takes three arguments:
 //
-//   1. The service name (one of "repo", "user", "auth", or "signal").
+//   1. The service name (one of "repo", "user", "auth", "build", "secret", or "signal").
 //   2. The command name (service-specific).
 //   3. The command arguments (service-specific).
 //
@@ -56,6 +56,8 @@ func printOpheliaHelp() {
 	fmt.Println("	repo	Repository service")
 	fmt.Println("	user	User service")
 	fmt.Println("	auth	Authentication service")
+	fmt.Println("	build	Build service")
+	fmt.Println("	secret	Secret service")
 }
 
 func printHelp(service string) {
@@ -66,6 +68,10 @@ func printHelp(service string) {
 		printUserHelp()
 	case "auth":
 		printAuthHelp()
+	case "build":
+		printBuildHelp()
+	case "secret":
+		printSecretHelp()
 	default:
 		printOpheliaHelp()
 	}
@@ -76,6 +82,8 @@ func handleCommands(ctx context.Context, conn *grpc.ClientConn, service, command
 	userClient := pb.NewUserServiceClient(conn)
 	authClient := pb.NewAuthServiceClient(conn)
 	signalClient := pb.NewSignalsClient(conn)
+	buildClient := pb.NewBuildServiceClient(conn)
+	secretClient := pb.NewSecretServiceClient(conn)
 
 	switch service {
 	case "--help":
@@ -90,8 +98,12 @@ func handleCommands(ctx context.Context, conn *grpc.ClientConn, service, command
 		handleAuthCommands(ctx, authClient, command, args)
 	case "signal":
 		handleSignals(ctx, signalClient, command, args)
+	case "build":
+		handleBuildCommands(ctx, buildClient, command, args)
+	case "secret":
+		handleSecretCommands(ctx, secretClient, command, args)
 	default:
-		fmt.Println("Invalid service. Use: repo, user, auth")
+		fmt.Println("Invalid service. Use: repo, user, auth, build, secret")
 		os.Exit(1)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
 )
@@ -18,6 +19,8 @@ import (
 // - update: Updates a repository by ID
 // - create: Creates a new repository
 // - delete: Deletes a repository by ID
+// - grant: Grants a role on a repository to a user
+// - revoke: Revokes the role of a user on a repository
 func handleRepoCommands(ctx context.Context, client pb.RepositoryServiceClient, command string, args []string) {
 	ctx = authenticateContext(ctx)
 	switch command {
@@ -34,29 +37,46 @@ func handleRepoCommands(ctx context.Context, client pb.RepositoryServiceClient,
 		getCmd.Parse(args)
 		GetRepository(ctx, client, *getID, *getName)
 	case "update":
-		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci repo update --id <id> --name <name> --desc <desc>")
+		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci repo update --id <id> --name <name> --desc <desc> [--artifact-retention <days>]")
 		updateCmd := flag.NewFlagSet("update", flag.ExitOnError)
 		updateID := updateCmd.String("id", "", "Repository ID")
 		updateName := updateCmd.String("name", "", "Repository Name")
 		updateDesc := updateCmd.String("desc", "", "Repository Description")
+		updateRetention := updateCmd.Int("artifact-retention", 0, "Days the build artifacts are kept (unchanged if 0)")
 		updateCmd.Parse(args)
-		UpdateRepository(ctx, client, *updateID, *updateName, *updateDesc)
+		UpdateRepository(ctx, client, *updateID, *updateName, *updateDesc, int32(*updateRetention))
 	case "create":
-		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci repo create --name <name> --desc <desc> --gitignore <gitignore>")
+		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci repo create --name <name> --desc <desc> --gitignore <gitignore> [--artifact-retention <days>]")
 		createCmd := flag.NewFlagSet("create", flag.ExitOnError)
 		createName := createCmd.String("name", "", "Repository Name")
 		createDesc := createCmd.String("desc", "", "Repository Description")
 		createGitignore := createCmd.String("gitignore", "", "Repository Gitignore")
+		createRetention := createCmd.Int("artifact-retention", 0, "Days the build artifacts are kept (server default if 0)")
 		createCmd.Parse(args)
-		CreateRepository(ctx, client, *createName, *createDesc, *createGitignore)
+		CreateRepository(ctx, client, *createName, *createDesc, *createGitignore, int32(*createRetention))
 	case "delete":
 		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci repo delete --id <id>")
 		deleteCmd := flag.NewFlagSet("delete", flag.ExitOnError)
 		deleteID := deleteCmd.String("id", "", "Repository ID")
 		deleteCmd.Parse(args)
 		DeleteRepository(ctx, client, *deleteID)
+	case "grant":
+		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci repo grant --repo <repo> --username <username> --role <role>")
+		grantCmd := flag.NewFlagSet("grant", flag.ExitOnError)
+		grantRepo := grantCmd.String("repo", "", "Repository Name")
+		grantUsername := grantCmd.String("username", "", "User Username")
+		grantRole := grantCmd.String("role", "", "Role (reader, developer, maintainer or admin)")
+		grantCmd.Parse(args)
+		GrantRepositoryAccess(ctx, client, *grantRepo, *grantUsername, *grantRole)
+	case "revoke":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci repo revoke --repo <repo> --username <username>")
+		revokeCmd := flag.NewFlagSet("revoke", flag.ExitOnError)
+		revokeRepo := revokeCmd.String("repo", "", "Repository Name")
+		revokeUsername := revokeCmd.String("username", "", "User Username")
+		revokeCmd.Parse(args)
+		RevokeRepositoryAccess(ctx, client, *revokeRepo, *revokeUsername)
 	default:
-		fmt.Println("Invalid repo command. Use: list, show, update, create, delete")
+		fmt.Println("Invalid repo command. Use: list, show, update, create, delete, grant, revoke")
 		os.Exit(1)
 	}
 }
@@ -69,6 +89,8 @@ func printRepoHelp() {
 	fmt.Println("	update	Update a repository by ID")
 	fmt.Println("	create	Create a new repository")
 	fmt.Println("	delete	Delete a repository by ID")
+	fmt.Println("	grant	Grant a role on a repository to a user")
+	fmt.Println("	revoke	Revoke the role of a user on a repository")
 }
 
 // ListRepositories retrieves and prints a list of all repositories.
@@ -117,7 +139,7 @@ func GetRepository(ctx context.Context, client pb.RepositoryServiceClient, id, n
 		log.Fatalf("failed to get repository: %v", err)
 	}
 	fmt.Println("Repository:")
-	fmt.Printf("ID: %s, Name: %s, Description: %s\n\n", res.Id, res.Name, res.Description)
+	fmt.Printf("ID: %s, Name: %s, Description: %s, Artifact Retention: %d days\n\n", res.Id, res.Name, res.Description, res.ArtifactRetentionDays)
 }
 
 // UpdateRepository updates an existing repository with the given information.
@@ -134,8 +156,9 @@ func GetRepository(ctx context.Context, client pb.RepositoryServiceClient, id, n
 // - id: The ID of the repository to update.
 // - name: The name of the repository to update.
 // - desc: The description of the repository to update.
-func UpdateRepository(ctx context.Context, client pb.RepositoryServiceClient, id, name, desc string) {
-	res, err := client.UpdateRepository(ctx, &pb.UpdateRepositoryRequest{Id: id, Name: name, Description: desc})
+// - retention: The number of days the build artifacts are kept, or 0 to leave it unchanged.
+func UpdateRepository(ctx context.Context, client pb.RepositoryServiceClient, id, name, desc string, retention int32) {
+	res, err := client.UpdateRepository(ctx, &pb.UpdateRepositoryRequest{Id: id, Name: name, Description: desc, ArtifactRetentionDays: retention})
 	if err != nil {
 		log.Fatalf("failed to update repository: %v", err)
 	}
@@ -156,13 +179,14 @@ func UpdateRepository(ctx context.Context, client pb.RepositoryServiceClient, id
 // - name: The name of the repository to create.
 // - desc: The description of the repository to create.
 // - gitignore: The main language of the repository to create to generate the .gitignore file.
-func CreateRepository(ctx context.Context, client pb.RepositoryServiceClient, name, desc, gitignore string) {
+// - retention: The number of days the build artifacts are kept, or 0 for the server default.
+func CreateRepository(ctx context.Context, client pb.RepositoryServiceClient, name, desc, gitignore string, retention int32) {
 	if name == "" {
 		fmt.Println("Missing Name")
 		os.Exit(1)
 		return
 	}
-	res, err := client.CreateRepository(ctx, &pb.CreateRepositoryRequest{Name: name, Description: desc, Gitignore: gitignore})
+	res, err := client.CreateRepository(ctx, &pb.CreateRepositoryRequest{Name: name, Description: desc, Gitignore: gitignore, ArtifactRetentionDays: retention})
 	if err != nil {
 		log.Fatalf("failed to create repository: %v", err)
 	}
@@ -193,3 +217,41 @@ func DeleteRepository(ctx context.Context, client pb.RepositoryServiceClient, id
 	}
 	fmt.Printf("Deleted Repository with ID: %s\n", id)
 }
+
+// GrantRepositoryAccess grants a role on a repository to a user, replacing any
+// role previously granted to them on the repository.
+//
+// If there is an error during the request, the function logs the error and
+// terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The RepositoryServiceClient used to access the repository service.
+// - repo: The name of the repository.
+// - username: The username of the user.
+// - role: The name of the role to grant.
+func GrantRepositoryAccess(ctx context.Context, client pb.RepositoryServiceClient, repo, username, role string) {
+	_, err := client.GrantRepositoryAccess(ctx, &pb.GrantRepositoryAccessRequest{Repository: repo, Username: username, Role: parseRole(role)})
+	if err != nil {
+		log.Fatalf("failed to grant access: %v", err)
+	}
+	fmt.Printf("Granted %s on %s to %s\n", strings.ToLower(role), repo, username)
+}
+
+// RevokeRepositoryAccess revokes the role granted to a user on a repository.
+//
+// If there is an error during the request, the function logs the error and
+// terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The RepositoryServiceClient used to access the repository service.
+// - repo: The name of the repository.
+// - username: The username of the user.
+func RevokeRepositoryAccess(ctx context.Context, client pb.RepositoryServiceClient, repo, username string) {
+	_, err := client.RevokeRepositoryAccess(ctx, &pb.RevokeRepositoryAccessRequest{Repository: repo, Username: username})
+	if err != nil {
+		log.Fatalf("failed to revoke access: %v", err)
+	}
+	fmt.Printf("Revoked access to %s from %s\n", repo, username)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+)
+
+// handleSecretCommands parses command line arguments for the secret command and
+// makes the right call to the SecretServiceClient.
+// The commands available are:
+// - set: Sets a secret of a repository, reading its value from stdin unless given
+// - list: Lists the names of the secrets of a repository
+// - delete: Deletes a secret of a repository
+func handleSecretCommands(ctx context.Context, client pb.SecretServiceClient, command string, args []string) {
+	ctx = authenticateContext(ctx)
+	switch command {
+	case "--help":
+		printSecretHelp()
+	case "set":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci secret set --repo <repo> --name <name> [--value <value>]")
+		setCmd := flag.NewFlagSet("set", flag.ExitOnError)
+		setRepo := setCmd.String("repo", "", "Repository Name")
+		setName := setCmd.String("name", "", "Secret Name")
+		setValue := setCmd.String("value", "", "Secret Value (read from stdin if empty)")
+		setCmd.Parse(args)
+		SetSecret(ctx, client, *setRepo, *setName, *setValue)
+	case "list":
+		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci secret list --repo <repo>")
+		listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+		listRepo := listCmd.String("repo", "", "Repository Name")
+		listCmd.Parse(args)
+		ListSecretNames(ctx, client, *listRepo)
+	case "delete":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci secret delete --repo <repo> --name <name>")
+		deleteCmd := flag.NewFlagSet("delete", flag.ExitOnError)
+		deleteRepo := deleteCmd.String("repo", "", "Repository Name")
+		deleteName := deleteCmd.String("name", "", "Secret Name")
+		deleteCmd.Parse(args)
+		DeleteSecret(ctx, client, *deleteRepo, *deleteName)
+	default:
+		fmt.Println("Invalid secret command. Use: set, list, delete")
+		os.Exit(1)
+	}
+}
+
+func printSecretHelp() {
+	fmt.Println("Usage: ophelia-ci secret <command> [arguments]")
+	fmt.Println("Commands:")
+	fmt.Println("	set	Set a secret of a repository, reading its value from stdin unless given")
+	fmt.Println("	list	List the names of the secrets of a repository")
+	fmt.Println("	delete	Delete a secret of a repository")
+}
+
+// SetSecret sets a secret of a repository.
+//
+// If value is empty, it is read from stdin, without its trailing newline, so
+// that it does not end up in the shell history.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The SecretServiceClient used to access the secret service.
+// - repo: The name of the repository.
+// - name: The name of the secret, exposed to jobs as an environment variable.
+// - value: The value of the secret.
+func SetSecret(ctx context.Context, client pb.SecretServiceClient, repo, name, value string) {
+	if repo == "" || name == "" {
+		fmt.Println("Missing Repository or Name")
+		os.Exit(1)
+		return
+	}
+	if value == "" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("failed to read secret value: %v", err)
+		}
+		value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	}
+	_, err := client.SetSecret(ctx, &pb.SetSecretRequest{Repository: repo, Name: name, Value: value})
+	if err != nil {
+		log.Fatalf("failed to set secret: %v", err)
+	}
+	fmt.Printf("Secret %s of %s set\n", name, repo)
+}
+
+// ListSecretNames prints the names of the secrets of a repository.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The SecretServiceClient used to access the secret service.
+// - repo: The name of the repository.
+func ListSecretNames(ctx context.Context, client pb.SecretServiceClient, repo string) {
+	res, err := client.ListSecretNames(ctx, &pb.ListSecretNamesRequest{Repository: repo})
+	if err != nil {
+		log.Fatalf("failed to list secrets: %v", err)
+	}
+	fmt.Println("Secrets:")
+	for _, name := range res.Names {
+		fmt.Println(name)
+	}
+	fmt.Println("")
+}
+
+// DeleteSecret deletes a secret of a repository.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The SecretServiceClient used to access the secret service.
+// - repo: The name of the repository.
+// - name: The name of the secret to delete.
+func DeleteSecret(ctx context.Context, client pb.SecretServiceClient, repo, name string) {
+	_, err := client.DeleteSecret(ctx, &pb.DeleteSecretRequest{Repository: repo, Name: name})
+	if err != nil {
+		log.Fatalf("failed to delete secret: %v", err)
+	}
+	fmt.Printf("Deleted secret %s of %s\n", name, repo)
+}
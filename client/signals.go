@@ -4,36 +4,57 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"google.golang.org/grpc/metadata"
 )
 
 // handleSignals parses command line arguments for the signals command and makes the right call to the SignalsClient.
+// When run by a post-receive hook, the hook secret of the repository found in OPHELIA_CI_HOOK_SECRET is sent
+// instead of the user token.
 // The commands available are:
-// - commit: Sends a commit signal to the server with the given commit hash, branch, repository and tag.
+// - commit: Sends a commit signal to the server for a ref updated by a push, with its old and new commit hashes,
+// branch or tag, whether it was deleted and who pushed it.
 func handleSignals(ctx context.Context, client pb.SignalsClient, command string, args []string) {
-	ctx = authenticateContext(ctx)
+	if secret := os.Getenv("OPHELIA_CI_HOOK_SECRET"); secret != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+secret)
+	} else {
+		ctx = authenticateContext(ctx)
+	}
 	switch command {
 	case "commit":
-		ensureArgsLength(args, 8, "Too many arguments\nUsage: ophelia-ci repo list")
+		ensureArgsLength(args, 4, "Not enough arguments\nUsage: ophelia-ci signal commit --repo <repository> --hash <commit hash> [--old-hash <commit hash>] [--ref <ref>] [--branch <branch>] [--tag <tag>] [--deleted] [--pusher <username>]")
 		getCmd := flag.NewFlagSet("commit", flag.ExitOnError)
 		getCommitHash := getCmd.String("hash", "", "Commit Hash")
+		getOldCommitHash := getCmd.String("old-hash", "", "Commit hash the ref pointed to before the push")
+		getRef := getCmd.String("ref", "", "Full name of the updated ref")
 		getBranch := getCmd.String("branch", "", "Branch")
 		getRepositoryName := getCmd.String("repo", "", "Repository")
 		getTag := getCmd.String("tag", "", "Tag")
+		getDeleted := getCmd.Bool("deleted", false, "Whether the ref was deleted")
+		getPusher := getCmd.String("pusher", "", "User who pushed")
 		getCmd.Parse(args)
-		SendCommitSignal(ctx, client, *getRepositoryName, *getCommitHash, *getBranch, *getTag)
+		SendCommitSignal(ctx, client, &pb.CommitRequest{
+			Repository:  *getRepositoryName,
+			CommitHash:  *getCommitHash,
+			OldRevision: *getOldCommitHash,
+			Ref:         *getRef,
+			Branch:      *getBranch,
+			Tag:         *getTag,
+			Deleted:     *getDeleted,
+			Pusher:      *getPusher,
+		})
 	default:
 		log.Fatalf("Unknown command: %s", command)
 	}
 }
 
-// SendCommitSignal sends a commit signal to the server with the given commit hash, branch, repository and tag.
+// SendCommitSignal sends a commit signal to the server for a ref updated by a push.
 // If there is an error, it will log the error and exit.
-func SendCommitSignal(ctx context.Context, client pb.SignalsClient, repo, hash, branch, tag string) {
-	_, err := client.CommitSignal(ctx, &pb.CommitRequest{Repository: repo, CommitHash: hash, Branch: branch, Tag: tag})
+func SendCommitSignal(ctx context.Context, client pb.SignalsClient, req *pb.CommitRequest) {
+	_, err := client.CommitSignal(ctx, req)
 	if err != nil {
 		log.Fatalf("failed to send commit signal: %v", err)
 	}
-	
 }
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // handleUserCommands parses command line arguments for the user command and makes the right call to the UserServiceClient.
@@ -16,6 +19,10 @@ import (
 // - show: Retrieves a user by ID or username
 // - create: Creates a new user
 // - delete: Deletes a user by ID
+// - role: Sets the global role of a user
+// - add-key: Adds a public key to a user
+// - keys: Lists the public keys of a user
+// - remove-key: Removes a public key of a user by fingerprint
 func handleUserCommands(ctx context.Context, client pb.UserServiceClient, command string, args []string) {
 	ctx = authenticateContext(ctx)
 	switch command {
@@ -32,12 +39,13 @@ func handleUserCommands(ctx context.Context, client pb.UserServiceClient, comman
 		getCmd.Parse(args)
 		GetUser(ctx, client, *getID, *getUsername)
 	case "create":
-		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci user create --username <username> --public-key <public-key>")
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci user create --username <username> --public-key <public-key> [--role <role>]")
 		createCmd := flag.NewFlagSet("create", flag.ExitOnError)
 		createUsername := createCmd.String("username", "", "User Username")
 		createPublicKey := createCmd.String("public-key", "", "User Public Key")
+		createRole := createCmd.String("role", "none", "Global Role (none, reader, developer, maintainer or admin)")
 		createCmd.Parse(args)
-		CreateUser(ctx, client, *createUsername, *createPublicKey)
+		CreateUser(ctx, client, *createUsername, *createPublicKey, *createRole)
 	case "update":
 		ensureArgsLength(args, 6, "Wrong number of arguments\nUsage: ophelia-ci user update --id <id> --username <username> --public-key <public-key>")
 		updateCmd := flag.NewFlagSet("update", flag.ExitOnError)
@@ -48,6 +56,35 @@ func handleUserCommands(ctx context.Context, client pb.UserServiceClient, comman
 		UpdateUser(ctx, client, *updateID, *updateUsername, *updatePublicKey)
 	case "delete":
 		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci user delete --id <id>")
+	case "role":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci user role --username <username> --role <role>")
+		roleCmd := flag.NewFlagSet("role", flag.ExitOnError)
+		roleUsername := roleCmd.String("username", "", "User Username")
+		roleRole := roleCmd.String("role", "", "Global Role (none, reader, developer, maintainer or admin)")
+		roleCmd.Parse(args)
+		SetUserRole(ctx, client, *roleUsername, *roleRole)
+	case "add-key":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci user add-key --username <username> --public-key <public-key> [--name <name>] [--expires <YYYY-MM-DD>]")
+		addKeyCmd := flag.NewFlagSet("add-key", flag.ExitOnError)
+		addKeyUsername := addKeyCmd.String("username", "", "User Username")
+		addKeyPublicKey := addKeyCmd.String("public-key", "", "User Public Key")
+		addKeyName := addKeyCmd.String("name", "", "Key Name, defaults to the key comment")
+		addKeyExpires := addKeyCmd.String("expires", "", "Date the key expires on, as YYYY-MM-DD")
+		addKeyCmd.Parse(args)
+		AddUserKey(ctx, client, *addKeyUsername, *addKeyPublicKey, *addKeyName, *addKeyExpires)
+	case "keys":
+		ensureArgsLength(args, 2, "Wrong number of arguments\nUsage: ophelia-ci user keys --username <username>")
+		keysCmd := flag.NewFlagSet("keys", flag.ExitOnError)
+		keysUsername := keysCmd.String("username", "", "User Username")
+		keysCmd.Parse(args)
+		ListUserKeys(ctx, client, *keysUsername)
+	case "remove-key":
+		ensureArgsLength(args, 4, "Wrong number of arguments\nUsage: ophelia-ci user remove-key --username <username> --fingerprint <fingerprint>")
+		removeKeyCmd := flag.NewFlagSet("remove-key", flag.ExitOnError)
+		removeKeyUsername := removeKeyCmd.String("username", "", "User Username")
+		removeKeyFingerprint := removeKeyCmd.String("fingerprint", "", "SHA256 Fingerprint of the key")
+		removeKeyCmd.Parse(args)
+		RemoveUserKey(ctx, client, *removeKeyUsername, *removeKeyFingerprint)
 	default:
 		fmt.Println("Invalid user command")
 		os.Exit(1)
@@ -62,6 +99,10 @@ func printUserHelp() {
 	fmt.Println("	create	Create a new user")
 	fmt.Println("	update	Update a user by ID")
 	fmt.Println("	delete	Delete a user by ID")
+	fmt.Println("	role	Set the global role of a user")
+	fmt.Println("	add-key	Add a public key to a user")
+	fmt.Println("	keys	List the public keys of a user")
+	fmt.Println("	remove-key	Remove a public key of a user by fingerprint")
 }
 
 // ListUsers retrieves and prints a list of all users.
@@ -123,12 +164,13 @@ func GetUser(ctx context.Context, client pb.UserServiceClient, id, name string)
 // - client: The UserServiceClient used to access the user service.
 // - username: The username of the user to be created.
 // - publicKey: The path to the public key file of the user to be created.
-func CreateUser(ctx context.Context, client pb.UserServiceClient, username, publicKey string) {
+// - role: The name of the global role of the user to be created.
+func CreateUser(ctx context.Context, client pb.UserServiceClient, username, publicKey, role string) {
 	publicKeyString, err := readPublicKey(publicKey)
 	if err != nil {
 		log.Fatalf("Failed to read public key: %v", err)
 	}
-	res, err := client.CreateUser(ctx, &pb.CreateUserRequest{Username: username, PublicKey: publicKeyString})
+	res, err := client.CreateUser(ctx, &pb.CreateUserRequest{Username: username, PublicKey: publicKeyString, Role: parseRole(role)})
 	if err != nil {
 		log.Fatalf("Failed to create user: %v", err)
 	}
@@ -181,6 +223,115 @@ func DeleteUser(ctx context.Context, client pb.UserServiceClient, id string) {
 	fmt.Printf("User with ID: %s successfully deleted\n\n", id)
 }
 
+// SetUserRole sets the global role of a user, which applies to every
+// repository.
+//
+// If there is an error during the request, the function logs the error and terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The UserServiceClient used to access the user service.
+// - username: The username of the user.
+// - role: The name of the role, or none to remove the global role of the user.
+func SetUserRole(ctx context.Context, client pb.UserServiceClient, username, role string) {
+	_, err := client.SetUserRole(ctx, &pb.SetUserRoleRequest{Username: username, Role: parseRole(role)})
+	if err != nil {
+		log.Fatalf("Failed to set user role: %v", err)
+	}
+	fmt.Printf("Role of %s set to %s\n\n", username, strings.ToLower(role))
+}
+
+// AddUserKey adds a public key to a user, and prints its fingerprint.
+//
+// If there is an error during the request, the function logs the error and terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The UserServiceClient used to access the user service.
+// - username: The username of the user.
+// - publicKey: The path to the public key file.
+// - name: The name of the key, or an empty string to use the comment of the key.
+// - expires: The date the key expires on, as YYYY-MM-DD, or an empty string if it never expires.
+func AddUserKey(ctx context.Context, client pb.UserServiceClient, username, publicKey, name, expires string) {
+	publicKeyString, err := readPublicKey(publicKey)
+	if err != nil {
+		log.Fatalf("Failed to read public key: %v", err)
+	}
+	req := &pb.AddUserKeyRequest{Username: username, PublicKey: publicKeyString, Name: name}
+	if expires != "" {
+		expiresAt, err := time.ParseInLocation(time.DateOnly, expires, time.Local)
+		if err != nil {
+			log.Fatalf("Invalid expiry date %s, use YYYY-MM-DD: %v", expires, err)
+		}
+		req.ExpiresAt = timestamppb.New(expiresAt)
+	}
+	key, err := client.AddUserKey(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to add user key: %v", err)
+	}
+	fmt.Println("Key added:")
+	printUserKey(key)
+	fmt.Println("")
+}
+
+// ListUserKeys retrieves and prints the public keys of a user.
+//
+// If there is an error during the request, the function logs the error and terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The UserServiceClient used to access the user service.
+// - username: The username of the user.
+func ListUserKeys(ctx context.Context, client pb.UserServiceClient, username string) {
+	res, err := client.ListUserKeys(ctx, &pb.ListUserKeysRequest{Username: username})
+	if err != nil {
+		log.Fatalf("Failed to list user keys: %v", err)
+	}
+	fmt.Printf("Keys of %s:\n", username)
+	for _, key := range res.Keys {
+		printUserKey(key)
+	}
+	fmt.Println("")
+}
+
+// RemoveUserKey removes a public key of a user by its SHA256 fingerprint.
+//
+// If there is an error during the request, the function logs the error and terminates the program.
+//
+// Parameters:
+// - ctx: The context for the request, used for cancellation and deadlines.
+// - client: The UserServiceClient used to access the user service.
+// - username: The username of the user.
+// - fingerprint: The SHA256 fingerprint of the key, as printed by the keys command.
+func RemoveUserKey(ctx context.Context, client pb.UserServiceClient, username, fingerprint string) {
+	_, err := client.RemoveUserKey(ctx, &pb.RemoveUserKeyRequest{Username: username, Fingerprint: fingerprint})
+	if err != nil {
+		log.Fatalf("Failed to remove user key: %v", err)
+	}
+	fmt.Printf("Key %s of %s removed\n\n", fingerprint, username)
+}
+
+// printUserKey prints the description of a public key of a user.
+func printUserKey(key *pb.UserKey) {
+	expires := formatTimestamp(key.ExpiresAt)
+	if key.ExpiresAt == nil {
+		expires = "never"
+	}
+	fmt.Printf("Fingerprint: %s, Type: %s, Name: %s, Comment: %s, Added: %s, Expires: %s\n",
+		key.Fingerprint, key.Type, key.Name, key.Comment, formatTimestamp(key.CreatedAt), expires)
+}
+
+// parseRole parses the name of a role, case insensitively, exiting the program
+// if it is not the name of a role.
+func parseRole(name string) pb.Role {
+	role, ok := pb.Role_value[strings.ToUpper(name)]
+	if !ok {
+		fmt.Printf("Invalid role %s. Use: none, reader, developer, maintainer, admin\n", name)
+		os.Exit(1)
+	}
+	return pb.Role(role)
+}
+
 // readPublicKey reads the content of a public key file and returns it as a string.
 //
 // Parameters:
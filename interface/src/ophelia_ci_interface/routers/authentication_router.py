@@ -93,7 +93,7 @@ def unique_key_page(request: Request, template: Template):
 
 
 @router.post('/unique', response_class=RedirectResponse)
-def unique_key(
+async def unique_key(
     unique_key: Annotated[
         str,
         Form(
@@ -101,17 +101,38 @@ def unique_key(
             description='The unique key generated when the server is started.',
         ),
     ],
+    username: Annotated[
+        str,
+        Form(
+            title='Username',
+            description='The username of the administrator.',
+        ),
+    ],
+    public_key: Annotated[
+        UploadFile,
+        File(
+            title='Public Key',
+            description="The administrator's public key file.",
+        ),
+    ],
     authentication_service: Authentication,
 ):
     """
-    Authenticate a user with the server's unique key, then redirect to the
-    home page upon successful authentication.
+    Authenticate a user with the server's unique key, creating or restoring
+    the administrator with the given username and public key, then redirect
+    to the home page upon successful authentication.
 
     :param unique_key: The unique key generated when the server is started.
+    :param username: The username of the administrator.
+    :param public_key: The administrator's public key file.
     :return: A RedirectResponse object that sets a session cookie and redirects
         to the home page.
     """
-    token = authentication_service.authenticate_with_unique_key(unique_key)
+    token = authentication_service.authenticate_with_unique_key(
+        unique_key,
+        username=username,
+        public_key=(await public_key.read()).decode('utf-8'),
+    )
     response = RedirectResponse(url='/', status_code=status.HTTP_303_SEE_OTHER)
     response.set_cookie(key='session', value=token)
     return response
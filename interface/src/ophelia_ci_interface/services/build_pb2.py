@@ -0,0 +1,76 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# NO CHECKED-IN PROTOBUF GENCODE
+# source: build.proto
+# Protobuf Python Version: 5.29.0
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import runtime_version as _runtime_version
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+_runtime_version.ValidateProtobufRuntimeVersion(
+    _runtime_version.Domain.PUBLIC,
+    5,
+    29,
+    0,
+    '',
+    'build.proto'
+)
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0b\x62uild.proto\x12\x05\x62uild\x1a\x1fgoogle/protobuf/timestamp.proto\">\n\x11ListBuildsRequest\x12\x15\n\rrepository_id\x18\x01 \x01(\t\x12\x12\n\nrepository\x18\x02 \x01(\t\"\x1d\n\x0fGetBuildRequest\x12\n\n\x02id\x18\x01 \x01(\t\" \n\x12\x43\x61ncelBuildRequest\x12\n\n\x02id\x18\x01 \x01(\t\"\x1f\n\x11RetryBuildRequest\x12\n\n\x02id\x18\x01 \x01(\t\"\xb9\x01\n\x13TriggerBuildRequest\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x02 \x01(\t\x12\x0b\n\x03ref\x18\x03 \x01(\t\x12>\n\nparameters\x18\x04 \x03(\x0b\x32*.build.TriggerBuildRequest.ParametersEntry\x1a\x31\n\x0fParametersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xbf\x02\n\x0bJobResponse\x12\n\n\x02id\x18\x01 \x01(\t\x12\x10\n\x08\x62uild_id\x18\x02 \x01(\t\x12\r\n\x05stage\x18\x03 \x01(\t\x12\x0c\n\x04name\x18\x04 \x01(\t\x12\"\n\x06status\x18\x05 \x01(\x0e\x32\x12.build.BuildStatus\x12\x11\n\texit_code\x18\x06 \x01(\x05\x12.\n\nstarted_at\x18\x07 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12/\n\x0b\x66inished_at\x18\x08 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12.\n\x06matrix\x18\t \x03(\x0b\x32\x1e.build.JobResponse.MatrixEntry\x1a-\n\x0bMatrixEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\xbe\x03\n\rBuildResponse\x12\n\n\x02id\x18\x01 \x01(\t\x12\x15\n\rrepository_id\x18\x02 \x01(\t\x12\x13\n\x0b\x63ommit_hash\x18\x03 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x04 \x01(\t\x12\x0b\n\x03tag\x18\x05 \x01(\t\x12\x14\n\x0ctrigger_user\x18\x06 \x01(\t\x12\"\n\x06status\x18\x07 \x01(\x0e\x32\x12.build.BuildStatus\x12.\n\ncreated_at\x18\x08 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12.\n\nstarted_at\x18\t \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12/\n\x0b\x66inished_at\x18\n \x01(\x0b\x32\x1a.google.protobuf.Timestamp\x12 \n\x04jobs\x18\x0b \x03(\x0b\x32\x12.build.JobResponse\x12\x38\n\nparameters\x18\x0c \x03(\x0b\x32$.build.BuildResponse.ParametersEntry\x1a\x31\n\x0fParametersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\":\n\x12ListBuildsResponse\x12$\n\x06\x62uilds\x18\x01 \x03(\x0b\x32\x14.build.BuildResponse\"D\n\x16StreamBuildLogsRequest\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0e\n\x06offset\x18\x02 \x01(\x03\x12\x0e\n\x06\x66ollow\x18\x03 \x01(\x08\"9\n\x0c\x42uildLogLine\x12\x0e\n\x06offset\x18\x01 \x01(\x03\x12\x0b\n\x03job\x18\x02 \x01(\t\x12\x0c\n\x04text\x18\x03 \x01(\t\"8\n\x17\x44ownloadArtifactRequest\x12\x10\n\x08\x62uild_id\x18\x01 \x01(\t\x12\x0b\n\x03job\x18\x02 \x01(\t\"*\n\rArtifactChunk\x12\x0b\n\x03job\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c*j\n\x0b\x42uildStatus\x12\n\n\x06QUEUED\x10\x00\x12\x0b\n\x07RUNNING\x10\x01\x12\x0b\n\x07SUCCESS\x10\x02\x12\n\n\x06\x46\x41ILED\x10\x03\x12\r\n\tCANCELLED\x10\x04\x12\x0b\n\x07SKIPPED\x10\x05\x12\r\n\tTIMED_OUT\x10\x06\x32\xe0\x03\n\x0c\x42uildService\x12\x41\n\nListBuilds\x12\x18.build.ListBuildsRequest\x1a\x19.build.ListBuildsResponse\x12\x38\n\x08GetBuild\x12\x16.build.GetBuildRequest\x1a\x14.build.BuildResponse\x12>\n\x0b\x43\x61ncelBuild\x12\x19.build.CancelBuildRequest\x1a\x14.build.BuildResponse\x12<\n\nRetryBuild\x12\x18.build.RetryBuildRequest\x1a\x14.build.BuildResponse\x12G\n\x0fStreamBuildLogs\x12\x1d.build.StreamBuildLogsRequest\x1a\x13.build.BuildLogLine0\x01\x12@\n\x0cTriggerBuild\x12\x1a.build.TriggerBuildRequest\x1a\x14.build.BuildResponse\x12J\n\x10\x44ownloadArtifact\x12\x1e.build.DownloadArtifactRequest\x1a\x14.build.ArtifactChunk0\x01\x42)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'build_pb2', _globals)
+if not _descriptor._USE_C_DESCRIPTORS:
+  _globals['DESCRIPTOR']._loaded_options = None
+  _globals['DESCRIPTOR']._serialized_options = b'Z\'github.com/EdmilsonRodrigues/ophelia-ci'
+  _globals['_TRIGGERBUILDREQUEST_PARAMETERSENTRY']._loaded_options = None
+  _globals['_TRIGGERBUILDREQUEST_PARAMETERSENTRY']._serialized_options = b'8\001'
+  _globals['_JOBRESPONSE_MATRIXENTRY']._loaded_options = None
+  _globals['_JOBRESPONSE_MATRIXENTRY']._serialized_options = b'8\001'
+  _globals['_BUILDRESPONSE_PARAMETERSENTRY']._loaded_options = None
+  _globals['_BUILDRESPONSE_PARAMETERSENTRY']._serialized_options = b'8\001'
+  _globals['_BUILDSTATUS']._serialized_start=1467
+  _globals['_BUILDSTATUS']._serialized_end=1573
+  _globals['_LISTBUILDSREQUEST']._serialized_start=55
+  _globals['_LISTBUILDSREQUEST']._serialized_end=117
+  _globals['_GETBUILDREQUEST']._serialized_start=119
+  _globals['_GETBUILDREQUEST']._serialized_end=148
+  _globals['_CANCELBUILDREQUEST']._serialized_start=150
+  _globals['_CANCELBUILDREQUEST']._serialized_end=182
+  _globals['_RETRYBUILDREQUEST']._serialized_start=184
+  _globals['_RETRYBUILDREQUEST']._serialized_end=215
+  _globals['_TRIGGERBUILDREQUEST']._serialized_start=218
+  _globals['_TRIGGERBUILDREQUEST']._serialized_end=403
+  _globals['_TRIGGERBUILDREQUEST_PARAMETERSENTRY']._serialized_start=354
+  _globals['_TRIGGERBUILDREQUEST_PARAMETERSENTRY']._serialized_end=403
+  _globals['_JOBRESPONSE']._serialized_start=406
+  _globals['_JOBRESPONSE']._serialized_end=725
+  _globals['_JOBRESPONSE_MATRIXENTRY']._serialized_start=680
+  _globals['_JOBRESPONSE_MATRIXENTRY']._serialized_end=725
+  _globals['_BUILDRESPONSE']._serialized_start=728
+  _globals['_BUILDRESPONSE']._serialized_end=1174
+  _globals['_BUILDRESPONSE_PARAMETERSENTRY']._serialized_start=354
+  _globals['_BUILDRESPONSE_PARAMETERSENTRY']._serialized_end=403
+  _globals['_LISTBUILDSRESPONSE']._serialized_start=1176
+  _globals['_LISTBUILDSRESPONSE']._serialized_end=1234
+  _globals['_STREAMBUILDLOGSREQUEST']._serialized_start=1236
+  _globals['_STREAMBUILDLOGSREQUEST']._serialized_end=1304
+  _globals['_BUILDLOGLINE']._serialized_start=1306
+  _globals['_BUILDLOGLINE']._serialized_end=1363
+  _globals['_DOWNLOADARTIFACTREQUEST']._serialized_start=1365
+  _globals['_DOWNLOADARTIFACTREQUEST']._serialized_end=1421
+  _globals['_ARTIFACTCHUNK']._serialized_start=1423
+  _globals['_ARTIFACTCHUNK']._serialized_end=1465
+  _globals['_BUILDSERVICE']._serialized_start=1576
+  _globals['_BUILDSERVICE']._serialized_end=2056
+# @@protoc_insertion_point(module_scope)
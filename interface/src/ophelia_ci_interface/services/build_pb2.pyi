@@ -0,0 +1,373 @@
+"""
+@generated by mypy-protobuf.  Do not edit manually!
+isort:skip_file
+"""
+
+import builtins
+import collections.abc
+import google.protobuf.descriptor
+import google.protobuf.internal.containers
+import google.protobuf.internal.enum_type_wrapper
+import google.protobuf.message
+import google.protobuf.timestamp_pb2
+import sys
+import typing
+
+if sys.version_info >= (3, 10):
+    import typing as typing_extensions
+else:
+    import typing_extensions
+
+DESCRIPTOR: google.protobuf.descriptor.FileDescriptor
+
+class _BuildStatus:
+    ValueType = typing.NewType("ValueType", builtins.int)
+    V: typing_extensions.TypeAlias = ValueType
+
+class _BuildStatusEnumTypeWrapper(google.protobuf.internal.enum_type_wrapper._EnumTypeWrapper[_BuildStatus.ValueType], builtins.type):
+    DESCRIPTOR: google.protobuf.descriptor.EnumDescriptor
+    QUEUED: _BuildStatus.ValueType  # 0
+    RUNNING: _BuildStatus.ValueType  # 1
+    SUCCESS: _BuildStatus.ValueType  # 2
+    FAILED: _BuildStatus.ValueType  # 3
+    CANCELLED: _BuildStatus.ValueType  # 4
+    SKIPPED: _BuildStatus.ValueType  # 5
+    TIMED_OUT: _BuildStatus.ValueType  # 6
+
+class BuildStatus(_BuildStatus, metaclass=_BuildStatusEnumTypeWrapper): ...
+
+QUEUED: BuildStatus.ValueType  # 0
+RUNNING: BuildStatus.ValueType  # 1
+SUCCESS: BuildStatus.ValueType  # 2
+FAILED: BuildStatus.ValueType  # 3
+CANCELLED: BuildStatus.ValueType  # 4
+SKIPPED: BuildStatus.ValueType  # 5
+TIMED_OUT: BuildStatus.ValueType  # 6
+global___BuildStatus = BuildStatus
+
+@typing.final
+class ListBuildsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    REPOSITORY_ID_FIELD_NUMBER: builtins.int
+    REPOSITORY_FIELD_NUMBER: builtins.int
+    repository_id: builtins.str
+    repository: builtins.str
+    def __init__(
+        self,
+        *,
+        repository_id: builtins.str = ...,
+        repository: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["repository", b"repository", "repository_id", b"repository_id"]) -> None: ...
+
+global___ListBuildsRequest = ListBuildsRequest
+
+@typing.final
+class GetBuildRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["id", b"id"]) -> None: ...
+
+global___GetBuildRequest = GetBuildRequest
+
+@typing.final
+class CancelBuildRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["id", b"id"]) -> None: ...
+
+global___CancelBuildRequest = CancelBuildRequest
+
+@typing.final
+class RetryBuildRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["id", b"id"]) -> None: ...
+
+global___RetryBuildRequest = RetryBuildRequest
+
+@typing.final
+class TriggerBuildRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing.final
+    class ParametersEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        value: builtins.str
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: builtins.str = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing.Literal["key", b"key", "value", b"value"]) -> None: ...
+
+    REPOSITORY_FIELD_NUMBER: builtins.int
+    BRANCH_FIELD_NUMBER: builtins.int
+    REF_FIELD_NUMBER: builtins.int
+    PARAMETERS_FIELD_NUMBER: builtins.int
+    repository: builtins.str
+    branch: builtins.str
+    ref: builtins.str
+    @property
+    def parameters(self) -> google.protobuf.internal.containers.ScalarMap[builtins.str, builtins.str]: ...
+    def __init__(
+        self,
+        *,
+        repository: builtins.str = ...,
+        branch: builtins.str = ...,
+        ref: builtins.str = ...,
+        parameters: collections.abc.Mapping[builtins.str, builtins.str] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["branch", b"branch", "parameters", b"parameters", "ref", b"ref", "repository", b"repository"]) -> None: ...
+
+global___TriggerBuildRequest = TriggerBuildRequest
+
+@typing.final
+class JobResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing.final
+    class MatrixEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        value: builtins.str
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: builtins.str = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing.Literal["key", b"key", "value", b"value"]) -> None: ...
+
+    ID_FIELD_NUMBER: builtins.int
+    BUILD_ID_FIELD_NUMBER: builtins.int
+    STAGE_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    STATUS_FIELD_NUMBER: builtins.int
+    EXIT_CODE_FIELD_NUMBER: builtins.int
+    STARTED_AT_FIELD_NUMBER: builtins.int
+    FINISHED_AT_FIELD_NUMBER: builtins.int
+    MATRIX_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    build_id: builtins.str
+    stage: builtins.str
+    name: builtins.str
+    status: global___BuildStatus.ValueType
+    exit_code: builtins.int
+    @property
+    def started_at(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    @property
+    def finished_at(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    @property
+    def matrix(self) -> google.protobuf.internal.containers.ScalarMap[builtins.str, builtins.str]: ...
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+        build_id: builtins.str = ...,
+        stage: builtins.str = ...,
+        name: builtins.str = ...,
+        status: global___BuildStatus.ValueType = ...,
+        exit_code: builtins.int = ...,
+        started_at: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        finished_at: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        matrix: collections.abc.Mapping[builtins.str, builtins.str] | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["finished_at", b"finished_at", "started_at", b"started_at"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["build_id", b"build_id", "exit_code", b"exit_code", "finished_at", b"finished_at", "id", b"id", "matrix", b"matrix", "name", b"name", "stage", b"stage", "started_at", b"started_at", "status", b"status"]) -> None: ...
+
+global___JobResponse = JobResponse
+
+@typing.final
+class BuildResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    @typing.final
+    class ParametersEntry(google.protobuf.message.Message):
+        DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+        KEY_FIELD_NUMBER: builtins.int
+        VALUE_FIELD_NUMBER: builtins.int
+        key: builtins.str
+        value: builtins.str
+        def __init__(
+            self,
+            *,
+            key: builtins.str = ...,
+            value: builtins.str = ...,
+        ) -> None: ...
+        def ClearField(self, field_name: typing.Literal["key", b"key", "value", b"value"]) -> None: ...
+
+    ID_FIELD_NUMBER: builtins.int
+    REPOSITORY_ID_FIELD_NUMBER: builtins.int
+    COMMIT_HASH_FIELD_NUMBER: builtins.int
+    BRANCH_FIELD_NUMBER: builtins.int
+    TAG_FIELD_NUMBER: builtins.int
+    TRIGGER_USER_FIELD_NUMBER: builtins.int
+    STATUS_FIELD_NUMBER: builtins.int
+    CREATED_AT_FIELD_NUMBER: builtins.int
+    STARTED_AT_FIELD_NUMBER: builtins.int
+    FINISHED_AT_FIELD_NUMBER: builtins.int
+    JOBS_FIELD_NUMBER: builtins.int
+    PARAMETERS_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    repository_id: builtins.str
+    commit_hash: builtins.str
+    branch: builtins.str
+    tag: builtins.str
+    trigger_user: builtins.str
+    status: global___BuildStatus.ValueType
+    @property
+    def created_at(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    @property
+    def started_at(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    @property
+    def finished_at(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    @property
+    def jobs(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___JobResponse]: ...
+    @property
+    def parameters(self) -> google.protobuf.internal.containers.ScalarMap[builtins.str, builtins.str]: ...
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+        repository_id: builtins.str = ...,
+        commit_hash: builtins.str = ...,
+        branch: builtins.str = ...,
+        tag: builtins.str = ...,
+        trigger_user: builtins.str = ...,
+        status: global___BuildStatus.ValueType = ...,
+        created_at: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        started_at: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        finished_at: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        jobs: collections.abc.Iterable[global___JobResponse] | None = ...,
+        parameters: collections.abc.Mapping[builtins.str, builtins.str] | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["created_at", b"created_at", "finished_at", b"finished_at", "started_at", b"started_at"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["branch", b"branch", "commit_hash", b"commit_hash", "created_at", b"created_at", "finished_at", b"finished_at", "id", b"id", "jobs", b"jobs", "parameters", b"parameters", "repository_id", b"repository_id", "started_at", b"started_at", "status", b"status", "tag", b"tag", "trigger_user", b"trigger_user"]) -> None: ...
+
+global___BuildResponse = BuildResponse
+
+@typing.final
+class ListBuildsResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    BUILDS_FIELD_NUMBER: builtins.int
+    @property
+    def builds(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___BuildResponse]: ...
+    def __init__(
+        self,
+        *,
+        builds: collections.abc.Iterable[global___BuildResponse] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["builds", b"builds"]) -> None: ...
+
+global___ListBuildsResponse = ListBuildsResponse
+
+@typing.final
+class StreamBuildLogsRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    OFFSET_FIELD_NUMBER: builtins.int
+    FOLLOW_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    offset: builtins.int
+    follow: builtins.bool
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+        offset: builtins.int = ...,
+        follow: builtins.bool = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["follow", b"follow", "id", b"id", "offset", b"offset"]) -> None: ...
+
+global___StreamBuildLogsRequest = StreamBuildLogsRequest
+
+@typing.final
+class BuildLogLine(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    OFFSET_FIELD_NUMBER: builtins.int
+    JOB_FIELD_NUMBER: builtins.int
+    TEXT_FIELD_NUMBER: builtins.int
+    offset: builtins.int
+    job: builtins.str
+    text: builtins.str
+    def __init__(
+        self,
+        *,
+        offset: builtins.int = ...,
+        job: builtins.str = ...,
+        text: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["job", b"job", "offset", b"offset", "text", b"text"]) -> None: ...
+
+global___BuildLogLine = BuildLogLine
+
+@typing.final
+class DownloadArtifactRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    BUILD_ID_FIELD_NUMBER: builtins.int
+    JOB_FIELD_NUMBER: builtins.int
+    build_id: builtins.str
+    job: builtins.str
+    def __init__(
+        self,
+        *,
+        build_id: builtins.str = ...,
+        job: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["build_id", b"build_id", "job", b"job"]) -> None: ...
+
+global___DownloadArtifactRequest = DownloadArtifactRequest
+
+@typing.final
+class ArtifactChunk(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    JOB_FIELD_NUMBER: builtins.int
+    DATA_FIELD_NUMBER: builtins.int
+    job: builtins.str
+    data: builtins.bytes
+    def __init__(
+        self,
+        *,
+        job: builtins.str = ...,
+        data: builtins.bytes = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["data", b"data", "job", b"job"]) -> None: ...
+
+global___ArtifactChunk = ArtifactChunk
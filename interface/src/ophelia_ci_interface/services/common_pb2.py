@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0c\x63ommon.proto\x12\x06\x63ommon\"\x07\n\x05\x45mptyB)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0c\x63ommon.proto\x12\x06\x63ommon\"\x07\n\x05\x45mpty*F\n\x04Role\x12\x08\n\x04NONE\x10\x00\x12\n\n\x06READER\x10\x01\x12\r\n\tDEVELOPER\x10\x02\x12\x0e\n\nMAINTAINER\x10\x03\x12\t\n\x05\x41\x44MIN\x10\x04\x42)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -32,6 +32,8 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'common_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z\'github.com/EdmilsonRodrigues/ophelia-ci'
+  _globals['_ROLE']._serialized_start=33
+  _globals['_ROLE']._serialized_end=103
   _globals['_EMPTY']._serialized_start=24
   _globals['_EMPTY']._serialized_end=31
 # @@protoc_insertion_point(module_scope)
@@ -3,12 +3,44 @@
 isort:skip_file
 """
 
+import builtins
 import google.protobuf.descriptor
+import google.protobuf.internal.enum_type_wrapper
 import google.protobuf.message
+import sys
 import typing
 
+if sys.version_info >= (3, 10):
+    import typing as typing_extensions
+else:
+    import typing_extensions
+
 DESCRIPTOR: google.protobuf.descriptor.FileDescriptor
 
+class _Role:
+    ValueType = typing.NewType("ValueType", builtins.int)
+    V: typing_extensions.TypeAlias = ValueType
+
+class _RoleEnumTypeWrapper(google.protobuf.internal.enum_type_wrapper._EnumTypeWrapper[_Role.ValueType], builtins.type):
+    DESCRIPTOR: google.protobuf.descriptor.EnumDescriptor
+    NONE: _Role.ValueType  # 0
+    READER: _Role.ValueType  # 1
+    DEVELOPER: _Role.ValueType  # 2
+    MAINTAINER: _Role.ValueType  # 3
+    ADMIN: _Role.ValueType  # 4
+
+class Role(_Role, metaclass=_RoleEnumTypeWrapper):
+    """Role is a set of permissions granted to a user, either globally or on a
+    repository. Each role has the permissions of the roles before it.
+    """
+
+NONE: Role.ValueType  # 0
+READER: Role.ValueType  # 1
+DEVELOPER: Role.ValueType  # 2
+MAINTAINER: Role.ValueType  # 3
+ADMIN: Role.ValueType  # 4
+global___Role = Role
+
 @typing.final
 class Empty(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
@@ -288,12 +288,17 @@ class AuthenticationService(ServiceMixin):
         blob_len = int.from_bytes(sig_io.read(4), byteorder='big')
         return sig_io.read(blob_len)
 
-    def authenticate_with_unique_key(self, unique_key: str):
+    def authenticate_with_unique_key(
+        self, unique_key: str, username: str, public_key: str
+    ):
         """
         Authenticate a user with the unique key created by the
-        server on startup.
+        server on startup, creating or restoring the administrator
+        with the given username and public key.
 
         :param unique_key: the unique key created by the server on startup
+        :param username: the username of the administrator
+        :param public_key: the public key of the administrator
         :return: the token of the user if the authentication is successful
         :raises OpheliaException: if the authentication failed
         """
@@ -301,10 +306,15 @@ class AuthenticationService(ServiceMixin):
             log_formatted(
                 'Authenticating via Unique Key',
                 unique_key=unique_key,
+                username=username,
                 logging_level=logging.WARNING,
             )
             response_auth = self.stub.UniqueKeyLogin(
-                user_pb2.UniqueKeyLoginRequest(uniqueKey=unique_key)
+                user_pb2.UniqueKeyLoginRequest(
+                    uniqueKey=unique_key,
+                    username=username,
+                    publicKey=public_key,
+                )
             )
             if response_auth.authenticated:
                 return response_auth.token
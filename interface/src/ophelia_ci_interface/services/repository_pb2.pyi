@@ -5,6 +5,7 @@ isort:skip_file
 
 import builtins
 import collections.abc
+import common_pb2
 import google.protobuf.descriptor
 import google.protobuf.internal.containers
 import google.protobuf.message
@@ -38,17 +39,20 @@ class CreateRepositoryRequest(google.protobuf.message.Message):
     NAME_FIELD_NUMBER: builtins.int
     DESCRIPTION_FIELD_NUMBER: builtins.int
     GITIGNORE_FIELD_NUMBER: builtins.int
+    ARTIFACT_RETENTION_DAYS_FIELD_NUMBER: builtins.int
     name: builtins.str
     description: builtins.str
     gitignore: builtins.str
+    artifact_retention_days: builtins.int
     def __init__(
         self,
         *,
         name: builtins.str = ...,
         description: builtins.str = ...,
         gitignore: builtins.str = ...,
+        artifact_retention_days: builtins.int = ...,
     ) -> None: ...
-    def ClearField(self, field_name: typing.Literal["description", b"description", "gitignore", b"gitignore", "name", b"name"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["artifact_retention_days", b"artifact_retention_days", "description", b"description", "gitignore", b"gitignore", "name", b"name"]) -> None: ...
 
 global___CreateRepositoryRequest = CreateRepositoryRequest
 
@@ -59,17 +63,20 @@ class UpdateRepositoryRequest(google.protobuf.message.Message):
     ID_FIELD_NUMBER: builtins.int
     NAME_FIELD_NUMBER: builtins.int
     DESCRIPTION_FIELD_NUMBER: builtins.int
+    ARTIFACT_RETENTION_DAYS_FIELD_NUMBER: builtins.int
     id: builtins.str
     name: builtins.str
     description: builtins.str
+    artifact_retention_days: builtins.int
     def __init__(
         self,
         *,
         id: builtins.str = ...,
         name: builtins.str = ...,
         description: builtins.str = ...,
+        artifact_retention_days: builtins.int = ...,
     ) -> None: ...
-    def ClearField(self, field_name: typing.Literal["description", b"description", "id", b"id", "name", b"name"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["artifact_retention_days", b"artifact_retention_days", "description", b"description", "id", b"id", "name", b"name"]) -> None: ...
 
 global___UpdateRepositoryRequest = UpdateRepositoryRequest
 
@@ -96,9 +103,11 @@ class RepositoryResponse(google.protobuf.message.Message):
     NAME_FIELD_NUMBER: builtins.int
     DESCRIPTION_FIELD_NUMBER: builtins.int
     LAST_UPDATE_FIELD_NUMBER: builtins.int
+    ARTIFACT_RETENTION_DAYS_FIELD_NUMBER: builtins.int
     id: builtins.str
     name: builtins.str
     description: builtins.str
+    artifact_retention_days: builtins.int
     @property
     def last_update(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
     def __init__(
@@ -108,9 +117,10 @@ class RepositoryResponse(google.protobuf.message.Message):
         name: builtins.str = ...,
         description: builtins.str = ...,
         last_update: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        artifact_retention_days: builtins.int = ...,
     ) -> None: ...
     def HasField(self, field_name: typing.Literal["last_update", b"last_update"]) -> builtins.bool: ...
-    def ClearField(self, field_name: typing.Literal["description", b"description", "id", b"id", "last_update", b"last_update", "name", b"name"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["artifact_retention_days", b"artifact_retention_days", "description", b"description", "id", b"id", "last_update", b"last_update", "name", b"name"]) -> None: ...
 
 global___RepositoryResponse = RepositoryResponse
 
@@ -129,3 +139,42 @@ class ListRepositoryResponse(google.protobuf.message.Message):
     def ClearField(self, field_name: typing.Literal["repositories", b"repositories"]) -> None: ...
 
 global___ListRepositoryResponse = ListRepositoryResponse
+
+@typing.final
+class GrantRepositoryAccessRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    REPOSITORY_FIELD_NUMBER: builtins.int
+    USERNAME_FIELD_NUMBER: builtins.int
+    ROLE_FIELD_NUMBER: builtins.int
+    repository: builtins.str
+    username: builtins.str
+    role: common_pb2.Role.ValueType
+    def __init__(
+        self,
+        *,
+        repository: builtins.str = ...,
+        username: builtins.str = ...,
+        role: common_pb2.Role.ValueType = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["repository", b"repository", "role", b"role", "username", b"username"]) -> None: ...
+
+global___GrantRepositoryAccessRequest = GrantRepositoryAccessRequest
+
+@typing.final
+class RevokeRepositoryAccessRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    REPOSITORY_FIELD_NUMBER: builtins.int
+    USERNAME_FIELD_NUMBER: builtins.int
+    repository: builtins.str
+    username: builtins.str
+    def __init__(
+        self,
+        *,
+        repository: builtins.str = ...,
+        username: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["repository", b"repository", "username", b"username"]) -> None: ...
+
+global___RevokeRepositoryAccessRequest = RevokeRepositoryAccessRequest
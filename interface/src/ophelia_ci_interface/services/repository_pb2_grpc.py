@@ -60,6 +60,16 @@ class RepositoryServiceStub(object):
                 request_serializer=repository__pb2.DeleteRepositoryRequest.SerializeToString,
                 response_deserializer=common__pb2.Empty.FromString,
                 _registered_method=True)
+        self.GrantRepositoryAccess = channel.unary_unary(
+                '/repository.RepositoryService/GrantRepositoryAccess',
+                request_serializer=repository__pb2.GrantRepositoryAccessRequest.SerializeToString,
+                response_deserializer=common__pb2.Empty.FromString,
+                _registered_method=True)
+        self.RevokeRepositoryAccess = channel.unary_unary(
+                '/repository.RepositoryService/RevokeRepositoryAccess',
+                request_serializer=repository__pb2.RevokeRepositoryAccessRequest.SerializeToString,
+                response_deserializer=common__pb2.Empty.FromString,
+                _registered_method=True)
 
 
 class RepositoryServiceServicer(object):
@@ -95,6 +105,18 @@ class RepositoryServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GrantRepositoryAccess(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RevokeRepositoryAccess(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_RepositoryServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -123,6 +145,16 @@ def add_RepositoryServiceServicer_to_server(servicer, server):
                     request_deserializer=repository__pb2.DeleteRepositoryRequest.FromString,
                     response_serializer=common__pb2.Empty.SerializeToString,
             ),
+            'GrantRepositoryAccess': grpc.unary_unary_rpc_method_handler(
+                    servicer.GrantRepositoryAccess,
+                    request_deserializer=repository__pb2.GrantRepositoryAccessRequest.FromString,
+                    response_serializer=common__pb2.Empty.SerializeToString,
+            ),
+            'RevokeRepositoryAccess': grpc.unary_unary_rpc_method_handler(
+                    servicer.RevokeRepositoryAccess,
+                    request_deserializer=repository__pb2.RevokeRepositoryAccessRequest.FromString,
+                    response_serializer=common__pb2.Empty.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'repository.RepositoryService', rpc_method_handlers)
@@ -268,3 +300,57 @@ class RepositoryService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def GrantRepositoryAccess(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/repository.RepositoryService/GrantRepositoryAccess',
+            repository__pb2.GrantRepositoryAccessRequest.SerializeToString,
+            common__pb2.Empty.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def RevokeRepositoryAccess(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/repository.RepositoryService/RevokeRepositoryAccess',
+            repository__pb2.RevokeRepositoryAccessRequest.SerializeToString,
+            common__pb2.Empty.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
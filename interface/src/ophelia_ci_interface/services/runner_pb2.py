@@ -0,0 +1,90 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# NO CHECKED-IN PROTOBUF GENCODE
+# source: runner.proto
+# Protobuf Python Version: 5.29.0
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import runtime_version as _runtime_version
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+_runtime_version.ValidateProtobufRuntimeVersion(
+    _runtime_version.Domain.PUBLIC,
+    5,
+    29,
+    0,
+    '',
+    'runner.proto'
+)
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+import ophelia_ci_interface.services.common_pb2 as common__pb2
+from google.protobuf import duration_pb2 as google_dot_protobuf_dot_duration__pb2
+from google.protobuf import timestamp_pb2 as google_dot_protobuf_dot_timestamp__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0crunner.proto\x12\x06runner\x1a\x0c\x63ommon.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"i\n\x0eRunnerResponse\x12\n\n\x02id\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0e\n\x06labels\x18\x03 \x03(\t\x12-\n\tlast_seen\x18\x04 \x01(\x0b\x32\x1a.google.protobuf.Timestamp\"Q\n\x15RegisterRunnerRequest\x12\x1a\n\x12registration_token\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\x0e\n\x06labels\x18\x03 \x03(\t\"3\n\x16RegisterRunnerResponse\x12\n\n\x02id\x18\x01 \x01(\t\x12\r\n\x05token\x18\x02 \x01(\t\"\'\n\x0fLeaseJobRequest\x12\x14\n\x0cwait_seconds\x18\x01 \x01(\x03\"\xc4\x01\n\x0eStepDefinition\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0b\n\x03run\x18\x02 \x01(\t\x12,\n\x03\x65nv\x18\x03 \x03(\x0b\x32\x1f.runner.StepDefinition.EnvEntry\x12\x11\n\tartifacts\x18\x04 \x03(\t\x12*\n\x07timeout\x18\x05 \x01(\x0b\x32\x19.google.protobuf.Duration\x1a*\n\x08\x45nvEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"-\n\x0f\x43\x61\x63heDefinition\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05paths\x18\x02 \x03(\t\"\xf1\x01\n\rJobDefinition\x12\x0c\n\x04name\x18\x01 \x01(\t\x12+\n\x03\x65nv\x18\x02 \x03(\x0b\x32\x1e.runner.JobDefinition.EnvEntry\x12%\n\x05steps\x18\x03 \x03(\x0b\x32\x16.runner.StepDefinition\x12&\n\x05\x63\x61\x63he\x18\x04 \x01(\x0b\x32\x17.runner.CacheDefinition\x12*\n\x07timeout\x18\x05 \x01(\x0b\x32\x19.google.protobuf.Duration\x1a*\n\x08\x45nvEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"\x81\x04\n\x10LeaseJobResponse\x12\x10\n\x08lease_id\x18\x01 \x01(\t\x12\x10\n\x08\x62uild_id\x18\x02 \x01(\t\x12\x0e\n\x06job_id\x18\x03 \x01(\t\x12\x12\n\nrepository\x18\x04 \x01(\t\x12\x13\n\x0b\x63ommit_hash\x18\x05 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x06 \x01(\t\x12\x0b\n\x03tag\x18\x07 \x01(\t\x12.\n\x03\x65nv\x18\x08 \x03(\x0b\x32!.runner.LeaseJobResponse.EnvEntry\x12\"\n\x03job\x18\t \x01(\x0b\x32\x15.runner.JobDefinition\x12\x1a\n\x12heartbeat_interval\x18\n \x01(\x03\x12<\n\nparameters\x18\x0b \x03(\x0b\x32(.runner.LeaseJobResponse.ParametersEntry\x12\x36\n\x07secrets\x18\x0c \x03(\x0b\x32%.runner.LeaseJobResponse.SecretsEntry\x1a*\n\x08\x45nvEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a\x31\n\x0fParametersEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\x1a.\n\x0cSecretsEntry\x12\x0b\n\x03key\x18\x01 \x01(\t\x12\r\n\x05value\x18\x02 \x01(\t:\x02\x38\x01\"$\n\x10HeartbeatRequest\x12\x10\n\x08lease_id\x18\x01 \x01(\t\"&\n\x11HeartbeatResponse\x12\x11\n\tcancelled\x18\x01 \x01(\x08\")\n\x15\x44ownloadSourceRequest\x12\x10\n\x08lease_id\x18\x01 \x01(\t\"\x1b\n\x0bSourceChunk\x12\x0c\n\x04\x64\x61ta\x18\x01 \x01(\x0c\"7\n\x15UploadLogChunkRequest\x12\x10\n\x08lease_id\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c\"7\n\x15UploadArtifactRequest\x12\x10\n\x08lease_id\x18\x01 \x01(\t\x12\x0c\n\x04\x64\x61ta\x18\x02 \x01(\x0c\"]\n\x12\x43ompleteJobRequest\x12\x10\n\x08lease_id\x18\x01 \x01(\t\x12\x0f\n\x07success\x18\x02 \x01(\x08\x12\x11\n\texit_code\x18\x03 \x01(\x05\x12\x11\n\ttimed_out\x18\x04 \x01(\x08\x32\xe5\x03\n\rRunnerService\x12O\n\x0eRegisterRunner\x12\x1d.runner.RegisterRunnerRequest\x1a\x1e.runner.RegisterRunnerResponse\x12=\n\x08LeaseJob\x12\x17.runner.LeaseJobRequest\x1a\x18.runner.LeaseJobResponse\x12@\n\tHeartbeat\x12\x18.runner.HeartbeatRequest\x1a\x19.runner.HeartbeatResponse\x12\x46\n\x0e\x44ownloadSource\x12\x1d.runner.DownloadSourceRequest\x1a\x13.runner.SourceChunk0\x01\x12>\n\x0eUploadLogChunk\x12\x1d.runner.UploadLogChunkRequest\x1a\r.common.Empty\x12@\n\x0eUploadArtifact\x12\x1d.runner.UploadArtifactRequest\x1a\r.common.Empty(\x01\x12\x38\n\x0b\x43ompleteJob\x12\x1a.runner.CompleteJobRequest\x1a\r.common.EmptyB)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'runner_pb2', _globals)
+if not _descriptor._USE_C_DESCRIPTORS:
+  _globals['DESCRIPTOR']._loaded_options = None
+  _globals['DESCRIPTOR']._serialized_options = b'Z\'github.com/EdmilsonRodrigues/ophelia-ci'
+  _globals['_STEPDEFINITION_ENVENTRY']._loaded_options = None
+  _globals['_STEPDEFINITION_ENVENTRY']._serialized_options = b'8\001'
+  _globals['_JOBDEFINITION_ENVENTRY']._loaded_options = None
+  _globals['_JOBDEFINITION_ENVENTRY']._serialized_options = b'8\001'
+  _globals['_LEASEJOBRESPONSE_ENVENTRY']._loaded_options = None
+  _globals['_LEASEJOBRESPONSE_ENVENTRY']._serialized_options = b'8\001'
+  _globals['_LEASEJOBRESPONSE_PARAMETERSENTRY']._loaded_options = None
+  _globals['_LEASEJOBRESPONSE_PARAMETERSENTRY']._serialized_options = b'8\001'
+  _globals['_LEASEJOBRESPONSE_SECRETSENTRY']._loaded_options = None
+  _globals['_LEASEJOBRESPONSE_SECRETSENTRY']._serialized_options = b'8\001'
+  _globals['_RUNNERRESPONSE']._serialized_start=103
+  _globals['_RUNNERRESPONSE']._serialized_end=208
+  _globals['_REGISTERRUNNERREQUEST']._serialized_start=210
+  _globals['_REGISTERRUNNERREQUEST']._serialized_end=291
+  _globals['_REGISTERRUNNERRESPONSE']._serialized_start=293
+  _globals['_REGISTERRUNNERRESPONSE']._serialized_end=344
+  _globals['_LEASEJOBREQUEST']._serialized_start=346
+  _globals['_LEASEJOBREQUEST']._serialized_end=385
+  _globals['_STEPDEFINITION']._serialized_start=388
+  _globals['_STEPDEFINITION']._serialized_end=584
+  _globals['_STEPDEFINITION_ENVENTRY']._serialized_start=542
+  _globals['_STEPDEFINITION_ENVENTRY']._serialized_end=584
+  _globals['_CACHEDEFINITION']._serialized_start=586
+  _globals['_CACHEDEFINITION']._serialized_end=631
+  _globals['_JOBDEFINITION']._serialized_start=634
+  _globals['_JOBDEFINITION']._serialized_end=875
+  _globals['_JOBDEFINITION_ENVENTRY']._serialized_start=542
+  _globals['_JOBDEFINITION_ENVENTRY']._serialized_end=584
+  _globals['_LEASEJOBRESPONSE']._serialized_start=878
+  _globals['_LEASEJOBRESPONSE']._serialized_end=1391
+  _globals['_LEASEJOBRESPONSE_ENVENTRY']._serialized_start=542
+  _globals['_LEASEJOBRESPONSE_ENVENTRY']._serialized_end=584
+  _globals['_LEASEJOBRESPONSE_PARAMETERSENTRY']._serialized_start=1294
+  _globals['_LEASEJOBRESPONSE_PARAMETERSENTRY']._serialized_end=1343
+  _globals['_LEASEJOBRESPONSE_SECRETSENTRY']._serialized_start=1345
+  _globals['_LEASEJOBRESPONSE_SECRETSENTRY']._serialized_end=1391
+  _globals['_HEARTBEATREQUEST']._serialized_start=1393
+  _globals['_HEARTBEATREQUEST']._serialized_end=1429
+  _globals['_HEARTBEATRESPONSE']._serialized_start=1431
+  _globals['_HEARTBEATRESPONSE']._serialized_end=1469
+  _globals['_DOWNLOADSOURCEREQUEST']._serialized_start=1471
+  _globals['_DOWNLOADSOURCEREQUEST']._serialized_end=1512
+  _globals['_SOURCECHUNK']._serialized_start=1514
+  _globals['_SOURCECHUNK']._serialized_end=1541
+  _globals['_UPLOADLOGCHUNKREQUEST']._serialized_start=1543
+  _globals['_UPLOADLOGCHUNKREQUEST']._serialized_end=1598
+  _globals['_UPLOADARTIFACTREQUEST']._serialized_start=1600
+  _globals['_UPLOADARTIFACTREQUEST']._serialized_end=1655
+  _globals['_COMPLETEJOBREQUEST']._serialized_start=1657
+  _globals['_COMPLETEJOBREQUEST']._serialized_end=1750
+  _globals['_RUNNERSERVICE']._serialized_start=1753
+  _globals['_RUNNERSERVICE']._serialized_end=2238
+# @@protoc_insertion_point(module_scope)
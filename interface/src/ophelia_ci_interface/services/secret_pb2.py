@@ -0,0 +1,46 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# NO CHECKED-IN PROTOBUF GENCODE
+# source: secret.proto
+# Protobuf Python Version: 5.29.0
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import runtime_version as _runtime_version
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+_runtime_version.ValidateProtobufRuntimeVersion(
+    _runtime_version.Domain.PUBLIC,
+    5,
+    29,
+    0,
+    '',
+    'secret.proto'
+)
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+import ophelia_ci_interface.services.common_pb2 as common__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0csecret.proto\x12\x06secret\x1a\x0c\x63ommon.proto\"C\n\x10SetSecretRequest\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t\x12\r\n\x05value\x18\x03 \x01(\t\",\n\x16ListSecretNamesRequest\x12\x12\n\nrepository\x18\x01 \x01(\t\"(\n\x17ListSecretNamesResponse\x12\r\n\x05names\x18\x01 \x03(\t\"7\n\x13\x44\x65leteSecretRequest\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0c\n\x04name\x18\x02 \x01(\t2\xd5\x01\n\rSecretService\x12\x34\n\tSetSecret\x12\x18.secret.SetSecretRequest\x1a\r.common.Empty\x12R\n\x0fListSecretNames\x12\x1e.secret.ListSecretNamesRequest\x1a\x1f.secret.ListSecretNamesResponse\x12:\n\x0c\x44\x65leteSecret\x12\x1b.secret.DeleteSecretRequest\x1a\r.common.EmptyB)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'secret_pb2', _globals)
+if not _descriptor._USE_C_DESCRIPTORS:
+  _globals['DESCRIPTOR']._loaded_options = None
+  _globals['DESCRIPTOR']._serialized_options = b'Z\'github.com/EdmilsonRodrigues/ophelia-ci'
+  _globals['_SETSECRETREQUEST']._serialized_start=38
+  _globals['_SETSECRETREQUEST']._serialized_end=105
+  _globals['_LISTSECRETNAMESREQUEST']._serialized_start=107
+  _globals['_LISTSECRETNAMESREQUEST']._serialized_end=151
+  _globals['_LISTSECRETNAMESRESPONSE']._serialized_start=153
+  _globals['_LISTSECRETNAMESRESPONSE']._serialized_end=193
+  _globals['_DELETESECRETREQUEST']._serialized_start=195
+  _globals['_DELETESECRETREQUEST']._serialized_end=250
+  _globals['_SECRETSERVICE']._serialized_start=253
+  _globals['_SECRETSERVICE']._serialized_end=466
+# @@protoc_insertion_point(module_scope)
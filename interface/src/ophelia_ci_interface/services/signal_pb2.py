@@ -25,7 +25,7 @@ _sym_db = _symbol_database.Default()
 import ophelia_ci_interface.services.common_pb2 as common__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0csignal.proto\x12\x06signal\x1a\x0c\x63ommon.proto\"U\n\rCommitRequest\x12\x13\n\x0b\x63ommit_hash\x18\x01 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x02 \x01(\t\x12\x12\n\nrepository\x18\x03 \x01(\t\x12\x0b\n\x03tag\x18\x04 \x01(\t2?\n\x07Signals\x12\x34\n\x0c\x43ommitSignal\x12\x15.signal.CommitRequest\x1a\r.common.EmptyB)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x0csignal.proto\x12\x06signal\x1a\x0c\x63ommon.proto\"\x99\x01\n\rCommitRequest\x12\x13\n\x0b\x63ommit_hash\x18\x01 \x01(\t\x12\x0e\n\x06\x62ranch\x18\x02 \x01(\t\x12\x12\n\nrepository\x18\x03 \x01(\t\x12\x0b\n\x03tag\x18\x04 \x01(\t\x12\x14\n\x0cold_revision\x18\x05 \x01(\t\x12\x0b\n\x03ref\x18\x06 \x01(\t\x12\x0f\n\x07\x64\x65leted\x18\x07 \x01(\x08\x12\x0e\n\x06pusher\x18\x08 \x01(\t2?\n\x07Signals\x12\x34\n\x0c\x43ommitSignal\x12\x15.signal.CommitRequest\x1a\r.common.EmptyB)Z\'github.com/EdmilsonRodrigues/ophelia-cib\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -33,8 +33,8 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'signal_pb2', _globals)
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'Z\'github.com/EdmilsonRodrigues/ophelia-ci'
-  _globals['_COMMITREQUEST']._serialized_start=38
-  _globals['_COMMITREQUEST']._serialized_end=123
-  _globals['_SIGNALS']._serialized_start=125
-  _globals['_SIGNALS']._serialized_end=188
+  _globals['_COMMITREQUEST']._serialized_start=39
+  _globals['_COMMITREQUEST']._serialized_end=192
+  _globals['_SIGNALS']._serialized_start=194
+  _globals['_SIGNALS']._serialized_end=257
 # @@protoc_insertion_point(module_scope)
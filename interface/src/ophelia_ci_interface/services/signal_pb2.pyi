@@ -18,10 +18,18 @@ class CommitRequest(google.protobuf.message.Message):
     BRANCH_FIELD_NUMBER: builtins.int
     REPOSITORY_FIELD_NUMBER: builtins.int
     TAG_FIELD_NUMBER: builtins.int
+    OLD_REVISION_FIELD_NUMBER: builtins.int
+    REF_FIELD_NUMBER: builtins.int
+    DELETED_FIELD_NUMBER: builtins.int
+    PUSHER_FIELD_NUMBER: builtins.int
     commit_hash: builtins.str
     branch: builtins.str
     repository: builtins.str
     tag: builtins.str
+    old_revision: builtins.str
+    ref: builtins.str
+    deleted: builtins.bool
+    pusher: builtins.str
     def __init__(
         self,
         *,
@@ -29,7 +37,11 @@ class CommitRequest(google.protobuf.message.Message):
         branch: builtins.str = ...,
         repository: builtins.str = ...,
         tag: builtins.str = ...,
+        old_revision: builtins.str = ...,
+        ref: builtins.str = ...,
+        deleted: builtins.bool = ...,
+        pusher: builtins.str = ...,
     ) -> None: ...
-    def ClearField(self, field_name: typing.Literal["branch", b"branch", "commit_hash", b"commit_hash", "repository", b"repository", "tag", b"tag"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["branch", b"branch", "commit_hash", b"commit_hash", "deleted", b"deleted", "old_revision", b"old_revision", "pusher", b"pusher", "ref", b"ref", "repository", b"repository", "tag", b"tag"]) -> None: ...
 
 global___CommitRequest = CommitRequest
@@ -5,9 +5,11 @@ isort:skip_file
 
 import builtins
 import collections.abc
+import common_pb2
 import google.protobuf.descriptor
 import google.protobuf.internal.containers
 import google.protobuf.message
+import google.protobuf.timestamp_pb2
 import typing
 
 DESCRIPTOR: google.protobuf.descriptor.FileDescriptor
@@ -83,13 +85,20 @@ class UniqueKeyLoginRequest(google.protobuf.message.Message):
     DESCRIPTOR: google.protobuf.descriptor.Descriptor
 
     UNIQUEKEY_FIELD_NUMBER: builtins.int
+    USERNAME_FIELD_NUMBER: builtins.int
+    PUBLICKEY_FIELD_NUMBER: builtins.int
     uniqueKey: builtins.str
+    username: builtins.str
+    """The administrator created, or restored, by the first login."""
+    publicKey: builtins.str
     def __init__(
         self,
         *,
         uniqueKey: builtins.str = ...,
+        username: builtins.str = ...,
+        publicKey: builtins.str = ...,
     ) -> None: ...
-    def ClearField(self, field_name: typing.Literal["uniqueKey", b"uniqueKey"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["publicKey", b"publicKey", "uniqueKey", b"uniqueKey", "username", b"username"]) -> None: ...
 
 global___UniqueKeyLoginRequest = UniqueKeyLoginRequest
 
@@ -117,15 +126,18 @@ class CreateUserRequest(google.protobuf.message.Message):
 
     USERNAME_FIELD_NUMBER: builtins.int
     PUBLICKEY_FIELD_NUMBER: builtins.int
+    ROLE_FIELD_NUMBER: builtins.int
     username: builtins.str
     publicKey: builtins.str
+    role: common_pb2.Role.ValueType
     def __init__(
         self,
         *,
         username: builtins.str = ...,
         publicKey: builtins.str = ...,
+        role: common_pb2.Role.ValueType = ...,
     ) -> None: ...
-    def ClearField(self, field_name: typing.Literal["publicKey", b"publicKey", "username", b"username"]) -> None: ...
+    def ClearField(self, field_name: typing.Literal["publicKey", b"publicKey", "role", b"role", "username", b"username"]) -> None: ...
 
 global___CreateUserRequest = CreateUserRequest
 
@@ -198,3 +210,137 @@ class DeleteUserRequest(google.protobuf.message.Message):
     def ClearField(self, field_name: typing.Literal["id", b"id"]) -> None: ...
 
 global___DeleteUserRequest = DeleteUserRequest
+
+@typing.final
+class SetUserRoleRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    USERNAME_FIELD_NUMBER: builtins.int
+    ROLE_FIELD_NUMBER: builtins.int
+    username: builtins.str
+    role: common_pb2.Role.ValueType
+    def __init__(
+        self,
+        *,
+        username: builtins.str = ...,
+        role: common_pb2.Role.ValueType = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["role", b"role", "username", b"username"]) -> None: ...
+
+global___SetUserRoleRequest = SetUserRoleRequest
+
+@typing.final
+class AddUserKeyRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    USERNAME_FIELD_NUMBER: builtins.int
+    PUBLICKEY_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    EXPIRESAT_FIELD_NUMBER: builtins.int
+    username: builtins.str
+    publicKey: builtins.str
+    name: builtins.str
+    """The name of the key, such as the machine it is used on. It defaults to
+    the comment of the key.
+    """
+    @property
+    def expiresAt(self) -> google.protobuf.timestamp_pb2.Timestamp:
+        """The key is rejected after it expires. It never expires if not set."""
+    def __init__(
+        self,
+        *,
+        username: builtins.str = ...,
+        publicKey: builtins.str = ...,
+        name: builtins.str = ...,
+        expiresAt: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["expiresAt", b"expiresAt"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["expiresAt", b"expiresAt", "name", b"name", "publicKey", b"publicKey", "username", b"username"]) -> None: ...
+
+global___AddUserKeyRequest = AddUserKeyRequest
+
+@typing.final
+class UserKey(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    USERNAME_FIELD_NUMBER: builtins.int
+    FINGERPRINT_FIELD_NUMBER: builtins.int
+    TYPE_FIELD_NUMBER: builtins.int
+    COMMENT_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    CREATEDAT_FIELD_NUMBER: builtins.int
+    EXPIRESAT_FIELD_NUMBER: builtins.int
+    username: builtins.str
+    fingerprint: builtins.str
+    """The SHA256 fingerprint of the key, as printed by `ssh-keygen -l`."""
+    type: builtins.str
+    comment: builtins.str
+    name: builtins.str
+    @property
+    def createdAt(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    @property
+    def expiresAt(self) -> google.protobuf.timestamp_pb2.Timestamp: ...
+    def __init__(
+        self,
+        *,
+        username: builtins.str = ...,
+        fingerprint: builtins.str = ...,
+        type: builtins.str = ...,
+        comment: builtins.str = ...,
+        name: builtins.str = ...,
+        createdAt: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+        expiresAt: google.protobuf.timestamp_pb2.Timestamp | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing.Literal["createdAt", b"createdAt", "expiresAt", b"expiresAt"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing.Literal["comment", b"comment", "createdAt", b"createdAt", "expiresAt", b"expiresAt", "fingerprint", b"fingerprint", "name", b"name", "type", b"type", "username", b"username"]) -> None: ...
+
+global___UserKey = UserKey
+
+@typing.final
+class ListUserKeysRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    USERNAME_FIELD_NUMBER: builtins.int
+    username: builtins.str
+    def __init__(
+        self,
+        *,
+        username: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["username", b"username"]) -> None: ...
+
+global___ListUserKeysRequest = ListUserKeysRequest
+
+@typing.final
+class ListUserKeysResponse(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    KEYS_FIELD_NUMBER: builtins.int
+    @property
+    def keys(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___UserKey]: ...
+    def __init__(
+        self,
+        *,
+        keys: collections.abc.Iterable[global___UserKey] | None = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["keys", b"keys"]) -> None: ...
+
+global___ListUserKeysResponse = ListUserKeysResponse
+
+@typing.final
+class RemoveUserKeyRequest(google.protobuf.message.Message):
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    USERNAME_FIELD_NUMBER: builtins.int
+    FINGERPRINT_FIELD_NUMBER: builtins.int
+    username: builtins.str
+    fingerprint: builtins.str
+    def __init__(
+        self,
+        *,
+        username: builtins.str = ...,
+        fingerprint: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing.Literal["fingerprint", b"fingerprint", "username", b"username"]) -> None: ...
+
+global___RemoveUserKeyRequest = RemoveUserKeyRequest
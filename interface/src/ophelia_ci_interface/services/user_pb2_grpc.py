@@ -218,6 +218,26 @@ class UserServiceStub(object):
                 request_serializer=user__pb2.DeleteUserRequest.SerializeToString,
                 response_deserializer=common__pb2.Empty.FromString,
                 _registered_method=True)
+        self.SetUserRole = channel.unary_unary(
+                '/user.UserService/SetUserRole',
+                request_serializer=user__pb2.SetUserRoleRequest.SerializeToString,
+                response_deserializer=common__pb2.Empty.FromString,
+                _registered_method=True)
+        self.AddUserKey = channel.unary_unary(
+                '/user.UserService/AddUserKey',
+                request_serializer=user__pb2.AddUserKeyRequest.SerializeToString,
+                response_deserializer=user__pb2.UserKey.FromString,
+                _registered_method=True)
+        self.ListUserKeys = channel.unary_unary(
+                '/user.UserService/ListUserKeys',
+                request_serializer=user__pb2.ListUserKeysRequest.SerializeToString,
+                response_deserializer=user__pb2.ListUserKeysResponse.FromString,
+                _registered_method=True)
+        self.RemoveUserKey = channel.unary_unary(
+                '/user.UserService/RemoveUserKey',
+                request_serializer=user__pb2.RemoveUserKeyRequest.SerializeToString,
+                response_deserializer=common__pb2.Empty.FromString,
+                _registered_method=True)
 
 
 class UserServiceServicer(object):
@@ -253,6 +273,30 @@ class UserServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def SetUserRole(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def AddUserKey(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListUserKeys(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def RemoveUserKey(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_UserServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -281,6 +325,26 @@ def add_UserServiceServicer_to_server(servicer, server):
                     request_deserializer=user__pb2.DeleteUserRequest.FromString,
                     response_serializer=common__pb2.Empty.SerializeToString,
             ),
+            'SetUserRole': grpc.unary_unary_rpc_method_handler(
+                    servicer.SetUserRole,
+                    request_deserializer=user__pb2.SetUserRoleRequest.FromString,
+                    response_serializer=common__pb2.Empty.SerializeToString,
+            ),
+            'AddUserKey': grpc.unary_unary_rpc_method_handler(
+                    servicer.AddUserKey,
+                    request_deserializer=user__pb2.AddUserKeyRequest.FromString,
+                    response_serializer=user__pb2.UserKey.SerializeToString,
+            ),
+            'ListUserKeys': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListUserKeys,
+                    request_deserializer=user__pb2.ListUserKeysRequest.FromString,
+                    response_serializer=user__pb2.ListUserKeysResponse.SerializeToString,
+            ),
+            'RemoveUserKey': grpc.unary_unary_rpc_method_handler(
+                    servicer.RemoveUserKey,
+                    request_deserializer=user__pb2.RemoveUserKeyRequest.FromString,
+                    response_serializer=common__pb2.Empty.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'user.UserService', rpc_method_handlers)
@@ -426,3 +490,111 @@ class UserService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def SetUserRole(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/SetUserRole',
+            user__pb2.SetUserRoleRequest.SerializeToString,
+            common__pb2.Empty.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def AddUserKey(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/AddUserKey',
+            user__pb2.AddUserKeyRequest.SerializeToString,
+            user__pb2.UserKey.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def ListUserKeys(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/ListUserKeys',
+            user__pb2.ListUserKeysRequest.SerializeToString,
+            user__pb2.ListUserKeysResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
+    @staticmethod
+    def RemoveUserKey(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/user.UserService/RemoveUserKey',
+            user__pb2.RemoveUserKeyRequest.SerializeToString,
+            common__pb2.Empty.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
@@ -19,11 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	RepositoryService_CreateRepository_FullMethodName = "/repository.RepositoryService/CreateRepository"
-	RepositoryService_UpdateRepository_FullMethodName = "/repository.RepositoryService/UpdateRepository"
-	RepositoryService_ListRepository_FullMethodName   = "/repository.RepositoryService/ListRepository"
-	RepositoryService_GetRepository_FullMethodName    = "/repository.RepositoryService/GetRepository"
-	RepositoryService_DeleteRepository_FullMethodName = "/repository.RepositoryService/DeleteRepository"
+	RepositoryService_CreateRepository_FullMethodName       = "/repository.RepositoryService/CreateRepository"
+	RepositoryService_UpdateRepository_FullMethodName       = "/repository.RepositoryService/UpdateRepository"
+	RepositoryService_ListRepository_FullMethodName         = "/repository.RepositoryService/ListRepository"
+	RepositoryService_GetRepository_FullMethodName          = "/repository.RepositoryService/GetRepository"
+	RepositoryService_DeleteRepository_FullMethodName       = "/repository.RepositoryService/DeleteRepository"
+	RepositoryService_GrantRepositoryAccess_FullMethodName  = "/repository.RepositoryService/GrantRepositoryAccess"
+	RepositoryService_RevokeRepositoryAccess_FullMethodName = "/repository.RepositoryService/RevokeRepositoryAccess"
 )
 
 // RepositoryServiceClient is the client API for RepositoryService service.
@@ -35,6 +37,8 @@ type RepositoryServiceClient interface {
 	ListRepository(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListRepositoryResponse, error)
 	GetRepository(ctx context.Context, in *GetRepositoryRequest, opts ...grpc.CallOption) (*RepositoryResponse, error)
 	DeleteRepository(ctx context.Context, in *DeleteRepositoryRequest, opts ...grpc.CallOption) (*Empty, error)
+	GrantRepositoryAccess(ctx context.Context, in *GrantRepositoryAccessRequest, opts ...grpc.CallOption) (*Empty, error)
+	RevokeRepositoryAccess(ctx context.Context, in *RevokeRepositoryAccessRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type repositoryServiceClient struct {
@@ -95,6 +99,26 @@ func (c *repositoryServiceClient) DeleteRepository(ctx context.Context, in *Dele
 	return out, nil
 }
 
+func (c *repositoryServiceClient) GrantRepositoryAccess(ctx context.Context, in *GrantRepositoryAccessRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, RepositoryService_GrantRepositoryAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) RevokeRepositoryAccess(ctx context.Context, in *RevokeRepositoryAccessRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, RepositoryService_RevokeRepositoryAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RepositoryServiceServer is the server API for RepositoryService service.
 // All implementations must embed UnimplementedRepositoryServiceServer
 // for forward compatibility.
@@ -104,6 +128,8 @@ type RepositoryServiceServer interface {
 	ListRepository(context.Context, *Empty) (*ListRepositoryResponse, error)
 	GetRepository(context.Context, *GetRepositoryRequest) (*RepositoryResponse, error)
 	DeleteRepository(context.Context, *DeleteRepositoryRequest) (*Empty, error)
+	GrantRepositoryAccess(context.Context, *GrantRepositoryAccessRequest) (*Empty, error)
+	RevokeRepositoryAccess(context.Context, *RevokeRepositoryAccessRequest) (*Empty, error)
 	mustEmbedUnimplementedRepositoryServiceServer()
 }
 
@@ -129,6 +155,12 @@ func (UnimplementedRepositoryServiceServer) GetRepository(context.Context, *GetR
 func (UnimplementedRepositoryServiceServer) DeleteRepository(context.Context, *DeleteRepositoryRequest) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteRepository not implemented")
 }
+func (UnimplementedRepositoryServiceServer) GrantRepositoryAccess(context.Context, *GrantRepositoryAccessRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantRepositoryAccess not implemented")
+}
+func (UnimplementedRepositoryServiceServer) RevokeRepositoryAccess(context.Context, *RevokeRepositoryAccessRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRepositoryAccess not implemented")
+}
 func (UnimplementedRepositoryServiceServer) mustEmbedUnimplementedRepositoryServiceServer() {}
 func (UnimplementedRepositoryServiceServer) testEmbeddedByValue()                           {}
 
@@ -240,6 +272,42 @@ func _RepositoryService_DeleteRepository_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepositoryService_GrantRepositoryAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRepositoryAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).GrantRepositoryAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_GrantRepositoryAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).GrantRepositoryAccess(ctx, req.(*GrantRepositoryAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_RevokeRepositoryAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRepositoryAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).RevokeRepositoryAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RepositoryService_RevokeRepositoryAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).RevokeRepositoryAccess(ctx, req.(*RevokeRepositoryAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RepositoryService_ServiceDesc is the grpc.ServiceDesc for RepositoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +335,14 @@ var RepositoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteRepository",
 			Handler:    _RepositoryService_DeleteRepository_Handler,
 		},
+		{
+			MethodName: "GrantRepositoryAccess",
+			Handler:    _RepositoryService_GrantRepositoryAccess_Handler,
+		},
+		{
+			MethodName: "RevokeRepositoryAccess",
+			Handler:    _RepositoryService_RevokeRepositoryAccess_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "repository.proto",
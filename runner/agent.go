@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/executor"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// leaseWaitSeconds is how long the server is asked to wait for a job
+	// before answering a lease request with no job.
+	leaseWaitSeconds = 30
+	// retryDelay is the time waited before leasing again after an error.
+	retryDelay = 5 * time.Second
+	// rpcTimeout bounds the calls made to the server while running a job.
+	rpcTimeout = 30 * time.Second
+	// defaultHeartbeatInterval is used when the server does not send one.
+	defaultHeartbeatInterval = 10 * time.Second
+	// artifactChunkSize is the size of the chunks of the artifacts archives
+	// uploaded to the server.
+	artifactChunkSize = 64 * 1024
+)
+
+// agent leases jobs from the server and runs them with the local executor.
+type agent struct {
+	client   pb.RunnerServiceClient
+	config   Config
+	executor *executor.Executor
+
+	mu    sync.Mutex
+	token string
+}
+
+// register registers the runner to the server with the registration token,
+// and keeps the runner token used by the other calls.
+func (a *agent) register(ctx context.Context) error {
+	res, err := a.client.RegisterRunner(ctx, &pb.RegisterRunnerRequest{
+		RegistrationToken: a.config.Runner.RegistrationToken,
+		Name:              a.config.Runner.Name,
+		Labels:            a.config.Runner.Labels,
+	})
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.token = res.Token
+	a.mu.Unlock()
+	return nil
+}
+
+// authContext returns a context carrying the runner token.
+func (a *agent) authContext(ctx context.Context) context.Context {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+a.token)
+}
+
+// work leases and runs jobs one after the other until ctx is cancelled.
+//
+// If the runner token is rejected, for example because it expired, the runner
+// registers again.
+func (a *agent) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		lease, err := a.client.LeaseJob(a.authContext(ctx), &pb.LeaseJobRequest{WaitSeconds: leaseWaitSeconds})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error leasing job: %v", err)
+			if status.Code(err) == codes.Unauthenticated {
+				if err := a.register(ctx); err != nil {
+					log.Printf("Error registering runner: %v", err)
+				}
+			}
+			sleep(ctx, retryDelay)
+			continue
+		}
+		if lease.LeaseId == "" {
+			continue
+		}
+		a.run(ctx, lease)
+	}
+}
+
+// run runs a leased job, sending heartbeats and output to the server while it
+// runs, and reports its result.
+//
+// The job is stopped if the server reports it as cancelled, if the lease is
+// lost, or if ctx is cancelled.
+func (a *agent) run(ctx context.Context, lease *pb.LeaseJobResponse) {
+	log.Printf("Running job %v of build %v for %v at %v", lease.Job.Name, lease.BuildId, lease.Repository, lease.CommitHash)
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go a.heartbeat(jobCtx, cancel, lease)
+
+	output := &logUploader{agent: a, leaseID: lease.LeaseId}
+	success, exitCode, timedOut := a.runJob(jobCtx, lease, output)
+
+	completeCtx, done := context.WithTimeout(context.WithoutCancel(ctx), rpcTimeout)
+	defer done()
+	_, err := a.client.CompleteJob(a.authContext(completeCtx), &pb.CompleteJobRequest{
+		LeaseId:  lease.LeaseId,
+		Success:  success,
+		ExitCode: exitCode,
+		TimedOut: timedOut,
+	})
+	if err != nil {
+		log.Printf("Error completing job %v: %v", lease.Job.Name, err)
+		return
+	}
+	log.Printf("Job %v of build %v finished with exit code %d", lease.Job.Name, lease.BuildId, exitCode)
+}
+
+// runJob downloads the sources of a leased job into a fresh workspace, runs
+// the job in it and uploads its artifacts.
+//
+// Returns whether the job succeeded, the exit code of its last step and
+// whether it was stopped by a timeout.
+func (a *agent) runJob(ctx context.Context, lease *pb.LeaseJobResponse, output io.Writer) (bool, int32, bool) {
+	workspace, err := a.downloadSource(ctx, lease)
+	if err != nil {
+		log.Printf("Error downloading sources: %v", err)
+		fmt.Fprintf(output, "Failed to prepare workspace: %v\n", err)
+		return false, -1, false
+	}
+	defer a.executor.Cleanup(workspace)
+
+	build := executor.Build{
+		ID:         lease.BuildId,
+		Repository: lease.Repository,
+		Revision:   lease.CommitHash,
+		Branch:     lease.Branch,
+		Tag:        lease.Tag,
+		Parameters: lease.Parameters,
+		Secrets:    lease.Secrets,
+		Pipeline:   &pipeline.Pipeline{Env: lease.Env},
+	}
+	job := pipelineJob(lease.Job)
+	result := a.executor.RunJob(ctx, workspace, build, job, output)
+	a.uploadArtifacts(ctx, lease, workspace, job, result, output)
+	return result.Success, int32(result.ExitCode()), result.TimedOut
+}
+
+// uploadArtifacts uploads the artifacts of a job that was run to the server,
+// reporting failures in the job output without failing the job.
+func (a *agent) uploadArtifacts(ctx context.Context, lease *pb.LeaseJobResponse, workspace string, job pipeline.Job, result executor.JobResult, output io.Writer) {
+	files, err := executor.ArtifactFiles(workspace, job, result)
+	if err != nil {
+		fmt.Fprintf(output, "Failed to collect artifacts: %v\n", err)
+		return
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stream, err := a.client.UploadArtifact(a.authContext(ctx))
+	if err == nil {
+		writer := &artifactWriter{stream: stream, leaseID: lease.LeaseId}
+		if err = executor.ArchiveArtifacts(workspace, files, writer); err == nil {
+			err = writer.flush()
+		}
+		// A failed send reports io.EOF, and the actual error on close.
+		if err == nil || errors.Is(err, io.EOF) {
+			_, err = stream.CloseAndRecv()
+		}
+	}
+	if err != nil {
+		log.Printf("Error uploading artifacts: %v", err)
+		fmt.Fprintf(output, "Failed to upload artifacts: %v\n", err)
+		return
+	}
+	fmt.Fprintf(output, "Uploaded %d artifact files\n", len(files))
+}
+
+// downloadSource extracts the sources of a leased job, streamed by the server,
+// into a fresh workspace named after the lease.
+func (a *agent) downloadSource(ctx context.Context, lease *pb.LeaseJobResponse) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := a.client.DownloadSource(a.authContext(ctx), &pb.DownloadSourceRequest{LeaseId: lease.LeaseId})
+	if err != nil {
+		return "", err
+	}
+	return a.executor.ExtractWorkspace(lease.LeaseId, &sourceReader{stream: stream})
+}
+
+// heartbeat renews a lease until ctx is cancelled, and cancels the job if the
+// server reports it as cancelled or no longer knows the lease.
+func (a *agent) heartbeat(ctx context.Context, cancel context.CancelFunc, lease *pb.LeaseJobResponse) {
+	interval := time.Duration(lease.HeartbeatInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		callCtx, done := context.WithTimeout(ctx, rpcTimeout)
+		res, err := a.client.Heartbeat(a.authContext(callCtx), &pb.HeartbeatRequest{LeaseId: lease.LeaseId})
+		done()
+		switch {
+		case status.Code(err) == codes.NotFound:
+			log.Printf("Lease of job %v was lost, stopping it", lease.Job.Name)
+			cancel()
+			return
+		case err != nil:
+			log.Printf("Error sending heartbeat: %v", err)
+		case res.Cancelled:
+			log.Printf("Job %v was cancelled, stopping it", lease.Job.Name)
+			cancel()
+			return
+		}
+	}
+}
+
+// pipelineJob converts the definition of a leased job to a pipeline job.
+func pipelineJob(definition *pb.JobDefinition) pipeline.Job {
+	job := pipeline.Job{Name: definition.Name, Env: definition.Env, Timeout: definition.Timeout.AsDuration()}
+	for _, step := range definition.Steps {
+		job.Steps = append(job.Steps, pipeline.Step{
+			Name:      step.Name,
+			Run:       step.Run,
+			Env:       step.Env,
+			Artifacts: step.Artifacts,
+			Timeout:   step.Timeout.AsDuration(),
+		})
+	}
+	if definition.Cache != nil {
+		job.Cache = &pipeline.Cache{Key: definition.Cache.Key, Paths: definition.Cache.Paths}
+	}
+	return job
+}
+
+// sleep waits for the given duration or until ctx is cancelled.
+func sleep(ctx context.Context, duration time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(duration):
+	}
+}
+
+// logUploader sends the output written to it to the server as log chunks.
+//
+// Upload errors are logged and otherwise ignored, so that a job is not
+// disturbed by a temporary loss of connection.
+type logUploader struct {
+	agent   *agent
+	leaseID string
+}
+
+func (u *logUploader) Write(p []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	data := append([]byte(nil), p...)
+	if _, err := u.agent.client.UploadLogChunk(u.agent.authContext(ctx), &pb.UploadLogChunkRequest{LeaseId: u.leaseID, Data: data}); err != nil {
+		log.Printf("Error uploading log chunk: %v", err)
+	}
+	return len(p), nil
+}
+
+// sourceReader reads the chunks of a DownloadSource stream as a continuous
+// stream of bytes.
+type sourceReader struct {
+	stream pb.RunnerService_DownloadSourceClient
+	buffer []byte
+}
+
+func (r *sourceReader) Read(p []byte) (int, error) {
+	for len(r.buffer) == 0 {
+		chunk, err := r.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		r.buffer = chunk.Data
+	}
+	n := copy(p, r.buffer)
+	r.buffer = r.buffer[n:]
+	return n, nil
+}
+
+// artifactWriter sends the data written to it to an UploadArtifact stream, in
+// chunks of artifactChunkSize bytes.
+type artifactWriter struct {
+	stream  pb.RunnerService_UploadArtifactClient
+	leaseID string
+	buffer  []byte
+}
+
+func (w *artifactWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+	for len(w.buffer) >= artifactChunkSize {
+		if err := w.send(w.buffer[:artifactChunkSize]); err != nil {
+			return 0, err
+		}
+		w.buffer = w.buffer[artifactChunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush sends the remaining buffered data.
+func (w *artifactWriter) flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	return w.send(w.buffer)
+}
+
+// send sends a chunk of the archive.
+func (w *artifactWriter) send(data []byte) error {
+	return w.stream.Send(&pb.UploadArtifactRequest{LeaseId: w.leaseID, Data: append([]byte(nil), data...)})
+}
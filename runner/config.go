@@ -0,0 +1,110 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+)
+
+type Config struct {
+	Runner struct {
+		Server            string   `toml:"server"`
+		RegistrationToken string   `toml:"registration_token"`
+		Name              string   `toml:"name"`
+		Labels            []string `toml:"labels"`
+		WorkDir           string   `toml:"work_dir"`
+		Concurrency       int      `toml:"concurrency"`
+		CacheMaxSize      int      `toml:"cache_max_size"`
+	} `toml:"runner"`
+	SSL struct {
+		Enabled bool   `toml:"enabled"`
+		CAFile  string `toml:"ca_file"`
+	} `toml:"ssl"`
+}
+
+var (
+	configCache Config
+)
+
+const configFile = "/etc/ophelia-ci/runner-config.toml"
+
+// LoadConfig reads the runner configuration from a TOML file located at
+// configFile. If the file does not exist, it is created from the environment
+// variables. If reading the file or unmarshalling the TOML data fails, the
+// function panics. It returns the cached configuration.
+func LoadConfig() Config {
+	var err error
+	if pb.CheckRunningFromImage() {
+		return loadConfigFromEnv()
+	}
+
+	if _, err := os.Stat(configFile); os.IsNotExist(err) {
+		config := loadConfigFromEnv()
+		if err := pb.SaveConfig(configFile, config); err != nil {
+			panic(err)
+		}
+		return config
+	}
+
+	configCache, err = pb.LoadConfigFromFile(configFile, configCache)
+	if err != nil {
+		panic(err)
+	}
+
+	return configCache
+}
+
+// loadConfigFromEnv loads the runner configuration from environment variables.
+// It retrieves the server address, registration token, runner name, labels,
+// work directory, concurrency, cache size in megabytes and TLS settings from
+// the corresponding environment variables and populates the Config struct. If
+// the environment variables are not set, it falls back to default values.
+func loadConfigFromEnv() (config Config) {
+	server := os.Getenv("OPHELIA_CI_SERVER")
+	if server == "" {
+		server = "localhost:50051"
+	}
+	config.Runner.Server = server
+	config.Runner.RegistrationToken = os.Getenv("OPHELIA_CI_RUNNER_REGISTRATION_TOKEN")
+
+	name := os.Getenv("OPHELIA_CI_RUNNER_NAME")
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatalf("OPHELIA_CI_RUNNER_NAME is not set and the hostname is unavailable: %v", err)
+		}
+		name = hostname
+	}
+	config.Runner.Name = name
+
+	if labels := os.Getenv("OPHELIA_CI_RUNNER_LABELS"); labels != "" {
+		config.Runner.Labels = strings.Split(labels, ",")
+	}
+
+	workDir := os.Getenv("OPHELIA_CI_RUNNER_WORK_DIR")
+	if workDir == "" {
+		workDir = "/var/lib/ophelia-ci-runner/"
+		log.Printf("OPHELIA_CI_RUNNER_WORK_DIR is not set. Using default path %s.", workDir)
+	}
+	config.Runner.WorkDir = workDir
+
+	concurrency, err := strconv.Atoi(os.Getenv("OPHELIA_CI_RUNNER_CONCURRENCY"))
+	if err != nil || concurrency <= 0 {
+		concurrency = 1
+	}
+	config.Runner.Concurrency = concurrency
+
+	cacheMaxSize, err := strconv.Atoi(os.Getenv("OPHELIA_CI_RUNNER_CACHE_MAX_SIZE"))
+	if err != nil || cacheMaxSize <= 0 {
+		log.Printf("OPHELIA_CI_RUNNER_CACHE_MAX_SIZE is not set or invalid. Using default cache size of 1024 MB.")
+		cacheMaxSize = 1024
+	}
+	config.Runner.CacheMaxSize = cacheMaxSize
+
+	config.SSL.Enabled, _ = strconv.ParseBool(os.Getenv("OPHELIA_CI_RUNNER_TLS"))
+	config.SSL.CAFile = os.Getenv("OPHELIA_CI_RUNNER_CA_FILE")
+	return
+}
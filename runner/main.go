@@ -0,0 +1,87 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"crypto/tls"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/cache"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/executor"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/redact"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Main starts the Ophelia CI Runner, which registers itself to the server and
+// runs the jobs leased from it until it receives SIGINT or SIGTERM.
+func main() {
+	log.Println("Ophelia CI Runner started!")
+
+	config := LoadConfig()
+
+	redactor, err := redact.New()
+	if err != nil {
+		log.Fatalf("Failed to load redact patterns: %v", err)
+	}
+	log.SetOutput(redactor.WithValues(config.Runner.RegistrationToken).Writer(log.Writer()))
+
+	creds, err := transportCredentials(config)
+	if err != nil {
+		log.Fatalf("Failed to load TLS credentials: %v", err)
+	}
+	conn, err := grpc.NewClient(config.Runner.Server, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Fatalf("did not connect: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	agent := &agent{
+		client:   pb.NewRunnerServiceClient(conn),
+		config:   config,
+		executor: executor.NewExecutor(config.Runner.WorkDir),
+	}
+	agent.executor.Cache = cache.New(filepath.Join(config.Runner.WorkDir, "cache"), cmp.Or(int64(config.Runner.CacheMaxSize)<<20, cache.DefaultMaxSize))
+	if err := agent.register(ctx); err != nil {
+		log.Fatalf("Failed to register runner: %v", err)
+	}
+	log.Printf("Registered as runner %v with labels %v", config.Runner.Name, config.Runner.Labels)
+
+	var workers sync.WaitGroup
+	for range max(config.Runner.Concurrency, 1) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			agent.work(ctx)
+		}()
+	}
+	workers.Wait()
+	log.Println("Ophelia CI Runner stopped")
+}
+
+// transportCredentials returns the credentials of the connection to the server.
+//
+// The connection uses TLS when it is enabled or a CA file is configured, and
+// verifies the server certificate with the CA file, or the system roots if
+// there is none. Otherwise the connection is in plaintext, and the server does
+// not send the secrets of repositories.
+func transportCredentials(config Config) (credentials.TransportCredentials, error) {
+	if config.SSL.CAFile != "" {
+		return credentials.NewClientTLSFromFile(config.SSL.CAFile, "")
+	}
+	if config.SSL.Enabled {
+		return credentials.NewTLS(&tls.Config{}), nil
+	}
+	log.Println("TLS is disabled, the server will not send repository secrets")
+	return insecure.NewCredentials(), nil
+}
@@ -0,0 +1,346 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: runner.proto
+
+package ophelia_ci
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RunnerService_RegisterRunner_FullMethodName = "/runner.RunnerService/RegisterRunner"
+	RunnerService_LeaseJob_FullMethodName       = "/runner.RunnerService/LeaseJob"
+	RunnerService_Heartbeat_FullMethodName      = "/runner.RunnerService/Heartbeat"
+	RunnerService_DownloadSource_FullMethodName = "/runner.RunnerService/DownloadSource"
+	RunnerService_UploadLogChunk_FullMethodName = "/runner.RunnerService/UploadLogChunk"
+	RunnerService_UploadArtifact_FullMethodName = "/runner.RunnerService/UploadArtifact"
+	RunnerService_CompleteJob_FullMethodName    = "/runner.RunnerService/CompleteJob"
+)
+
+// RunnerServiceClient is the client API for RunnerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RunnerServiceClient interface {
+	RegisterRunner(ctx context.Context, in *RegisterRunnerRequest, opts ...grpc.CallOption) (*RegisterRunnerResponse, error)
+	LeaseJob(ctx context.Context, in *LeaseJobRequest, opts ...grpc.CallOption) (*LeaseJobResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	DownloadSource(ctx context.Context, in *DownloadSourceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SourceChunk], error)
+	UploadLogChunk(ctx context.Context, in *UploadLogChunkRequest, opts ...grpc.CallOption) (*Empty, error)
+	UploadArtifact(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadArtifactRequest, Empty], error)
+	CompleteJob(ctx context.Context, in *CompleteJobRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type runnerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRunnerServiceClient(cc grpc.ClientConnInterface) RunnerServiceClient {
+	return &runnerServiceClient{cc}
+}
+
+func (c *runnerServiceClient) RegisterRunner(ctx context.Context, in *RegisterRunnerRequest, opts ...grpc.CallOption) (*RegisterRunnerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterRunnerResponse)
+	err := c.cc.Invoke(ctx, RunnerService_RegisterRunner_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerServiceClient) LeaseJob(ctx context.Context, in *LeaseJobRequest, opts ...grpc.CallOption) (*LeaseJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaseJobResponse)
+	err := c.cc.Invoke(ctx, RunnerService_LeaseJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, RunnerService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerServiceClient) DownloadSource(ctx context.Context, in *DownloadSourceRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SourceChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RunnerService_ServiceDesc.Streams[0], RunnerService_DownloadSource_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadSourceRequest, SourceChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RunnerService_DownloadSourceClient = grpc.ServerStreamingClient[SourceChunk]
+
+func (c *runnerServiceClient) UploadLogChunk(ctx context.Context, in *UploadLogChunkRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, RunnerService_UploadLogChunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runnerServiceClient) UploadArtifact(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadArtifactRequest, Empty], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RunnerService_ServiceDesc.Streams[1], RunnerService_UploadArtifact_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadArtifactRequest, Empty]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RunnerService_UploadArtifactClient = grpc.ClientStreamingClient[UploadArtifactRequest, Empty]
+
+func (c *runnerServiceClient) CompleteJob(ctx context.Context, in *CompleteJobRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, RunnerService_CompleteJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RunnerServiceServer is the server API for RunnerService service.
+// All implementations must embed UnimplementedRunnerServiceServer
+// for forward compatibility.
+type RunnerServiceServer interface {
+	RegisterRunner(context.Context, *RegisterRunnerRequest) (*RegisterRunnerResponse, error)
+	LeaseJob(context.Context, *LeaseJobRequest) (*LeaseJobResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	DownloadSource(*DownloadSourceRequest, grpc.ServerStreamingServer[SourceChunk]) error
+	UploadLogChunk(context.Context, *UploadLogChunkRequest) (*Empty, error)
+	UploadArtifact(grpc.ClientStreamingServer[UploadArtifactRequest, Empty]) error
+	CompleteJob(context.Context, *CompleteJobRequest) (*Empty, error)
+	mustEmbedUnimplementedRunnerServiceServer()
+}
+
+// UnimplementedRunnerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRunnerServiceServer struct{}
+
+func (UnimplementedRunnerServiceServer) RegisterRunner(context.Context, *RegisterRunnerRequest) (*RegisterRunnerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterRunner not implemented")
+}
+func (UnimplementedRunnerServiceServer) LeaseJob(context.Context, *LeaseJobRequest) (*LeaseJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseJob not implemented")
+}
+func (UnimplementedRunnerServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedRunnerServiceServer) DownloadSource(*DownloadSourceRequest, grpc.ServerStreamingServer[SourceChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadSource not implemented")
+}
+func (UnimplementedRunnerServiceServer) UploadLogChunk(context.Context, *UploadLogChunkRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadLogChunk not implemented")
+}
+func (UnimplementedRunnerServiceServer) UploadArtifact(grpc.ClientStreamingServer[UploadArtifactRequest, Empty]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadArtifact not implemented")
+}
+func (UnimplementedRunnerServiceServer) CompleteJob(context.Context, *CompleteJobRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteJob not implemented")
+}
+func (UnimplementedRunnerServiceServer) mustEmbedUnimplementedRunnerServiceServer() {}
+func (UnimplementedRunnerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRunnerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RunnerServiceServer will
+// result in compilation errors.
+type UnsafeRunnerServiceServer interface {
+	mustEmbedUnimplementedRunnerServiceServer()
+}
+
+func RegisterRunnerServiceServer(s grpc.ServiceRegistrar, srv RunnerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRunnerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RunnerService_ServiceDesc, srv)
+}
+
+func _RunnerService_RegisterRunner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRunnerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServiceServer).RegisterRunner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RunnerService_RegisterRunner_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServiceServer).RegisterRunner(ctx, req.(*RegisterRunnerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RunnerService_LeaseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaseJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServiceServer).LeaseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RunnerService_LeaseJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServiceServer).LeaseJob(ctx, req.(*LeaseJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RunnerService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RunnerService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RunnerService_DownloadSource_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadSourceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RunnerServiceServer).DownloadSource(m, &grpc.GenericServerStream[DownloadSourceRequest, SourceChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RunnerService_DownloadSourceServer = grpc.ServerStreamingServer[SourceChunk]
+
+func _RunnerService_UploadLogChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadLogChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServiceServer).UploadLogChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RunnerService_UploadLogChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServiceServer).UploadLogChunk(ctx, req.(*UploadLogChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RunnerService_UploadArtifact_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RunnerServiceServer).UploadArtifact(&grpc.GenericServerStream[UploadArtifactRequest, Empty]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RunnerService_UploadArtifactServer = grpc.ClientStreamingServer[UploadArtifactRequest, Empty]
+
+func _RunnerService_CompleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RunnerServiceServer).CompleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RunnerService_CompleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RunnerServiceServer).CompleteJob(ctx, req.(*CompleteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RunnerService_ServiceDesc is the grpc.ServiceDesc for RunnerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RunnerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "runner.RunnerService",
+	HandlerType: (*RunnerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterRunner",
+			Handler:    _RunnerService_RegisterRunner_Handler,
+		},
+		{
+			MethodName: "LeaseJob",
+			Handler:    _RunnerService_LeaseJob_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _RunnerService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "UploadLogChunk",
+			Handler:    _RunnerService_UploadLogChunk_Handler,
+		},
+		{
+			MethodName: "CompleteJob",
+			Handler:    _RunnerService_CompleteJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DownloadSource",
+			Handler:       _RunnerService_DownloadSource_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadArtifact",
+			Handler:       _RunnerService_UploadArtifact_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "runner.proto",
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+)
+
+// runCommand runs a command given on the command line instead of starting the
+// server, and exits with a non-zero status if it fails.
+//
+// The available commands are:
+// - admin reset: Resets the bootstrap, so that the server issues a new unique
+// key when it next starts, for recovering access when every administrator lost
+// their key.
+func runCommand(args []string) {
+	switch {
+	case len(args) == 2 && args[0] == "admin" && args[1] == "reset":
+		if err := resetBootstrap(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to reset the bootstrap: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("The bootstrap was reset. Restart the server, and log in with the unique key it logs")
+		fmt.Println("to create an administrator, or to replace the public key of an existing one.")
+	case len(args) == 1 && (args[0] == "--help" || args[0] == "help"):
+		printHelp()
+	default:
+		printHelp()
+		os.Exit(2)
+	}
+}
+
+func printHelp() {
+	fmt.Println("Usage: ophelia-ci-server [command]")
+	fmt.Println("Starts the server when no command is given.")
+	fmt.Println("Commands:")
+	fmt.Println("	admin reset	Issue a new unique key on the next start, to recover administrator access")
+}
+
+// resetBootstrap resets the bootstrap recorded in the database of the server.
+//
+// Returns an error if the database cannot be opened or the bootstrap cannot be
+// reset.
+func resetBootstrap() error {
+	config := LoadConfig()
+	db, err := sql.Open("sqlite3", config.Server.HomePath+"/ophelia.db")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	bootstrapStore := store.NewSQLBootstrapStore(db)
+	return bootstrapStore.ResetBootstrap()
+}
@@ -0,0 +1,86 @@
+package artifact
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Manager keeps the artifacts of builds on disk under its root directory, in
+// one directory per build holding a gzipped tar archive per job.
+type Manager struct {
+	root string
+}
+
+// NewManager creates a Manager storing artifacts under root.
+func NewManager(root string) *Manager {
+	return &Manager{root: root}
+}
+
+// Save stores the artifacts archive of a job, written by write, replacing any
+// previous archive of the job.
+//
+// The archive is written to a temporary file first, so that a failed or
+// interrupted write never leaves a partial archive behind.
+//
+// Returns the size of the stored archive.
+func (m *Manager) Save(buildID, jobID string, write func(io.Writer) error) (int64, error) {
+	dir := m.dir(buildID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create artifacts directory: %w", err)
+	}
+	file, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create artifacts archive: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := write(file); err != nil {
+		file.Close()
+		return 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(file.Name(), m.path(buildID, jobID)); err != nil {
+		return 0, fmt.Errorf("failed to store artifacts archive: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// Open opens the artifacts archive of a job for reading.
+func (m *Manager) Open(buildID, jobID string) (*os.File, error) {
+	return os.Open(m.path(buildID, jobID))
+}
+
+// Remove deletes the artifacts archive of a job, and the directory of its build
+// once it holds no other archive.
+func (m *Manager) Remove(buildID, jobID string) error {
+	if err := os.Remove(m.path(buildID, jobID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	entries, err := os.ReadDir(m.dir(buildID))
+	if err == nil && len(entries) == 0 {
+		return os.Remove(m.dir(buildID))
+	}
+	return nil
+}
+
+// dir returns the directory of the artifacts of a build. The IDs are generated
+// by the server, but are cleaned anyway so that they never point outside of
+// root.
+func (m *Manager) dir(buildID string) string {
+	return filepath.Join(m.root, filepath.Base(buildID))
+}
+
+// path returns the path of the artifacts archive of a job.
+func (m *Manager) path(buildID, jobID string) string {
+	return filepath.Join(m.dir(buildID), filepath.Base(jobID)+".tar.gz")
+}
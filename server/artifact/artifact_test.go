@@ -0,0 +1,45 @@
+package artifact
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveAndRemove(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	size, err := manager.Save("build", "job", func(w io.Writer) error {
+		_, err := io.WriteString(w, "archive")
+		return err
+	})
+	if err != nil || size != 7 {
+		t.Fatalf("unexpected save result %d: %v", size, err)
+	}
+
+	_, err = manager.Save("build", "job", func(w io.Writer) error {
+		io.WriteString(w, "partial")
+		return errors.New("upload interrupted")
+	})
+	if err == nil {
+		t.Fatal("expected the failed save to be reported")
+	}
+
+	file, err := manager.Open("build", "job")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != "archive" {
+		t.Fatalf("a failed save replaced the archive: %q", content)
+	}
+
+	if err := manager.Remove("build", "job"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(manager.root, "build")); !os.IsNotExist(err) {
+		t.Fatalf("empty build directory was not removed: %v", err)
+	}
+}
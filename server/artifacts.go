@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/dispatch"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/executor"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+)
+
+const (
+	// defaultArtifactRetentionDays is the number of days artifacts are kept
+	// when neither the repository nor the configuration sets it.
+	defaultArtifactRetentionDays = 30
+	// artifactSweepInterval is the time between two sweeps of the expired
+	// artifacts.
+	artifactSweepInterval = time.Hour
+)
+
+// collectArtifacts stores the artifacts of a job run by the local executor,
+// collected from its workspace.
+func (s *server) collectArtifacts(job *dispatch.Job, workspace string, result executor.JobResult) {
+	files, err := executor.ArtifactFiles(workspace, job.Definition, result)
+	if err != nil {
+		log.Printf("Error collecting artifacts of job %v: %v", job.ID, err)
+		fmt.Fprintf(job.Output, "Failed to collect artifacts: %v\n", err)
+		return
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	err = s.saveArtifacts(job, func(w io.Writer) error {
+		return executor.ArchiveArtifacts(workspace, files, w)
+	})
+	if err != nil {
+		fmt.Fprintf(job.Output, "Failed to store artifacts: %v\n", err)
+		return
+	}
+	fmt.Fprintf(job.Output, "Stored %d artifact files\n", len(files))
+}
+
+// saveArtifacts stores the artifacts archive of a job, written by write, and
+// records it.
+func (s *server) saveArtifacts(job *dispatch.Job, write func(io.Writer) error) error {
+	repo, err := s.repositorieStore.GetRepositoryByName(job.Build.Repository)
+	if err != nil {
+		log.Printf("Error getting repository: %v", err)
+		return err
+	}
+
+	size, err := s.artifacts.Save(job.Build.ID, job.ID, write)
+	if err != nil {
+		log.Printf("Error storing artifacts of job %v: %v", job.ID, err)
+		return err
+	}
+	err = s.artifactStore.CreateArtifact(store.Artifact{
+		JobId:        job.ID,
+		BuildId:      job.Build.ID,
+		RepositoryId: repo.Id,
+		Job:          job.Definition.Name,
+		Size:         size,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		s.artifacts.Remove(job.Build.ID, job.ID)
+		return err
+	}
+	log.Printf("Stored %d bytes of artifacts of job %v", size, job.ID)
+	return nil
+}
+
+// retentionDays returns the number of days the artifacts of a repository
+// are kept, which is the configured default unless the repository sets it.
+func (s *server) retentionDays(repositoryId string) int {
+	days, ok, err := s.artifactStore.GetRetention(repositoryId)
+	if err != nil || !ok {
+		return s.artifactRetention
+	}
+	return days
+}
+
+// startArtifactSweeper deletes the expired artifacts of every repository
+// periodically until ctx is cancelled.
+func (s *server) startArtifactSweeper(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(artifactSweepInterval)
+		defer ticker.Stop()
+		for {
+			s.sweepArtifacts(time.Now())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sweepArtifacts deletes the artifacts stored for longer than the retention
+// period of their repository at now.
+func (s *server) sweepArtifacts(now time.Time) {
+	repos, err := s.repositorieStore.ListRepositories()
+	if err != nil {
+		log.Printf("Error listing repositories: %v", err)
+		return
+	}
+
+	for _, repo := range repos.Repositories {
+		days := s.retentionDays(repo.Id)
+		expired, err := s.artifactStore.ListArtifactsBefore(repo.Id, now.AddDate(0, 0, -days))
+		if err != nil {
+			continue
+		}
+		for _, artifact := range expired {
+			if err := s.artifacts.Remove(artifact.BuildId, artifact.JobId); err != nil {
+				log.Printf("Error removing artifacts of job %v: %v", artifact.JobId, err)
+				continue
+			}
+			s.artifactStore.DeleteArtifact(artifact.JobId)
+		}
+		if len(expired) > 0 {
+			log.Printf("Removed the artifacts of %d jobs of %v older than %d days", len(expired), repo.Name, days)
+		}
+	}
+}
@@ -4,34 +4,70 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 var (
-	jwtSecret             = getSecret()
-	uniqueKey             = randomKey()
+	jwtSecret = getSecret()
+	// uniqueKey is the key that creates the first administrator, which is
+	// generated when a server that was not bootstrapped starts, and is empty
+	// otherwise.
+	uniqueKey string
+	// bootstrapMutex serializes the unique key logins, so that the unique
+	// key creates a single administrator.
+	bootstrapMutex        sync.Mutex
 	noAuthNeededFunctions = map[string]bool{
 		"/user.AuthService/AuthenticationChallenge": true,
 		"/user.AuthService/Authentication":          true,
 		"/user.AuthService/UniqueKeyLogin":          true,
 		"/health.HealthService/Health":              true,
+		"/runner.RunnerService/RegisterRunner":      true,
 	}
 )
 
 const (
-	uniqueKeyExpirationDays = 1
+	// runnerServicePrefix is the prefix of the methods called by runners,
+	// which are authenticated with runner tokens instead of user tokens.
+	runnerServicePrefix = "/runner.RunnerService/"
+	// signalsServicePrefix is the prefix of the methods called by the
+	// post-receive hooks, which also accept the hook secret of the repository
+	// named in the request.
+	signalsServicePrefix = "/signal.Signals/"
 )
 
+// usernameContextKey is the context key under which AuthInterceptor stores the
+// username of the authenticated caller.
+type usernameContextKey struct{}
+
+// runnerContextKey is the context key under which AuthInterceptor stores the
+// ID of the authenticated runner.
+type runnerContextKey struct{}
+
+// hookContextKey is the context key under which AuthInterceptor stores the
+// repository whose hook secret authenticated a call to the signals service.
+type hookContextKey struct{}
+
 // AuthInterceptor is a gRPC interceptor that verifies the JWT token sent
 // by the client in the Authorization header. It skips authentication for
 // methods that are used for authentication.
@@ -39,22 +75,168 @@ const (
 // The interceptor is called by gRPC for each unary RPC received by the
 // server. It extracts the JWT token from the context, verifies it and
 // returns an error if the token is invalid or missing. If the token is
-// valid, it calls the handler function to process the RPC.
-func AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	methodName := info.FullMethod
+// valid, it calls the handler function to process the RPC with the
+// username of the caller stored in the context.
+//
+// The methods of the signals service also accept the hook secret of the
+// repository they are called for, so that post-receive hooks do not need a
+// user token.
+//
+// Calls made with a user token are then authorized, so that users only call
+// the methods their role allows.
+func (s *server) AuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var err error
+	if strings.HasPrefix(info.FullMethod, signalsServicePrefix) {
+		ctx, err = authenticateHook(ctx, info.FullMethod, req)
+	} else {
+		ctx, err = authenticate(ctx, info.FullMethod)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
 
+// AuthStreamInterceptor is the streaming counterpart of AuthInterceptor.
+//
+// The interceptor is called by gRPC for each streaming RPC received by the
+// server. It verifies the JWT token in the same way as AuthInterceptor, and
+// calls the handler with a stream whose context carries the username of the
+// caller. The call is authorized when the handler receives the request.
+func (s *server) AuthStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	stream := &authenticatedStream{ServerStream: ss, ctx: ctx}
+	stream.authorize = func(req any) error {
+		return s.authorize(ctx, info.FullMethod, req)
+	}
+	return handler(srv, stream)
+}
+
+// authenticatedStream is a grpc.ServerStream whose context carries the
+// username of the authenticated caller.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	// authorize authorizes the call with its first message, and is nil once
+	// it has been called.
+	authorize func(req any) error
+}
+
+// Context returns the context of the stream with the username of the caller.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg receives a message of the stream, authorizing the call with the
+// first one.
+func (s *authenticatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if authorize := s.authorize; authorize != nil {
+		s.authorize = nil
+		return authorize(m)
+	}
+	return nil
+}
+
+// authenticate verifies the JWT token of a call to the given method, unless the
+// method does not need authentication.
+//
+// The methods of the runner service only accept runner tokens, and every other
+// method only accepts user tokens.
+//
+// Returns:
+// - context.Context: The context of the call, with the username of the caller or
+// the ID of the runner if authenticated.
+// - error: An error if the token is invalid or missing.
+func authenticate(ctx context.Context, methodName string) (context.Context, error) {
 	if noAuthNeededFunctions[methodName] {
 		log.Println("Skipping authentication for method:", methodName)
-		return handler(ctx, req)
+		return ctx, nil
 	}
 	log.Println("Authenticating method:", methodName)
 
-	_, err := extractAndVerifyJWT(ctx)
+	if strings.HasPrefix(methodName, runnerServicePrefix) {
+		runnerID, err := extractAndVerifyRunnerJWT(ctx)
+		if err != nil {
+			log.Println("Error extracting and verifying runner JWT:", err)
+			return nil, status.Error(codes.Unauthenticated, "invalid runner token")
+		}
+		return context.WithValue(ctx, runnerContextKey{}, runnerID), nil
+	}
+
+	username, err := extractAndVerifyJWT(ctx)
 	if err != nil {
 		log.Println("Error extracting and verifying JWT:", err)
 		return nil, err
 	}
-	return handler(ctx, req)
+	return context.WithValue(ctx, usernameContextKey{}, username), nil
+}
+
+// usernameFromContext returns the username of the authenticated caller, as
+// stored in the context by AuthInterceptor, or an empty string if there is none.
+func usernameFromContext(ctx context.Context) string {
+	username, _ := ctx.Value(usernameContextKey{}).(string)
+	return username
+}
+
+// repositoryRequest is a request made for a single repository, such as a
+// commit signal.
+type repositoryRequest interface {
+	GetRepository() string
+}
+
+// authenticateHook verifies the token of a call to a method of the signals
+// service. The token is either a user JWT or the hook secret of the repository
+// named in the request, compared in constant time.
+//
+// Returns:
+// - context.Context: The context of the call, with the username of the caller
+// if it sent a user token, or the repository if it sent its hook secret.
+// - error: An error if the token is neither a valid user token nor the hook
+// secret of the repository.
+func authenticateHook(ctx context.Context, methodName string, req interface{}) (context.Context, error) {
+	if ctx, err := authenticate(ctx, methodName); err == nil {
+		return ctx, nil
+	}
+
+	token, ok := extractTokenFromContext(ctx)
+	request, isRepositoryRequest := req.(repositoryRequest)
+	if !ok || !isRepositoryRequest {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	name := request.GetRepository()
+	if name == "" || filepath.Base(name) != name {
+		return nil, status.Error(codes.Unauthenticated, "invalid hook secret")
+	}
+	secret, err := git.HookSecret(getRepoPath(name))
+	if err != nil || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		log.Printf("Invalid hook secret for repository %v", name)
+		return nil, status.Error(codes.Unauthenticated, "invalid hook secret")
+	}
+	return context.WithValue(ctx, hookContextKey{}, name), nil
+}
+
+// hookFromContext returns the repository whose hook secret authenticated the
+// call, as stored in the context by AuthInterceptor, or an empty string if the
+// call was not made by a post-receive hook.
+func hookFromContext(ctx context.Context) string {
+	repository, _ := ctx.Value(hookContextKey{}).(string)
+	return repository
+}
+
+// runnerFromContext returns the ID of the authenticated runner, as stored in
+// the context by AuthInterceptor, or an empty string if there is none.
+func runnerFromContext(ctx context.Context) string {
+	runnerID, _ := ctx.Value(runnerContextKey{}).(string)
+	return runnerID
 }
 
 // getSecret retrieves the server secret from the configuration. If no secret
@@ -107,7 +289,8 @@ func (s *server) AuthenticationChallenge(ctx context.Context, req *pb.Authentica
 }
 
 // Authentication verifies the user's challenge response and returns a JWT token
-// if the authentication is successful.
+// if the authentication is successful. The response may be signed with any of
+// the keys of the user that have not expired.
 //
 // Parameters:
 //   - ctx: The context for the request, which carries deadlines, cancellation signals,
@@ -128,15 +311,9 @@ func (s *server) Authentication(ctx context.Context, req *pb.AuthenticationReque
 	}
 	s.challenges.Delete(req.Username)
 
-	storedPublicKey, err := s.userStore.GetPublicKeyByUsername(req.Username)
+	storedKeys, err := s.userPublicKeys(req.Username)
 	if err != nil {
-		log.Println("Error getting public key:", err)
-		return
-	}
-
-	storedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(storedPublicKey))
-	if err != nil {
-		log.Println("Error parsing stored public key:", err)
+		log.Println("Error getting public keys:", err)
 		return
 	}
 
@@ -152,7 +329,9 @@ func (s *server) Authentication(ctx context.Context, req *pb.AuthenticationReque
 		return
 	}
 
-	if !verifySignature(storedKey, challengeBytes, signature) {
+	if !slices.ContainsFunc(storedKeys, func(storedKey ssh.PublicKey) bool {
+		return verifySignature(storedKey, challengeBytes, signature)
+	}) {
 		log.Println("Signature verification failed")
 		return
 	}
@@ -166,31 +345,85 @@ func (s *server) Authentication(ctx context.Context, req *pb.AuthenticationReque
 	return &pb.AuthenticationResponse{Authenticated: true, Token: token}, nil
 }
 
-// UniqueKeyLogin logs in a user using the unique key thst is generated when the server is started,
-// and returns a JWT token if the login is successful.
-// This is used for the initial login when the server is started.
+// UniqueKeyLogin bootstraps the server with the unique key that is generated
+// when a server that was not bootstrapped starts, and returns a JWT token if the
+// login is successful.
+//
+// The login creates the user in the request with the given public key, or
+// replaces every public key of the user if it exists, and makes them an
+// administrator. The bootstrap is then recorded as completed, which disables
+// the unique key until the bootstrap is reset with `ophelia-ci-server admin
+// reset`.
 //
 // Parameters:
 //   - ctx: The context for the request, which carries deadlines, cancellation signals,
 //     and other request-scoped values.
-//   - req: The request containing the unique key.
+//   - req: The request containing the unique key, and the username and public key
+//     of the administrator.
 //
 // Returns:
-//   - *pb.AuthenticationResponse: The response containing the generated JWT token
-//     if the login is successful, or an error if the login fails.
+//   - *pb.AuthenticationResponse: The response containing the JWT token of the
+//     administrator if the login is successful, or an error if the login fails.
 func (s *server) UniqueKeyLogin(ctx context.Context, req *pb.UniqueKeyLoginRequest) (*pb.AuthenticationResponse, error) {
-	log.Printf("UniqueKeyLogin with request: %v", req)
-	if uniqueKey != "" && req.UniqueKey == uniqueKey {
-		token, err := generateJWT(req.UniqueKey, uniqueKeyExpirationDays)
-		if err != nil {
-			log.Println("Error generating JWT:", err)
-			return &pb.AuthenticationResponse{Authenticated: false}, err
-		}
-		uniqueKey = ""
-		return &pb.AuthenticationResponse{Authenticated: true, Token: token}, nil
+	// The request is not logged, as the unique key must never reach the logs.
+	log.Println("UniqueKeyLogin attempt")
+	bootstrapMutex.Lock()
+	defer bootstrapMutex.Unlock()
+	if uniqueKey == "" || subtle.ConstantTimeCompare([]byte(req.UniqueKey), []byte(uniqueKey)) != 1 {
+		log.Println("Invalid unique key")
+		return &pb.AuthenticationResponse{Authenticated: false}, nil
+	}
+	if req.Username == "" {
+		return nil, status.Error(codes.InvalidArgument, "the username of the administrator is required")
 	}
-	log.Println("Invalid unique key")
-	return &pb.AuthenticationResponse{Authenticated: false}, nil
+	key, err := store.NewUserKey(req.PublicKey, "")
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
+
+	user, err := s.userStore.GetUserByUsername(req.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		user, err = s.userStore.CreateUser(&pb.CreateUserRequest{Username: req.Username, PublicKey: req.PublicKey})
+	} else if err == nil {
+		user, err = s.userStore.UpdateUser(&pb.UpdateUserRequest{Id: user.Id, Username: user.Username, PublicKey: req.PublicKey})
+	}
+	if err != nil {
+		log.Println("Error saving administrator:", err)
+		return nil, status.Error(codes.Internal, "failed to save the administrator")
+	}
+	if err := s.userKeyStore.ReplaceKeys(user.Id, key, req.PublicKey); err != nil {
+		return nil, status.Error(codes.Internal, "failed to save the key of the administrator")
+	}
+	if err := s.roleStore.SetUserRole(user.Id, pb.Role_ADMIN); err != nil {
+		return nil, status.Error(codes.Internal, "failed to make the user an administrator")
+	}
+	if err := s.bootstrapStore.CompleteBootstrap(user.Username); err != nil && !errors.Is(err, store.ErrBootstrapped) {
+		return nil, status.Error(codes.Internal, "failed to record the bootstrap")
+	}
+	uniqueKey = ""
+	log.Printf("Bootstrapped the server with administrator %v", user.Username)
+
+	token, err := generateJWT(user.Username, LoadConfig().Server.ExpirationTime)
+	if err != nil {
+		log.Println("Error generating JWT:", err)
+		return &pb.AuthenticationResponse{Authenticated: false}, err
+	}
+	return &pb.AuthenticationResponse{Authenticated: true, Token: token}, nil
+}
+
+// issueUniqueKey generates the unique key that creates the first administrator,
+// unless the server was already bootstrapped, in which case the unique key
+// login stays disabled.
+//
+// Returns:
+// - string: The unique key, or an empty string if the server was bootstrapped.
+// - error: An error if the bootstrap cannot be read.
+func issueUniqueKey(bootstrapStore store.BootstrapStore) (string, error) {
+	bootstrapped, err := bootstrapStore.Bootstrapped()
+	if err != nil || bootstrapped {
+		return "", err
+	}
+	return randomKey(), nil
 }
 
 // verifySignature verifies the signature of a challenge using the stored public key.
@@ -239,6 +472,25 @@ func generateJWT(username string, expirationDays int) (string, error) {
 	return token.SignedString([]byte(jwtSecret))
 }
 
+// generateRunnerJWT generates a JWT token for the runner with the given ID that
+// expires in the given number of days. Runner tokens are only accepted by the
+// runner service.
+//
+// Parameters:
+// - runnerID: The ID of the runner for which the token is to be generated.
+// - expirationDays: The number of days until the token expires.
+//
+// Returns:
+// - string: The generated JWT token.
+// - error: An error if the token generation fails.
+func generateRunnerJWT(runnerID string, expirationDays int) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"runner": runnerID,
+		"exp":    time.Now().Add(time.Hour * 24 * time.Duration(expirationDays)).Unix(),
+	})
+	return token.SignedString([]byte(jwtSecret))
+}
+
 // extractTokenFromContext extracts the JWT token from the authorization header of the given context.
 //
 // Parameters:
@@ -300,14 +552,57 @@ func extractAndVerifyJWT(ctx context.Context) (username string, err error) {
 	if !ok {
 		return "", fmt.Errorf("no token found")
 	}
-	log.Println("Token found:", tokenString)
+	return verifyUserJWT(tokenString)
+}
+
+// verifyUserJWT verifies a user JWT token and returns the username if the token
+// is valid. Runner tokens are rejected.
+//
+// Parameters:
+// - tokenString: The JWT token to be verified.
+//
+// Returns:
+// - string: The username if the token is valid, or an empty string otherwise.
+// - error: An error if the token is invalid.
+func verifyUserJWT(tokenString string) (username string, err error) {
 	jwtMapClaims, err := verifyJWT(tokenString)
 	if err != nil {
 		return
 	}
-	username, ok = jwtMapClaims["username"].(string)
+	username, ok := jwtMapClaims["username"].(string)
+	if !ok {
+		err = fmt.Errorf("invalid token")
+	}
+	return
+}
+
+// extractAndVerifyRunnerJWT extracts a runner JWT token from the authorization header
+// of the given context, verifies it, and returns the ID of the runner if the token is valid.
+//
+// Parameters:
+// - ctx: The context for which the token is to be extracted.
+//
+// Returns:
+// - string: The ID of the runner if the token is valid, or an empty string otherwise.
+// - error: An error if the token is invalid or missing.
+func extractAndVerifyRunnerJWT(ctx context.Context) (runnerID string, err error) {
+	jwtMapClaims, err := extractAndVerifyClaims(ctx)
+	if err != nil {
+		return
+	}
+	runnerID, ok := jwtMapClaims["runner"].(string)
 	if !ok {
 		err = fmt.Errorf("invalid token")
 	}
 	return
 }
+
+// extractAndVerifyClaims extracts a JWT token from the authorization header of the
+// given context, verifies it, and returns its claims.
+func extractAndVerifyClaims(ctx context.Context) (jwt.MapClaims, error) {
+	tokenString, ok := extractTokenFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no token found")
+	}
+	return verifyJWT(tokenString)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// newUserServer returns a server with the stores of users, their keys and
+// roles, and the bootstrap, in a new SQLite database.
+func newUserServer(t *testing.T) *server {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "ophelia.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &server{
+		userStore:      store.NewSQLUserStore(db),
+		userKeyStore:   store.NewSQLUserKeyStore(db),
+		roleStore:      store.NewSQLRoleStore(db),
+		bootstrapStore: store.NewSQLBootstrapStore(db),
+	}
+}
+
+// newPublicKey returns a new public key in the authorized_keys format.
+func newPublicKey(t *testing.T, comment string) string {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))) + " " + comment
+}
+
+// setUniqueKey sets the unique key of the server for a test.
+func setUniqueKey(t *testing.T, key string) {
+	t.Helper()
+	previous := uniqueKey
+	uniqueKey = key
+	t.Cleanup(func() { uniqueKey = previous })
+}
+
+func TestUniqueKeyLoginRequiresUsernameAndPublicKey(t *testing.T) {
+	s := newUserServer(t)
+	setUniqueKey(t, "unique")
+
+	tests := []struct {
+		name string
+		req  *pb.UniqueKeyLoginRequest
+	}{
+		{name: "Without username", req: &pb.UniqueKeyLoginRequest{UniqueKey: "unique", PublicKey: newPublicKey(t, "alice@laptop")}},
+		{name: "Without public key", req: &pb.UniqueKeyLoginRequest{UniqueKey: "unique", Username: "alice"}},
+		{name: "Invalid public key", req: &pb.UniqueKeyLoginRequest{UniqueKey: "unique", Username: "alice", PublicKey: "ssh-ed25519 invalid"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := s.UniqueKeyLogin(context.Background(), test.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+
+	if bootstrapped, err := s.bootstrapStore.Bootstrapped(); err != nil || bootstrapped {
+		t.Fatalf("expected invalid requests not to bootstrap the server, got %v (%v)", bootstrapped, err)
+	}
+	if uniqueKey != "unique" {
+		t.Fatal("expected invalid requests not to use the unique key")
+	}
+}
+
+func TestUniqueKeyLoginCreatesAdministratorOnce(t *testing.T) {
+	s := newUserServer(t)
+	setUniqueKey(t, "unique")
+	publicKey := newPublicKey(t, "alice@laptop")
+
+	response, err := s.UniqueKeyLogin(context.Background(), &pb.UniqueKeyLoginRequest{UniqueKey: "wrong", Username: "mallory", PublicKey: publicKey})
+	if err != nil || response.Authenticated {
+		t.Fatalf("expected a wrong unique key to be rejected, got %v (%v)", response, err)
+	}
+
+	response, err = s.UniqueKeyLogin(context.Background(), &pb.UniqueKeyLoginRequest{UniqueKey: "unique", Username: "alice", PublicKey: publicKey})
+	if err != nil || !response.Authenticated || response.Token == "" {
+		t.Fatalf("expected the unique key to log in, got %v (%v)", response, err)
+	}
+	if role, err := s.userRole("alice", ""); err != nil || role != pb.Role_ADMIN {
+		t.Errorf("expected alice to be an administrator, got %v (%v)", role, err)
+	}
+	user, err := s.userStore.GetUserByUsername("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys, err := s.userKeyStore.ActivePublicKeys(user.Id); err != nil || !slices.Equal(keys, []string{publicKey}) {
+		t.Errorf("expected the public key of alice, got %v (%v)", keys, err)
+	}
+	if bootstrapped, err := s.bootstrapStore.Bootstrapped(); err != nil || !bootstrapped {
+		t.Errorf("expected the server to be bootstrapped, got %v (%v)", bootstrapped, err)
+	}
+
+	response, err = s.UniqueKeyLogin(context.Background(), &pb.UniqueKeyLoginRequest{UniqueKey: "unique", Username: "mallory", PublicKey: newPublicKey(t, "mallory")})
+	if err != nil || response.Authenticated {
+		t.Fatalf("expected the unique key to be used once, got %v (%v)", response, err)
+	}
+	if _, err := s.userStore.GetUserByUsername("mallory"); err == nil {
+		t.Error("expected no user to be created by a second login")
+	}
+}
+
+func TestUniqueKeyLoginRestoresAdministrator(t *testing.T) {
+	s := newUserServer(t)
+	setUniqueKey(t, "unique")
+	user, err := s.userStore.CreateUser(&pb.CreateUserRequest{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lostKey := newPublicKey(t, "alice@old-laptop")
+	key, err := store.NewUserKey(lostKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.userKeyStore.AddKey(user.Id, key, lostKey); err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey := newPublicKey(t, "alice@laptop")
+	response, err := s.UniqueKeyLogin(context.Background(), &pb.UniqueKeyLoginRequest{UniqueKey: "unique", Username: "alice", PublicKey: publicKey})
+	if err != nil || !response.Authenticated {
+		t.Fatalf("expected the unique key to log in, got %v (%v)", response, err)
+	}
+	if keys, err := s.userKeyStore.ActivePublicKeys(user.Id); err != nil || !slices.Equal(keys, []string{publicKey}) {
+		t.Errorf("expected the lost key to be replaced, got %v (%v)", keys, err)
+	}
+	if role, err := s.userRole("alice", ""); err != nil || role != pb.Role_ADMIN {
+		t.Errorf("expected alice to be an administrator, got %v (%v)", role, err)
+	}
+}
+
+func TestUniqueKeyLoginRejectedAfterBootstrap(t *testing.T) {
+	s := newUserServer(t)
+	if err := s.bootstrapStore.CompleteBootstrap("alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := issueUniqueKey(s.bootstrapStore)
+	if err != nil || key != "" {
+		t.Fatalf("expected no unique key for a bootstrapped server, got %q (%v)", key, err)
+	}
+	setUniqueKey(t, key)
+	for _, attempt := range []string{"", "unique"} {
+		response, err := s.UniqueKeyLogin(context.Background(), &pb.UniqueKeyLoginRequest{UniqueKey: attempt, Username: "mallory", PublicKey: newPublicKey(t, "mallory")})
+		if err != nil || response.Authenticated {
+			t.Errorf("expected the unique key %q to be rejected, got %v (%v)", attempt, response, err)
+		}
+	}
+
+	if err := s.bootstrapStore.ResetBootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if key, err := issueUniqueKey(s.bootstrapStore); err != nil || key == "" {
+		t.Fatalf("expected a unique key once the bootstrap is reset, got %q (%v)", key, err)
+	}
+}
+
+// createHookRepository creates a bare repository named name in the home path
+// of the server, and returns its hook secret.
+func createHookRepository(t *testing.T, homePath, name string) string {
+	t.Helper()
+	repoPath := filepath.Join(homePath, name+".git")
+	if output, err := exec.Command("git", "init", "--bare", repoPath).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create repository: %v\n%s", err, output)
+	}
+	if err := git.UpdatePostReceiveHook(repoPath); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := git.HookSecret(repoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+func TestAuthenticateHook(t *testing.T) {
+	homePath := t.TempDir()
+	t.Setenv("OPHELIA_CI_FROM_IMAGE", "1")
+	t.Setenv("APP_OPHELIA_CI_SERVER_HOME_PATH", homePath)
+	secret := createHookRepository(t, homePath, "project")
+	otherSecret := createHookRepository(t, homePath, "other")
+
+	tests := []struct {
+		name          string
+		authorization string
+		repository    string
+		authenticated bool
+	}{
+		{name: "Hook secret", authorization: "Bearer " + secret, repository: "project", authenticated: true},
+		{name: "Wrong secret", authorization: "Bearer wrong", repository: "project"},
+		{name: "Missing secret", repository: "project"},
+		{name: "Secret of another repository", authorization: "Bearer " + otherSecret, repository: "project"},
+		{name: "Missing repository", authorization: "Bearer " + secret, repository: "missing"},
+		{name: "Repository outside the home path", authorization: "Bearer " + secret, repository: "../project"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", test.authorization))
+			}
+			req := &pb.CommitRequest{Repository: test.repository, Pusher: "alice"}
+
+			ctx, err := authenticateHook(ctx, "/signal.Signals/CommitSignal", req)
+			if !test.authenticated {
+				if status.Code(err) != codes.Unauthenticated {
+					t.Errorf("expected Unauthenticated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repository := hookFromContext(ctx); repository != test.repository {
+				t.Errorf("expected the call to be made by the hook of %v, got %q", test.repository, repository)
+			}
+		})
+	}
+}
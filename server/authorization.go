@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/permission"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// authorize checks that the user authenticated in ctx has the role needed to
+// call a method with req, as given by the permission rules.
+//
+// The role of the user is their global role or, for the methods called for a
+// repository, the highest of their global role and of the role granted to them
+// on the repository. Calls not made with a user token, such as the calls of
+// runners and post-receive hooks, are not checked.
+//
+// Returns:
+// - error: A PermissionDenied error if the user may not call the method.
+func (s *server) authorize(ctx context.Context, method string, req any) error {
+	username := usernameFromContext(ctx)
+	if username == "" {
+		return nil
+	}
+	rule, ok := permission.ForMethod(method)
+	if !ok {
+		log.Printf("Denied unknown method %v to %v", method, username)
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed", method)
+	}
+
+	repositoryId := ""
+	if rule.Repository {
+		repositoryId = s.requestRepository(req)
+	}
+	role, err := s.userRole(username, repositoryId)
+	if err != nil {
+		return status.Error(codes.Internal, "failed to read roles")
+	}
+	if !permission.Allows(role, rule.Role) {
+		log.Printf("Denied %v to %v with role %v", method, username, role)
+		return status.Errorf(codes.PermissionDenied, "%s needs the %v role", method, rule.Role)
+	}
+	return nil
+}
+
+// authorizeGit checks that a user may run a Git service, such as
+// git-receive-pack, on the named repository through the Git transports.
+//
+// Returns:
+// - error: An error if the repository does not exist or the user may not run
+// the service on it.
+func (s *server) authorizeGit(username, name, service string) error {
+	rule, ok := permission.ForGitService(service)
+	if !ok {
+		return fmt.Errorf("%s is not allowed", service)
+	}
+	repo, err := s.repositorieStore.GetRepositoryByName(name)
+	if err != nil {
+		return fmt.Errorf("repository %s not found", name)
+	}
+	role, err := s.userRole(username, repo.Id)
+	if err != nil {
+		return err
+	}
+	if !permission.Allows(role, rule.Role) {
+		return fmt.Errorf("%s on %s needs the %v role", service, name, rule.Role)
+	}
+	return nil
+}
+
+// userRole returns the role of a user, on a repository if repositoryId is not
+// empty, or globally otherwise. Unknown users have the NONE role.
+func (s *server) userRole(username, repositoryId string) (pb.Role, error) {
+	user, err := s.userStore.GetUserByUsername(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pb.Role_NONE, nil
+	}
+	if err != nil {
+		return pb.Role_NONE, err
+	}
+
+	global, err := s.roleStore.GetUserRole(user.Id)
+	if err != nil || repositoryId == "" {
+		return global, err
+	}
+	repository, err := s.roleStore.GetRepositoryRole(repositoryId, user.Id)
+	if err != nil {
+		return pb.Role_NONE, err
+	}
+	return permission.Effective(global, repository), nil
+}
+
+// canRead reports whether the user authenticated in ctx may read a
+// repository, so that listings only show the repositories they can see.
+func (s *server) canRead(ctx context.Context, repositoryId string) bool {
+	username := usernameFromContext(ctx)
+	if username == "" {
+		return true
+	}
+	role, err := s.userRole(username, repositoryId)
+	return err == nil && permission.Allows(role, pb.Role_READER)
+}
+
+// requestRepository returns the ID of the repository a request is for, looking
+// up the repository by name or the build the request names if needed.
+//
+// Returns an empty string if the request is not for a single existing
+// repository, in which case only the global role of the user counts.
+func (s *server) requestRepository(req any) string {
+	switch req := req.(type) {
+	case *pb.GetRepositoryRequest:
+		if req.Id != "" {
+			return req.Id
+		}
+		return s.repositoryId(req.Name)
+	case *pb.UpdateRepositoryRequest:
+		return req.Id
+	case *pb.DeleteRepositoryRequest:
+		return req.Id
+	case *pb.ListBuildsRequest:
+		if req.RepositoryId != "" {
+			return req.RepositoryId
+		}
+		return s.repositoryId(req.Repository)
+	case *pb.GetBuildRequest:
+		return s.buildRepositoryId(req.Id)
+	case *pb.CancelBuildRequest:
+		return s.buildRepositoryId(req.Id)
+	case *pb.RetryBuildRequest:
+		return s.buildRepositoryId(req.Id)
+	case *pb.StreamBuildLogsRequest:
+		return s.buildRepositoryId(req.Id)
+	case *pb.DownloadArtifactRequest:
+		return s.buildRepositoryId(req.BuildId)
+	case repositoryRequest:
+		return s.repositoryId(req.GetRepository())
+	}
+	return ""
+}
+
+// repositoryId returns the ID of the repository with the given name, or an
+// empty string if there is none.
+func (s *server) repositoryId(name string) string {
+	if name == "" {
+		return ""
+	}
+	repo, err := s.repositorieStore.GetRepositoryByName(name)
+	if err != nil {
+		return ""
+	}
+	return repo.Id
+}
+
+// buildRepositoryId returns the ID of the repository of a build, or an empty
+// string if there is no such build.
+func (s *server) buildRepositoryId(buildId string) string {
+	build, err := s.buildStore.GetBuild(buildId)
+	if err != nil {
+		return ""
+	}
+	return build.RepositoryId
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/buildlog"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/dispatch"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/executor"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+)
+
+// enqueueBuild records a new build of a repository revision, with one job per
+// pipeline job, and adds it to the build queue.
+//
+// If coalesce is true, the builds of the same branch still waiting in the queue
+// are cancelled, since the new build supersedes them.
+//
+// Parameters:
+//   - repo: The repository to be built.
+//   - build: The build information, with the commit hash, branch, tag, trigger user
+//     and resolved parameters.
+//   - definition: The pipeline loaded from the repository at the build commit.
+//   - coalesce: Whether to cancel the queued builds of the same branch.
+//
+// Returns:
+//   - *pb.BuildResponse: The queued build, with its jobs.
+//   - error: An error if there is an issue recording the build.
+func (s *server) enqueueBuild(repo *pb.RepositoryResponse, build *pb.BuildResponse, definition *pipeline.Pipeline, coalesce bool) (*pb.BuildResponse, error) {
+	if coalesce && build.Branch != "" {
+		s.cancelQueuedBuilds(repo, build.Branch)
+	}
+
+	build.RepositoryId = repo.Id
+	created, err := s.buildStore.CreateBuild(build)
+	if err != nil {
+		log.Printf("Error creating build: %v", err)
+		return nil, err
+	}
+
+	for _, stage := range definition.Stages {
+		for _, job := range stage.Jobs {
+			record := &pb.JobResponse{BuildId: created.Id, Stage: stage.Name, Name: job.Name}
+			if job.Cell != nil {
+				record.Matrix = job.Cell.Values
+			}
+			createdJob, err := s.buildStore.CreateJob(record)
+			if err != nil {
+				log.Printf("Error creating job: %v", err)
+				s.buildStore.UpdateBuildStatus(created.Id, pb.BuildStatus_FAILED)
+				return nil, err
+			}
+			created.Jobs = append(created.Jobs, createdJob)
+		}
+	}
+
+	if _, err := s.buildLogs.Open(created.Id); err != nil {
+		log.Printf("Error opening build log: %v", err)
+		s.finishBuild(created, pb.BuildStatus_FAILED)
+		return nil, err
+	}
+
+	s.queue.Notify()
+	return created, nil
+}
+
+// cancelQueuedBuilds cancels the queued builds of a repository branch.
+func (s *server) cancelQueuedBuilds(repo *pb.RepositoryResponse, branch string) {
+	queued, err := s.buildStore.ListBuildsByStatus(pb.BuildStatus_QUEUED)
+	if err != nil {
+		log.Printf("Error listing queued builds: %v", err)
+		return
+	}
+	for _, build := range queued.Builds {
+		if build.RepositoryId == repo.Id && build.Branch == branch && build.Tag == "" {
+			log.Printf("Build %v of %v superseded by a newer commit", build.Id, branch)
+			s.finishBuild(build, pb.BuildStatus_CANCELLED)
+		}
+	}
+}
+
+// executeBuild runs a build taken from the build queue.
+//
+// The build is claimed first, so that builds cancelled while queued are
+// skipped, and builds cancelled after they were dispatched are finished. The repository and the pipeline at the build commit are then loaded
+// again, since queued builds may have been reloaded after a server restart.
+//
+// Parameters:
+//   - ctx: The context of the build, cancelled by CancelBuild.
+//   - build: The queued build to run.
+func (s *server) executeBuild(ctx context.Context, build *pb.BuildResponse) {
+	claimed, err := s.buildStore.ClaimBuild(build.Id)
+	if err != nil || !claimed {
+		// A build cancelled after it was dispatched is left by CancelBuild
+		// to be finished here, since it was already running in the queue.
+		if current, err := s.buildStore.GetBuild(build.Id); err == nil && current.Status == pb.BuildStatus_CANCELLED {
+			s.finishBuild(current, pb.BuildStatus_CANCELLED)
+		}
+		return
+	}
+
+	buildLog, err := s.buildLogs.Open(build.Id)
+	if err != nil {
+		log.Printf("Error opening build log: %v", err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+
+	repo, err := s.repositorieStore.GetRepository(build.RepositoryId)
+	if err != nil {
+		log.Printf("Error getting repository of build %v: %v", build.Id, err)
+		fmt.Fprintf(buildLog.Writer(""), "Failed to get repository: %v\n", err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+
+	definition, err := pipeline.Load(getRepoPath(repo.Name), build.CommitHash)
+	if err != nil {
+		log.Printf("Error loading pipeline of build %v: %v", build.Id, err)
+		fmt.Fprintf(buildLog.Writer(""), "Failed to load pipeline: %v\n", err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+
+	build.Jobs, err = s.buildStore.ListJobs(build.Id)
+	if err != nil {
+		log.Printf("Error listing jobs of build %v: %v", build.Id, err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+
+	build.Parameters, err = s.buildStore.ListParameters(build.Id)
+	if err != nil {
+		log.Printf("Error listing parameters of build %v: %v", build.Id, err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+
+	s.runBuild(ctx, repo, build, definition, buildLog)
+}
+
+// recoverBuilds marks the builds left running by a previous server process as
+// failed, since their jobs did not survive the restart, and removes the
+// workspaces they left behind. Queued builds are left untouched and are
+// dispatched again by the build queue.
+func (s *server) recoverBuilds() {
+	running, err := s.buildStore.ListBuildsByStatus(pb.BuildStatus_RUNNING)
+	if err != nil {
+		log.Printf("Error listing running builds: %v", err)
+		return
+	}
+	for _, build := range running.Builds {
+		log.Printf("Build %v was interrupted by a server restart", build.Id)
+		if buildLog, err := s.buildLogs.Open(build.Id); err == nil {
+			fmt.Fprintln(buildLog.Writer(""), "Build interrupted by a server restart")
+		}
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+	}
+
+	if err := s.executor.Cleanup(s.executor.WorkspaceRoot); err != nil {
+		log.Printf("Error removing workspaces: %v", err)
+	}
+}
+
+// runBuild runs the jobs of a build, handing each job to the runners through
+// the dispatcher as soon as the jobs it waits for succeeded, and recording the
+// status of the build and of each job as it goes.
+//
+// Jobs that do not depend on each other run in parallel. Jobs whose runs-on
+// labels no runner has fail without being queued, and the jobs waiting for a
+// job that did not succeed are marked as skipped. When a job expanded from a
+// fail-fast matrix fails, the other jobs of the matrix are cancelled. If ctx
+// is cancelled, the running jobs are stopped, the jobs that were not started
+// are marked as cancelled and so is the build. A build running longer than
+// the timeout of its pipeline is stopped the same way, and marked as timed out.
+//
+// The secrets of the repository are exposed to the jobs, and their values are
+// redacted from the build log.
+//
+// Parameters:
+//   - ctx: The context of the build, cancelled by CancelBuild.
+//   - repo: The repository being built.
+//   - build: The build to run, with its jobs.
+//   - definition: The pipeline loaded from the repository at the build commit.
+//   - buildLog: The log receiving the output of the build.
+func (s *server) runBuild(ctx context.Context, repo *pb.RepositoryResponse, build *pb.BuildResponse, definition *pipeline.Pipeline, buildLog *buildlog.Log) {
+	log.Printf("Starting build %v for %v at %v", build.Id, repo.Name, build.CommitHash)
+
+	jobs := definition.Jobs()
+	records, err := jobRecords(jobs, build.Jobs)
+	if err != nil {
+		log.Printf("Error matching the jobs of build %v: %v", build.Id, err)
+		fmt.Fprintf(buildLog.Writer(""), "Failed to match the jobs of the pipeline: %v\n", err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+
+	secrets, err := s.buildSecrets(repo.Id)
+	if err != nil {
+		log.Printf("Error loading secrets of build %v: %v", build.Id, err)
+		fmt.Fprintf(buildLog.Writer(""), "Failed to load the secrets of %s: %v\n", repo.Name, err)
+		s.finishBuild(build, pb.BuildStatus_FAILED)
+		return
+	}
+	buildLog.RedactValues(slices.Collect(maps.Values(secrets))...)
+
+	if definition.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, definition.Timeout)
+		defer cancel()
+	}
+
+	executorBuild := executor.Build{
+		ID:             build.Id,
+		Repository:     repo.Name,
+		RepositoryPath: getRepoPath(repo.Name),
+		Revision:       build.CommitHash,
+		Branch:         build.Branch,
+		Tag:            build.Tag,
+		Parameters:     build.Parameters,
+		Secrets:        secrets,
+		Pipeline:       definition,
+	}
+
+	type jobResult struct {
+		job    pipeline.Job
+		status pb.BuildStatus
+	}
+	dependencies := definition.Dependencies()
+	statuses := make(map[string]pb.BuildStatus)
+	started := make(map[string]bool)
+	results := make(chan jobResult)
+	running := 0
+
+	// The jobs of a fail-fast matrix share a context, cancelled when one fails.
+	matrixContexts := make(map[string]context.Context)
+	matrixCancels := make(map[string]context.CancelFunc)
+	for _, job := range jobs {
+		if job.Cell != nil && job.Cell.FailFast && matrixContexts[job.Cell.Job] == nil {
+			matrixContexts[job.Cell.Job], matrixCancels[job.Cell.Job] = context.WithCancel(ctx)
+			defer matrixCancels[job.Cell.Job]()
+		}
+	}
+	jobContext := func(job pipeline.Job) context.Context {
+		if job.Cell != nil && job.Cell.FailFast {
+			return matrixContexts[job.Cell.Job]
+		}
+		return ctx
+	}
+
+	for {
+		for progress := ctx.Err() == nil; progress; {
+			progress = false
+			for _, job := range jobs {
+				if started[job.Name] || ctx.Err() != nil {
+					continue
+				}
+				ready, skip := true, false
+				for _, dependency := range dependencies[job.Name] {
+					status, finished := statuses[dependency]
+					ready = ready && finished
+					skip = skip || (finished && status != pb.BuildStatus_SUCCESS)
+				}
+				if !ready {
+					continue
+				}
+
+				started[job.Name] = true
+				record := records[job.Name]
+				jobCtx := jobContext(job)
+				if skip || jobCtx.Err() != nil {
+					status := pb.BuildStatus_SKIPPED
+					output := buildLog.Writer(job.Name)
+					if skip {
+						fmt.Fprintf(output, "Job skipped: a job it needs did not succeed\n")
+					} else {
+						status = pb.BuildStatus_CANCELLED
+						fmt.Fprintf(output, "Job cancelled: another job of matrix %s failed\n", job.Cell.Job)
+					}
+					output.Close()
+					s.buildStore.UpdateJobStatus(record.Id, status, 0)
+					statuses[job.Name] = status
+					progress = true
+					continue
+				}
+
+				running++
+				go func() {
+					results <- jobResult{job, s.runJob(jobCtx, executorBuild, job, record, buildLog)}
+				}()
+			}
+		}
+
+		if running == 0 {
+			break
+		}
+		result := <-results
+		running--
+		statuses[result.job.Name] = result.status
+		if cell := result.job.Cell; cell != nil && cell.FailFast && failed(result.status) {
+			log.Printf("Job %v of build %v failed, cancelling the other jobs of matrix %v", result.job.Name, build.Id, cell.Job)
+			matrixCancels[cell.Job]()
+		}
+	}
+
+	status := pb.BuildStatus_SUCCESS
+	for _, jobStatus := range statuses {
+		if failed(jobStatus) {
+			status = pb.BuildStatus_FAILED
+		}
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		fmt.Fprintf(buildLog.Writer(""), "Build timed out after %v\n", definition.Timeout)
+		status = pb.BuildStatus_TIMED_OUT
+	case ctx.Err() != nil:
+		status = pb.BuildStatus_CANCELLED
+	}
+	s.finishBuild(build, status)
+}
+
+// jobRecords matches the jobs of a pipeline with the stored jobs of a build,
+// by name and matrix values, since the stored jobs are listed in the order
+// they were recorded rather than in pipeline order.
+//
+// Parameters:
+//   - jobs: The jobs of the pipeline loaded at the build commit.
+//   - stored: The stored jobs of the build.
+//
+// Returns:
+//   - map[string]*pb.JobResponse: The stored job of each pipeline job, by name.
+//   - error: An error if a pipeline job has no stored job.
+func jobRecords(jobs []pipeline.Job, stored []*pb.JobResponse) (map[string]*pb.JobResponse, error) {
+	byKey := make(map[string]*pb.JobResponse, len(stored))
+	for _, record := range stored {
+		byKey[jobKey(record.Name, record.Matrix)] = record
+	}
+
+	records := make(map[string]*pb.JobResponse, len(jobs))
+	for _, job := range jobs {
+		var matrix map[string]string
+		if job.Cell != nil {
+			matrix = job.Cell.Values
+		}
+		record, ok := byKey[jobKey(job.Name, matrix)]
+		if !ok {
+			return nil, fmt.Errorf("job %s was not recorded with the build", job.Name)
+		}
+		records[job.Name] = record
+	}
+	return records, nil
+}
+
+// jobKey identifies a job of a build by its name and matrix values.
+func jobKey(name string, matrix map[string]string) string {
+	key := name
+	for _, axis := range slices.Sorted(maps.Keys(matrix)) {
+		key += "\x00" + axis + "=" + matrix[axis]
+	}
+	return key
+}
+
+// failed reports whether a job with the given status failed, including by
+// running out of time.
+func failed(status pb.BuildStatus) bool {
+	return status == pb.BuildStatus_FAILED || status == pb.BuildStatus_TIMED_OUT
+}
+
+// runJob hands a job of a build to the runners through the dispatcher, waits
+// for it to finish and records its status.
+//
+// Parameters:
+//   - ctx: The context of the build, cancelled by CancelBuild.
+//   - build: The build the job belongs to.
+//   - job: The pipeline definition of the job.
+//   - record: The stored job.
+//   - buildLog: The log receiving the output of the build.
+//
+// Returns:
+//   - pb.BuildStatus: The final status of the job, TIMED_OUT if the job, one
+//     of its steps or the build ran out of time.
+func (s *server) runJob(ctx context.Context, build executor.Build, job pipeline.Job, record *pb.JobResponse, buildLog *buildlog.Log) pb.BuildStatus {
+	output := buildLog.Writer(job.Name)
+	defer output.Close()
+
+	var result dispatch.Result
+	if err := s.checkSchedulable(job.RunsOn); err != nil {
+		log.Printf("Job %v of build %v cannot be scheduled: %v", job.Name, build.ID, err)
+		fmt.Fprintf(output, "Job cannot be scheduled: %v\n", err)
+		result.ExitCode = -1
+	} else {
+		result, _ = s.dispatcher.Submit(ctx, &dispatch.Job{
+			ID:         record.Id,
+			Build:      build,
+			Definition: job,
+			Output:     output,
+		})
+	}
+
+	status := pb.BuildStatus_SUCCESS
+	switch {
+	case result.TimedOut || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		status = pb.BuildStatus_TIMED_OUT
+	case ctx.Err() != nil:
+		status = pb.BuildStatus_CANCELLED
+	case !result.Success:
+		status = pb.BuildStatus_FAILED
+	}
+	s.buildStore.UpdateJobStatus(record.Id, status, result.ExitCode)
+	return status
+}
+
+// finishBuild records the final status of a build, marks the jobs that never
+// ran as cancelled and closes the build log.
+func (s *server) finishBuild(build *pb.BuildResponse, status pb.BuildStatus) {
+	if err := s.buildLogs.Close(build.Id); err != nil {
+		log.Printf("Error closing log of build %v: %v", build.Id, err)
+	}
+
+	jobs, err := s.buildStore.ListJobs(build.Id)
+	if err != nil {
+		log.Printf("Error listing jobs of build %v: %v", build.Id, err)
+	}
+	for _, job := range jobs {
+		if !store.IsFinalStatus(job.Status) {
+			s.buildStore.UpdateJobStatus(job.Id, pb.BuildStatus_CANCELLED, 0)
+		}
+	}
+
+	finished, err := s.buildStore.UpdateBuildStatus(build.Id, status)
+	if err != nil {
+		log.Printf("Error finishing build %v: %v", build.Id, err)
+		return
+	}
+	if !finished {
+		log.Printf("Build %v had already finished, keeping its status", build.Id)
+		return
+	}
+	log.Printf("Build %v finished with status %v", build.Id, status)
+}
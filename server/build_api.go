@@ -0,0 +1,288 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"slices"
+	"strings"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/buildlog"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListBuilds lists the builds of a repository, newest first.
+//
+// The request may contain either the ID or the name of the repository whose
+// builds are listed. If both are empty, the builds of every repository are listed.
+//
+// The response will contain the list of builds, without their jobs.
+func (s *server) ListBuilds(ctx context.Context, req *pb.ListBuildsRequest) (*pb.ListBuildsResponse, error) {
+	log.Printf("Listing builds with request: %v", req)
+	repositoryId := req.RepositoryId
+	if repositoryId == "" && req.Repository != "" {
+		repo, err := s.repositorieStore.GetRepositoryByName(req.Repository)
+		if err != nil {
+			log.Printf("Error getting repository: %v", err)
+			return nil, err
+		}
+		repositoryId = repo.Id
+	}
+
+	builds, err := s.buildStore.ListBuilds(repositoryId)
+	if err != nil {
+		log.Printf("Error listing builds: %v", err)
+		return nil, err
+	}
+	return builds, nil
+}
+
+// GetBuild gets a build by its ID.
+//
+// The request must contain the ID of the build.
+//
+// The response will contain the build information along with its jobs.
+func (s *server) GetBuild(ctx context.Context, req *pb.GetBuildRequest) (*pb.BuildResponse, error) {
+	log.Printf("Getting build with request: %v", req)
+	build, err := s.buildStore.GetBuild(req.Id)
+	if err != nil {
+		log.Printf("Error getting build: %v", err)
+		return nil, err
+	}
+	return build, nil
+}
+
+// CancelBuild cancels a queued or running build.
+//
+// The request must contain the ID of the build to be cancelled.
+// A queued build is removed from the build queue. The running steps of a
+// running build are sent SIGTERM, and killed if they are still running after
+// the grace period of the executor.
+//
+// The response will contain the cancelled build information.
+func (s *server) CancelBuild(ctx context.Context, req *pb.CancelBuildRequest) (*pb.BuildResponse, error) {
+	log.Printf("Cancelling build with request: %v", req)
+	build, err := s.buildStore.GetBuild(req.Id)
+	if err != nil {
+		log.Printf("Error getting build: %v", err)
+		return nil, err
+	}
+	if store.IsFinalStatus(build.Status) {
+		return nil, status.Errorf(codes.FailedPrecondition, "build %s already finished with status %v", build.Id, build.Status)
+	}
+
+	cancelled, err := s.buildStore.UpdateBuildStatus(build.Id, pb.BuildStatus_CANCELLED)
+	if err != nil {
+		log.Printf("Error cancelling build: %v", err)
+		return nil, err
+	}
+	if !cancelled {
+		return nil, status.Errorf(codes.FailedPrecondition, "build %s already finished", build.Id)
+	}
+	if !s.queue.Cancel(build.Id) {
+		// The build was still queued, so no runner will finish it.
+		s.finishBuild(build, pb.BuildStatus_CANCELLED)
+	}
+	return s.buildStore.GetBuild(build.Id)
+}
+
+// RetryBuild queues a new build of the same commit as an existing build.
+//
+// The request must contain the ID of the build to be retried.
+// The pipeline definition is read again from the repository at the build commit,
+// and the caller is recorded as the user who triggered the new build.
+//
+// The response will contain the new build information.
+func (s *server) RetryBuild(ctx context.Context, req *pb.RetryBuildRequest) (*pb.BuildResponse, error) {
+	log.Printf("Retrying build with request: %v", req)
+	build, err := s.buildStore.GetBuild(req.Id)
+	if err != nil {
+		log.Printf("Error getting build: %v", err)
+		return nil, err
+	}
+
+	repo, err := s.repositorieStore.GetRepository(build.RepositoryId)
+	if err != nil {
+		log.Printf("Error getting repository: %v", err)
+		return nil, err
+	}
+
+	definition, err := pipeline.Load(getRepoPath(repo.Name), build.CommitHash)
+	if err != nil {
+		log.Printf("Error loading pipeline: %v", err)
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to load pipeline: %v", err)
+	}
+
+	parameters, err := definition.ResolveParameters(build.Parameters)
+	if err != nil {
+		log.Printf("Error resolving parameters: %v", err)
+		return nil, status.Errorf(codes.FailedPrecondition, "invalid parameters: %v", err)
+	}
+
+	return s.enqueueBuild(repo, &pb.BuildResponse{
+		CommitHash:  build.CommitHash,
+		Branch:      build.Branch,
+		Tag:         build.Tag,
+		TriggerUser: usernameFromContext(ctx),
+		Parameters:  parameters,
+	}, definition, false)
+}
+
+// TriggerBuild queues a build of a repository revision, with user-supplied
+// parameters.
+//
+// The request must contain the name of the repository and the ref to be built,
+// which is resolved against the repository as a branch, a tag, a full ref name
+// or a commit hash, in that order. For compatibility, the branch is used when
+// no ref is given. The parameters are checked against the parameters declared
+// by the pipeline at the resolved commit, and the missing ones take their
+// default values. The caller is recorded as the user who triggered the build.
+//
+// The response will contain the new build information.
+func (s *server) TriggerBuild(ctx context.Context, req *pb.TriggerBuildRequest) (*pb.BuildResponse, error) {
+	log.Printf("Triggering build with request: %v", req)
+	repo, err := s.repositorieStore.GetRepositoryByName(req.Repository)
+	if err != nil {
+		log.Printf("Error getting repository: %v", err)
+		return nil, err
+	}
+
+	ref := cmp.Or(req.Ref, req.Branch)
+	if ref == "" {
+		return nil, status.Error(codes.InvalidArgument, "ref is required")
+	}
+	build, err := resolveRef(getRepoPath(repo.Name), ref)
+	if err != nil {
+		log.Printf("Error resolving ref: %v", err)
+		return nil, status.Errorf(codes.NotFound, "ref %s not found in %s", ref, repo.Name)
+	}
+
+	definition, err := pipeline.Load(getRepoPath(repo.Name), build.CommitHash)
+	if err != nil {
+		log.Printf("Error loading pipeline: %v", err)
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to load pipeline: %v", err)
+	}
+
+	build.Parameters, err = definition.ResolveParameters(req.Parameters)
+	if err != nil {
+		log.Printf("Error resolving parameters: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameters: %v", err)
+	}
+
+	build.TriggerUser = usernameFromContext(ctx)
+	return s.enqueueBuild(repo, build, definition, false)
+}
+
+// resolveRef resolves a ref of a repository to the commit to be built, along
+// with the branch or tag it names. The ref is tried as a branch, a tag, a full
+// ref name and a commit hash, in that order.
+func resolveRef(repoPath, ref string) (*pb.BuildResponse, error) {
+	if commitHash, err := git.ResolveRevision(repoPath, "refs/heads/"+ref); err == nil {
+		return &pb.BuildResponse{CommitHash: commitHash, Branch: ref}, nil
+	}
+	if commitHash, err := git.ResolveRevision(repoPath, "refs/tags/"+ref); err == nil {
+		return &pb.BuildResponse{CommitHash: commitHash, Tag: ref}, nil
+	}
+
+	commitHash, err := git.ResolveRevision(repoPath, ref)
+	if err != nil {
+		return nil, err
+	}
+	build := &pb.BuildResponse{CommitHash: commitHash}
+	if branch, ok := strings.CutPrefix(ref, "refs/heads/"); ok {
+		build.Branch = branch
+	} else if tag, ok := strings.CutPrefix(ref, "refs/tags/"); ok {
+		build.Tag = tag
+	}
+	return build, nil
+}
+
+// StreamBuildLogs streams the output lines of a build.
+//
+// The request must contain the ID of the build, and may contain the offset of
+// the first line to be sent, so that clients can resume an interrupted stream.
+// If follow is set, the stream stays open and sends new lines as they are
+// produced until the build finishes.
+//
+// The response is a stream of log lines, each with its offset and job name.
+func (s *server) StreamBuildLogs(req *pb.StreamBuildLogsRequest, stream pb.BuildService_StreamBuildLogsServer) error {
+	log.Printf("Streaming build logs with request: %v", req)
+	if _, err := s.buildStore.GetBuild(req.Id); err != nil {
+		log.Printf("Error getting build: %v", err)
+		return err
+	}
+
+	err := s.buildLogs.Read(stream.Context(), req.Id, req.Offset, req.Follow, func(line buildlog.Line) error {
+		return stream.Send(&pb.BuildLogLine{Offset: line.Offset, Job: line.Job, Text: line.Text})
+	})
+	if err != nil {
+		log.Printf("Error streaming build logs: %v", err)
+		return err
+	}
+	return nil
+}
+
+// DownloadArtifact streams the artifacts archives of the jobs of a build.
+//
+// The request must contain the ID of the build, and may contain the name of a
+// job to download only the artifacts of that job.
+//
+// The response is a stream of chunks of the gzipped tar archive of each job,
+// each chunk carrying the name of its job.
+func (s *server) DownloadArtifact(req *pb.DownloadArtifactRequest, stream pb.BuildService_DownloadArtifactServer) error {
+	log.Printf("Downloading artifacts with request: %v", req)
+	artifacts, err := s.artifactStore.ListArtifacts(req.BuildId)
+	if err != nil {
+		log.Printf("Error listing artifacts: %v", err)
+		return err
+	}
+	if req.Job != "" {
+		artifacts = slices.DeleteFunc(artifacts, func(artifact store.Artifact) bool {
+			return artifact.Job != req.Job
+		})
+	}
+	if len(artifacts) == 0 {
+		return status.Errorf(codes.NotFound, "no artifacts found for build %s", req.BuildId)
+	}
+
+	for _, artifact := range artifacts {
+		if err := s.sendArtifact(stream, artifact); err != nil {
+			log.Printf("Error sending artifacts of job %v: %v", artifact.JobId, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// sendArtifact sends the artifacts archive of a job to a DownloadArtifact
+// stream, in chunks of sourceChunkSize bytes.
+func (s *server) sendArtifact(stream pb.BuildService_DownloadArtifactServer, artifact store.Artifact) error {
+	file, err := s.artifacts.Open(artifact.BuildId, artifact.JobId)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	buffer := make([]byte, sourceChunkSize)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			if err := stream.Send(&pb.ArtifactChunk{Job: artifact.Job, Data: buffer[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
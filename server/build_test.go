@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/buildlog"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/queue"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+)
+
+func TestCancelBuildBetweenDispatchAndClaim(t *testing.T) {
+	root := t.TempDir()
+	db, err := sql.Open("sqlite3", filepath.Join(root, "ophelia.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	buildStore := store.NewSQLBuildStore(db)
+	s := &server{buildStore: buildStore, buildLogs: buildlog.NewManager(filepath.Join(root, "logs"), nil)}
+
+	build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: "repository", CommitHash: "commit", Branch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildStore.CreateJob(&pb.JobResponse{BuildId: build.Id, Stage: "test", Name: "unit"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.buildLogs.Open(build.Id); err != nil {
+		t.Fatal(err)
+	}
+
+	// The queue dispatches the build, which is cancelled before it is claimed.
+	dispatched := make(chan struct{})
+	claim := make(chan struct{})
+	executed := make(chan struct{})
+	s.queue = queue.New(buildStore, 1, func(ctx context.Context, build *pb.BuildResponse) {
+		close(dispatched)
+		<-claim
+		s.executeBuild(ctx, build)
+		close(executed)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.queue.Start(ctx)
+	<-dispatched
+
+	if _, err := s.CancelBuild(context.Background(), &pb.CancelBuildRequest{Id: build.Id}); err != nil {
+		t.Fatal(err)
+	}
+	close(claim)
+	<-executed
+
+	cancelled, err := buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled.Status != pb.BuildStatus_CANCELLED {
+		t.Errorf("expected the build to be cancelled, got %v", cancelled.Status)
+	}
+	for _, job := range cancelled.Jobs {
+		if job.Status != pb.BuildStatus_CANCELLED {
+			t.Errorf("expected job %v to be cancelled, got %v", job.Name, job.Status)
+		}
+	}
+
+	readCtx, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	if err := s.buildLogs.Read(readCtx, build.Id, 0, true, func(buildlog.Line) error { return nil }); err != nil {
+		t.Errorf("expected following the log to end once the build is cancelled, got %v", err)
+	}
+}
+
+func TestJobRecordsMatchByNameAndMatrix(t *testing.T) {
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        matrix:
+          go: ["1.23", "1.24"]
+        steps:
+          - run: go test ./...
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := definition.Jobs()
+	stored := []*pb.JobResponse{
+		{Id: "second", Name: jobs[1].Name, Matrix: map[string]string{"go": "1.24"}},
+		{Id: "first", Name: jobs[0].Name, Matrix: map[string]string{"go": "1.23"}},
+	}
+
+	records, err := jobRecords(jobs, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[jobs[0].Name].Id != "first" || records[jobs[1].Name].Id != "second" {
+		t.Errorf("expected the jobs to be matched by name and matrix, got %v", records)
+	}
+
+	stored[0].Matrix["go"] = "1.22"
+	if _, err := jobRecords(jobs, stored); err == nil {
+		t.Error("expected an error for a job missing from the build")
+	}
+}
+
+func TestRunBuildFailsWhenAJobIsMissing(t *testing.T) {
+	root := t.TempDir()
+	db, err := sql.Open("sqlite3", filepath.Join(root, "ophelia.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	buildStore := store.NewSQLBuildStore(db)
+	s := &server{buildStore: buildStore, buildLogs: buildlog.NewManager(filepath.Join(root, "logs"), nil)}
+
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        steps:
+          - run: go test ./...
+      - name: lint
+        steps:
+          - run: go vet ./...
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: "repository", CommitHash: "commit", Branch: "main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := buildStore.CreateJob(&pb.JobResponse{BuildId: build.Id, Stage: "test", Name: "unit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	build.Jobs = []*pb.JobResponse{job}
+	buildLog, err := s.buildLogs.Open(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.runBuild(context.Background(), &pb.RepositoryResponse{Id: "repository", Name: "project"}, build, definition, buildLog)
+
+	failed, err := buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.Status != pb.BuildStatus_FAILED {
+		t.Errorf("expected the build to fail, got %v", failed.Status)
+	}
+	if failed.Jobs[0].Status != pb.BuildStatus_CANCELLED {
+		t.Errorf("expected the recorded job not to run, got %v", failed.Jobs[0].Status)
+	}
+}
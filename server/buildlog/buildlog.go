@@ -0,0 +1,256 @@
+package buildlog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/redact"
+)
+
+// Line is a single line of build output.
+type Line struct {
+	Offset int64  `json:"-"`
+	Job    string `json:"job"`
+	Text   string `json:"text"`
+}
+
+// Manager keeps the logs of builds on disk under its root directory, one file
+// per build, and tracks the logs of the builds still producing output so that
+// readers can follow them.
+//
+// The output written to the logs is redacted with redactor before it is
+// stored.
+type Manager struct {
+	root     string
+	redactor *redact.Redactor
+	mu       sync.Mutex
+	live     map[string]*Log
+}
+
+// Log is the log of a build that is still producing output.
+type Log struct {
+	mu       sync.Mutex
+	file     *os.File
+	changed  chan struct{}
+	finished bool
+	redactor *redact.Redactor
+}
+
+// NewManager creates a Manager storing build logs under root, redacted with
+// redactor. A nil redactor leaves the output unchanged.
+func NewManager(root string, redactor *redact.Redactor) *Manager {
+	return &Manager{
+		root:     root,
+		redactor: redactor,
+		live:     make(map[string]*Log),
+	}
+}
+
+// Open creates the log of a build, or reopens it for appending, and registers
+// it as live until Close is called.
+func (m *Manager) Open(buildID string) (*Log, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if log, ok := m.live[buildID]; ok {
+		return log, nil
+	}
+	if err := os.MkdirAll(m.root, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+	file, err := os.OpenFile(m.path(buildID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open build log: %w", err)
+	}
+
+	log := &Log{file: file, changed: make(chan struct{}), redactor: m.redactor}
+	m.live[buildID] = log
+	return log, nil
+}
+
+// Close marks the log of a build as finished, waking up its followers, and
+// stops tracking it as live.
+func (m *Manager) Close(buildID string) error {
+	m.mu.Lock()
+	log, ok := m.live[buildID]
+	delete(m.live, buildID)
+	m.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return log.finish()
+}
+
+// Read sends the lines of a build log starting at offset to send.
+//
+// If follow is true and the build is still producing output, Read waits for
+// new lines until the build finishes or ctx is cancelled. Otherwise it returns
+// once the lines written so far have been sent.
+func (m *Manager) Read(ctx context.Context, buildID string, offset int64, follow bool, send func(Line) error) error {
+	m.mu.Lock()
+	live := m.live[buildID]
+	m.mu.Unlock()
+
+	file, err := os.Open(m.path(buildID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open build log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var lineOffset int64
+	var partial []byte
+	for {
+		var changed <-chan struct{}
+		finished := true
+		if live != nil && follow {
+			changed, finished = live.watch()
+		}
+
+		for {
+			chunk, err := reader.ReadBytes('\n')
+			partial = append(partial, chunk...)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read build log: %w", err)
+			}
+
+			if lineOffset >= offset {
+				var line Line
+				if err := json.Unmarshal(partial, &line); err != nil {
+					return fmt.Errorf("failed to decode build log line %d: %w", lineOffset, err)
+				}
+				line.Offset = lineOffset
+				if err := send(line); err != nil {
+					return err
+				}
+			}
+			lineOffset++
+			partial = nil
+		}
+
+		if finished {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// path returns the file path of the log of a build.
+func (m *Manager) path(buildID string) string {
+	return filepath.Join(m.root, buildID+".log")
+}
+
+// Writer returns a writer appending the output of a job to the log, one line
+// at a time. The writer must be closed to flush a trailing line that does not
+// end with a newline.
+func (l *Log) Writer(job string) io.WriteCloser {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return &lineWriter{log: l, job: job, redactor: l.redactor.Lines()}
+}
+
+// RedactValues redacts the given values, and their base64 forms, from the
+// output written by the writers created from now on, so that secrets never
+// reach the log file nor its followers.
+func (l *Log) RedactValues(values ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.redactor = l.redactor.WithValues(values...)
+}
+
+// append writes a line to the log file and wakes up the followers.
+func (l *Log) append(job, text string) error {
+	data, err := json.Marshal(Line{Job: job, Text: text})
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.finished {
+		return fmt.Errorf("build log is already closed")
+	}
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	close(l.changed)
+	l.changed = make(chan struct{})
+	return nil
+}
+
+// watch returns a channel closed on the next change of the log, and whether
+// the log is already finished.
+func (l *Log) watch() (<-chan struct{}, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.changed, l.finished
+}
+
+// finish closes the log file and wakes up the followers for the last time.
+func (l *Log) finish() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.finished {
+		return nil
+	}
+	l.finished = true
+	close(l.changed)
+	return l.file.Close()
+}
+
+// lineWriter splits the output of a job into lines appended to a log. It may
+// be used by several goroutines at once.
+type lineWriter struct {
+	log      *Log
+	job      string
+	redactor *redact.Lines
+	mu       sync.Mutex
+	buffer   []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buffer = append(w.buffer, p...)
+	for {
+		index := bytes.IndexByte(w.buffer, '\n')
+		if index < 0 {
+			return len(p), nil
+		}
+		line := string(bytes.TrimSuffix(w.buffer[:index], []byte("\r")))
+		w.buffer = w.buffer[index+1:]
+		if err := w.log.append(w.job, w.redactor.Redact(line)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	line := string(w.buffer)
+	w.buffer = nil
+	return w.log.append(w.job, w.redactor.Redact(line))
+}
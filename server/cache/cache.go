@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSize is the maximum size of a cache in bytes when none is
+// configured.
+const DefaultMaxSize int64 = 1 << 30
+
+// Cache keeps the dependency caches of jobs on disk under its root directory,
+// as gzipped tar archives named after the hash of their key.
+//
+// Once the archives take more than maxSize bytes, the least recently used ones
+// are evicted. A maxSize of zero or less disables eviction.
+type Cache struct {
+	root    string
+	maxSize int64
+	mu      sync.Mutex
+}
+
+// New creates a Cache storing archives under root, up to maxSize bytes.
+func New(root string, maxSize int64) *Cache {
+	return &Cache{root: root, maxSize: maxSize}
+}
+
+// Open opens the archive stored for key for reading, and marks it as used.
+//
+// If there is no archive for key, an error satisfying os.IsNotExist is
+// returned.
+func (c *Cache) Open(key string) (*os.File, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := c.path(key)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return file, nil
+}
+
+// Save stores the archive for key, written by write, replacing any previous
+// archive of the key, then evicts the least recently used archives until the
+// cache fits in its maximum size.
+//
+// The archive is written to a temporary file first, so that a failed or
+// interrupted write never leaves a partial archive behind. An archive larger
+// than the maximum size is not kept.
+//
+// Returns the size of the stored archive.
+func (c *Cache) Save(key string, write func(io.Writer) error) (int64, error) {
+	if err := os.MkdirAll(c.root, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	file, err := os.CreateTemp(c.root, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create cache archive: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := write(file); err != nil {
+		file.Close()
+		return 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, err
+	}
+	if c.maxSize > 0 && info.Size() > c.maxSize {
+		return 0, fmt.Errorf("cache archive of %d bytes exceeds the maximum cache size of %d bytes", info.Size(), c.maxSize)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.Rename(file.Name(), c.path(key)); err != nil {
+		return 0, fmt.Errorf("failed to store cache archive: %w", err)
+	}
+	return info.Size(), c.evict()
+}
+
+// evict removes the least recently used archives until the total size of the
+// cache is at most its maximum size. It must be called with mu held.
+func (c *Cache) evict() error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return err
+	}
+
+	var archives []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		archives = append(archives, info)
+		total += info.Size()
+	}
+
+	slices.SortFunc(archives, func(a, b os.FileInfo) int {
+		return a.ModTime().Compare(b.ModTime())
+	})
+	for _, archive := range archives {
+		if total <= c.maxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.root, archive.Name())); err != nil {
+			return err
+		}
+		total -= archive.Size()
+	}
+	return nil
+}
+
+// path returns the path of the archive of key. Keys are hashed, so that any
+// key maps to a single file name inside root.
+func (c *Cache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(c.root, hex.EncodeToString(hash[:])+".tar.gz")
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func save(t *testing.T, cache *Cache, key, content string) {
+	t.Helper()
+	_, err := cache.Save(key, func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ensure the next archive is more recent, whatever the file system timestamp
+	// resolution.
+	past := time.Now().Add(-time.Hour)
+	os.Chtimes(cache.path(key), past, past)
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := New(t.TempDir(), 10)
+	save(t, cache, "a", "aaaa")
+	save(t, cache, "b", "bbbb")
+
+	file, err := cache.Open("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	save(t, cache, "c", "cccc")
+	if _, err := cache.Open("b"); !os.IsNotExist(err) {
+		t.Fatalf("expected the least recently used archive to be evicted, got %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		file, err := cache.Open(key)
+		if err != nil {
+			t.Fatalf("expected archive %q to be kept: %v", key, err)
+		}
+		file.Close()
+	}
+
+	_, err = cache.Save("large", func(w io.Writer) error {
+		_, err := io.WriteString(w, strings.Repeat("x", 11))
+		return err
+	})
+	if err == nil {
+		t.Fatal("expected an archive larger than the cache to be rejected")
+	}
+}
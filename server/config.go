@@ -4,25 +4,43 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/pelletier/go-toml/v2"
 )
 
 type Config struct {
 	Server struct {
 		Port           int    `toml:"port"`
+		HTTPPort       int    `toml:"http_port"`
+		SSHPort        int    `toml:"ssh_port"`
 		Secret         string `toml:"secret"`
 		HomePath         string `toml:"home_path"`
 		ExpirationTime int    `toml:"expiration_time"`
+		ArtifactRetentionDays int `toml:"artifact_retention_days"`
+		CacheMaxSize int `toml:"cache_max_size"`
+		MasterKey string `toml:"master_key"`
+		RedactPatterns []string `toml:"redact_patterns"`
 	} `toml:"server"`
 	SSL struct {
 		CertFile string `toml:"cert_file"`
 		KeyFile  string `toml:"key_file"`
 	} `toml:"ssl"`
+	Runner struct {
+		MaxConcurrentBuilds int      `toml:"max_concurrent_builds"`
+		CoalesceBuilds      bool     `toml:"coalesce_builds"`
+		LocalExecutor       bool     `toml:"local_executor"`
+		Labels              []string `toml:"labels"`
+		RegistrationToken   string   `toml:"registration_token"`
+		LeaseTimeout        int      `toml:"lease_timeout"`
+	} `toml:"runner"`
 }
 
 var (
 	configCache Config
+	configOnce  sync.Once
 )
 
 const (
@@ -34,7 +52,6 @@ const (
 // once and caches the result. If reading the file or unmarshalling the TOML data
 // fails, the function panics. It returns the cached configuration.
 func LoadConfig() Config {
-	var err error
 	if pb.CheckRunningFromImage() {
 		configCache = loadConfigFromEnv()
 		return configCache
@@ -47,12 +64,47 @@ func LoadConfig() Config {
 		return config
 	}
 
-	configCache, err = pb.LoadConfigFromFile(configPath, configCache)
+	configOnce.Do(func() {
+		var err error
+		configCache, err = loadConfigFile(configPath)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	return configCache
+}
+
+// loadConfigFile reads the server configuration from the TOML file at path.
+//
+// The runner settings missing from the file, as in the files written before
+// they existed, take the same defaults as the environment variables, so that
+// builds are run by the local executor. So do the ports of the Git smart HTTP
+// and SSH servers, which are only disabled by setting them to 0.
+//
+// Returns:
+// - Config: The configuration.
+// - error: An error if the file cannot be read or parsed.
+func loadConfigFile(path string) (Config, error) {
+	var config Config
+	config.Server.HTTPPort = 8080
+	config.Server.SSHPort = 2222
+	config.Runner.CoalesceBuilds = true
+	config.Runner.LocalExecutor = true
+
+	data, err := os.ReadFile(path)
 	if err != nil {
-		panic(err)
+		return config, err
+	}
+	if err := toml.Unmarshal(data, &config); err != nil {
+		return config, err
 	}
 
-	return configCache
+	config.Runner.MaxConcurrentBuilds = max(config.Runner.MaxConcurrentBuilds, 1)
+	if config.Runner.LeaseTimeout <= 0 {
+		config.Runner.LeaseTimeout = 60
+	}
+	return config, nil
 }
 
 // loadConfigFromEnv loads the server configuration from environment variables.
@@ -68,6 +120,20 @@ func loadConfigFromEnv() (config Config) {
 	}
 	config.Server.Port = port
 
+	httpPort, err := strconv.Atoi(os.Getenv("APP_OPHELIA_CI_SERVER_HTTP_PORT"))
+	if err != nil || httpPort < 0 {
+		log.Printf("APP_OPHELIA_CI_SERVER_HTTP_PORT is not set or invalid. Using default port 8080.")
+		httpPort = 8080
+	}
+	config.Server.HTTPPort = httpPort
+
+	sshPort, err := strconv.Atoi(os.Getenv("APP_OPHELIA_CI_SERVER_SSH_PORT"))
+	if err != nil || sshPort < 0 {
+		log.Printf("APP_OPHELIA_CI_SERVER_SSH_PORT is not set or invalid. Using default port 2222.")
+		sshPort = 2222
+	}
+	config.Server.SSHPort = sshPort
+
 	secret := os.Getenv("APP_OPHELIA_CI_SERVER_SECRET")
 	if secret == "" {
 		log.Printf("APP_OPHELIA_CI_SERVER_SECRET is not set. Using random secret.")
@@ -89,7 +155,64 @@ func loadConfigFromEnv() (config Config) {
 	}
 	config.Server.ExpirationTime = expirationTime
 
+	artifactRetentionDays, err := strconv.Atoi(os.Getenv("APP_OPHELIA_CI_SERVER_ARTIFACT_RETENTION_DAYS"))
+	if err != nil || artifactRetentionDays <= 0 {
+		log.Printf("APP_OPHELIA_CI_SERVER_ARTIFACT_RETENTION_DAYS is not set or invalid. Using default artifact retention of 30 days.")
+		artifactRetentionDays = 30
+	}
+	config.Server.ArtifactRetentionDays = artifactRetentionDays
+
+	cacheMaxSize, err := strconv.Atoi(os.Getenv("APP_OPHELIA_CI_SERVER_CACHE_MAX_SIZE"))
+	if err != nil || cacheMaxSize <= 0 {
+		log.Printf("APP_OPHELIA_CI_SERVER_CACHE_MAX_SIZE is not set or invalid. Using default cache size of 1024 MB.")
+		cacheMaxSize = 1024
+	}
+	config.Server.CacheMaxSize = cacheMaxSize
+
+	config.Server.MasterKey = os.Getenv("APP_OPHELIA_CI_SERVER_MASTER_KEY")
+	if config.Server.MasterKey == "" {
+		log.Printf("APP_OPHELIA_CI_SERVER_MASTER_KEY is not set. Repository secrets are disabled.")
+	}
+
+	// Patterns are separated by newlines, since regular expressions may contain
+	// commas.
+	if patterns := os.Getenv("APP_OPHELIA_CI_SERVER_REDACT_PATTERNS"); patterns != "" {
+		config.Server.RedactPatterns = strings.Split(patterns, "\n")
+	}
+
 	config.SSL.CertFile = os.Getenv("APP_OPHELIA_CI_SERVER_CERT_FILE")
 	config.SSL.KeyFile = os.Getenv("APP_OPHELIA_CI_SERVER_KEY_FILE")
+
+	maxConcurrentBuilds, err := strconv.Atoi(os.Getenv("APP_OPHELIA_CI_RUNNER_MAX_CONCURRENT_BUILDS"))
+	if err != nil || maxConcurrentBuilds <= 0 {
+		log.Printf("APP_OPHELIA_CI_RUNNER_MAX_CONCURRENT_BUILDS is not set or invalid. Using default of 1 concurrent build.")
+		maxConcurrentBuilds = 1
+	}
+	config.Runner.MaxConcurrentBuilds = maxConcurrentBuilds
+
+	coalesceBuilds, err := strconv.ParseBool(os.Getenv("APP_OPHELIA_CI_RUNNER_COALESCE_BUILDS"))
+	if err != nil {
+		coalesceBuilds = true
+	}
+	config.Runner.CoalesceBuilds = coalesceBuilds
+
+	localExecutor, err := strconv.ParseBool(os.Getenv("APP_OPHELIA_CI_RUNNER_LOCAL_EXECUTOR"))
+	if err != nil {
+		localExecutor = true
+	}
+	config.Runner.LocalExecutor = localExecutor
+
+	if labels := os.Getenv("APP_OPHELIA_CI_RUNNER_LABELS"); labels != "" {
+		config.Runner.Labels = strings.Split(labels, ",")
+	}
+
+	config.Runner.RegistrationToken = os.Getenv("APP_OPHELIA_CI_RUNNER_REGISTRATION_TOKEN")
+
+	leaseTimeout, err := strconv.Atoi(os.Getenv("APP_OPHELIA_CI_RUNNER_LEASE_TIMEOUT"))
+	if err != nil || leaseTimeout <= 0 {
+		log.Printf("APP_OPHELIA_CI_RUNNER_LEASE_TIMEOUT is not set or invalid. Using default lease timeout of 60 seconds.")
+		leaseTimeout = 60
+	}
+	config.Runner.LeaseTimeout = leaseTimeout
 	return
 }
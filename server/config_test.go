@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigFileWithoutRunnerSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server-config.toml")
+	data := "[server]\nport = 50051\nhome_path = \"/var/lib/ophelia/\"\n\n[ssl]\ncert_file = \"\"\nkey_file = \"\"\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Server.Port != 50051 {
+		t.Errorf("expected port 50051, got %d", config.Server.Port)
+	}
+	if config.Server.HTTPPort != 8080 {
+		t.Errorf("expected HTTP port 8080, got %d", config.Server.HTTPPort)
+	}
+	if config.Server.SSHPort != 2222 {
+		t.Errorf("expected SSH port 2222, got %d", config.Server.SSHPort)
+	}
+	if !config.Runner.LocalExecutor {
+		t.Error("expected the local executor to be enabled by default")
+	}
+	if !config.Runner.CoalesceBuilds {
+		t.Error("expected builds to be coalesced by default")
+	}
+	if config.Runner.MaxConcurrentBuilds != 1 {
+		t.Errorf("expected 1 concurrent build, got %d", config.Runner.MaxConcurrentBuilds)
+	}
+	if config.Runner.LeaseTimeout != 60 {
+		t.Errorf("expected a lease timeout of 60 seconds, got %d", config.Runner.LeaseTimeout)
+	}
+}
+
+func TestLoadConfigFileKeepsRunnerSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server-config.toml")
+	data := "[runner]\nlocal_executor = false\nmax_concurrent_builds = 0\nlease_timeout = 30\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Runner.LocalExecutor {
+		t.Error("expected the local executor to stay disabled")
+	}
+	if config.Runner.MaxConcurrentBuilds != 1 {
+		t.Errorf("expected at least 1 concurrent build, got %d", config.Runner.MaxConcurrentBuilds)
+	}
+	if config.Runner.LeaseTimeout != 30 {
+		t.Errorf("expected a lease timeout of 30 seconds, got %d", config.Runner.LeaseTimeout)
+	}
+}
+
+func TestLoadConfigFileDisablesGitServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server-config.toml")
+	data := "[server]\nhttp_port = 0\nssh_port = 0\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Server.HTTPPort != 0 {
+		t.Errorf("expected the Git smart HTTP server to be disabled, got port %d", config.Server.HTTPPort)
+	}
+	if config.Server.SSHPort != 0 {
+		t.Errorf("expected the Git SSH server to be disabled, got port %d", config.Server.SSHPort)
+	}
+}
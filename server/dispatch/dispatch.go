@@ -0,0 +1,313 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/executor"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"github.com/google/uuid"
+)
+
+// DefaultTimeout is the time after which a lease that is not renewed expires,
+// when no timeout is configured.
+const DefaultTimeout = time.Minute
+
+// ErrLeaseNotFound is returned when a lease does not exist, has expired or
+// belongs to another runner.
+var ErrLeaseNotFound = errors.New("lease not found")
+
+// Job is a pipeline job waiting to be run by a runner.
+type Job struct {
+	ID         string
+	Build      executor.Build
+	Definition pipeline.Job
+	Output     io.Writer
+}
+
+// Result holds the outcome of a job reported by a runner.
+type Result struct {
+	Success  bool
+	ExitCode int32
+	TimedOut bool
+}
+
+// Runner describes a runner leasing jobs. Leases of local runners, which run
+// inside the server process, never expire.
+type Runner struct {
+	ID     string
+	Name   string
+	Labels []string
+	Local  bool
+}
+
+// CanRun reports whether the runner has all the given labels, as required by
+// the runs-on labels of a job.
+func (r Runner) CanRun(labels []string) bool {
+	for _, label := range labels {
+		if !slices.Contains(r.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// Lease is a job handed to a runner. A lease of a remote runner must be
+// renewed with Heartbeat before it expires, or its job is requeued.
+type Lease struct {
+	ID     string
+	Runner Runner
+	Job    *Job
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	deadline time.Time
+	task     *task
+}
+
+// Context returns the context of the lease, cancelled when its job is
+// cancelled or the lease expires.
+func (l *Lease) Context() context.Context {
+	return l.ctx
+}
+
+// task is a submitted job, waiting for a runner or leased to one.
+type task struct {
+	job       *Job
+	result    chan Result
+	lease     *Lease
+	withdrawn bool
+}
+
+// Dispatcher hands submitted jobs to the runners asking for work. Each runner
+// gets the oldest submitted job whose runs-on labels it has.
+type Dispatcher struct {
+	// OnExpire is called when the lease of a remote runner expires, before
+	// its job is requeued.
+	OnExpire func(lease *Lease)
+
+	timeout time.Duration
+
+	mu      sync.Mutex
+	pending []*task
+	leases  map[string]*Lease
+	changed chan struct{}
+}
+
+// New creates a Dispatcher whose remote leases expire when they are not renewed
+// within timeout. A timeout that is not positive is replaced by DefaultTimeout.
+func New(timeout time.Duration) *Dispatcher {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Dispatcher{
+		timeout: timeout,
+		leases:  make(map[string]*Lease),
+		changed: make(chan struct{}),
+	}
+}
+
+// Timeout returns the time after which a lease that is not renewed expires.
+func (d *Dispatcher) Timeout() time.Duration {
+	return d.timeout
+}
+
+// Start expires the leases that are not renewed in time, until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.timeout / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				d.expire(now)
+			}
+		}
+	}()
+}
+
+// Submit queues a job and waits until a runner has run it.
+//
+// If ctx is cancelled before the job is leased, the job is withdrawn. If it
+// is cancelled while the job runs, the lease context is cancelled and Submit
+// waits for the runner to report the job as finished, or for the lease to
+// expire.
+//
+// Returns the result of the job, and the error of ctx if it was cancelled.
+func (d *Dispatcher) Submit(ctx context.Context, job *Job) (Result, error) {
+	t := &task{job: job, result: make(chan Result, 1)}
+	d.mu.Lock()
+	d.pending = append(d.pending, t)
+	d.notifyLocked()
+	d.mu.Unlock()
+
+	select {
+	case result := <-t.result:
+		return result, nil
+	case <-ctx.Done():
+	}
+
+	if d.withdraw(t) {
+		return <-t.result, ctx.Err()
+	}
+	return Result{ExitCode: -1}, ctx.Err()
+}
+
+// Lease waits until a job the runner can run is available and leases it to the
+// runner.
+//
+// Returns an error if ctx is cancelled before such a job is available.
+func (d *Dispatcher) Lease(ctx context.Context, runner Runner) (*Lease, error) {
+	for {
+		d.mu.Lock()
+		index := slices.IndexFunc(d.pending, func(t *task) bool {
+			return runner.CanRun(t.job.Definition.RunsOn)
+		})
+		if index >= 0 {
+			t := d.pending[index]
+			d.pending = slices.Delete(d.pending, index, index+1)
+			lease := d.leaseLocked(t, runner)
+			d.mu.Unlock()
+			return lease, nil
+		}
+		changed := d.changed
+		d.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// Get returns a lease held by a runner.
+func (d *Dispatcher) Get(leaseID, runnerID string) (*Lease, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.getLocked(leaseID, runnerID)
+}
+
+// Heartbeat renews a lease held by a runner.
+//
+// Returns whether the job of the lease was cancelled, in which case the runner
+// should stop it and complete the lease.
+func (d *Dispatcher) Heartbeat(leaseID, runnerID string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lease, err := d.getLocked(leaseID, runnerID)
+	if err != nil {
+		return false, err
+	}
+	lease.deadline = time.Now().Add(d.timeout)
+	return lease.ctx.Err() != nil, nil
+}
+
+// Complete releases a lease held by a runner with the result of its job.
+func (d *Dispatcher) Complete(leaseID, runnerID string, result Result) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lease, err := d.getLocked(leaseID, runnerID)
+	if err != nil {
+		return err
+	}
+	delete(d.leases, lease.ID)
+	lease.cancel()
+	lease.task.result <- result
+	return nil
+}
+
+// withdraw removes a task from the pending list, or cancels its lease.
+//
+// Returns whether the task was leased, in which case its result is still
+// delivered once the lease is completed or expires.
+func (d *Dispatcher) withdraw(t *task) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t.withdrawn = true
+	if t.lease != nil {
+		t.lease.cancel()
+		return true
+	}
+	for index, pending := range d.pending {
+		if pending == t {
+			d.pending = append(d.pending[:index], d.pending[index+1:]...)
+			break
+		}
+	}
+	return false
+}
+
+// expire releases the remote leases that were not renewed in time, calling
+// OnExpire for each of them. The jobs that were not withdrawn are then put back
+// at the front of the pending list.
+func (d *Dispatcher) expire(now time.Time) {
+	var expired []*Lease
+	d.mu.Lock()
+	for id, lease := range d.leases {
+		if lease.Runner.Local || now.Before(lease.deadline) {
+			continue
+		}
+		delete(d.leases, id)
+		lease.cancel()
+		expired = append(expired, lease)
+	}
+	d.mu.Unlock()
+
+	for _, lease := range expired {
+		log.Printf("Lease %v of runner %v expired", lease.ID, lease.Runner.Name)
+		if d.OnExpire != nil {
+			d.OnExpire(lease)
+		}
+
+		d.mu.Lock()
+		t := lease.task
+		if t.withdrawn {
+			t.result <- Result{ExitCode: -1}
+		} else {
+			t.lease = nil
+			d.pending = append([]*task{t}, d.pending...)
+			d.notifyLocked()
+		}
+		d.mu.Unlock()
+	}
+}
+
+// leaseLocked leases a task to a runner. d.mu must be held.
+func (d *Dispatcher) leaseLocked(t *task, runner Runner) *Lease {
+	ctx, cancel := context.WithCancel(context.Background())
+	lease := &Lease{
+		ID:       uuid.New().String(),
+		Runner:   runner,
+		Job:      t.job,
+		ctx:      ctx,
+		cancel:   cancel,
+		deadline: time.Now().Add(d.timeout),
+		task:     t,
+	}
+	t.lease = lease
+	d.leases[lease.ID] = lease
+	return lease
+}
+
+// getLocked returns a lease held by a runner. d.mu must be held.
+func (d *Dispatcher) getLocked(leaseID, runnerID string) (*Lease, error) {
+	lease, ok := d.leases[leaseID]
+	if !ok || lease.Runner.ID != runnerID {
+		return nil, ErrLeaseNotFound
+	}
+	return lease, nil
+}
+
+// notifyLocked wakes up the runners waiting for a job. d.mu must be held.
+func (d *Dispatcher) notifyLocked() {
+	close(d.changed)
+	d.changed = make(chan struct{})
+}
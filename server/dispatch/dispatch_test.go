@@ -0,0 +1,124 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+)
+
+var remote = Runner{ID: "remote", Name: "remote"}
+
+func TestSubmitReturnsResultOfLeasedJob(t *testing.T) {
+	d := New(time.Minute)
+	results := make(chan Result)
+	go func() {
+		result, _ := d.Submit(context.Background(), &Job{ID: "job"})
+		results <- result
+	}()
+
+	lease, err := d.Lease(context.Background(), remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Job.ID != "job" {
+		t.Fatalf("unexpected job %v", lease.Job.ID)
+	}
+	if _, err := d.Get(lease.ID, "other"); !errors.Is(err, ErrLeaseNotFound) {
+		t.Fatalf("lease should not be visible to other runners, got %v", err)
+	}
+	if err := d.Complete(lease.ID, remote.ID, Result{Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if result := <-results; !result.Success {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestLeaseSkipsJobsWithMissingLabels(t *testing.T) {
+	d := New(time.Minute)
+	go d.Submit(context.Background(), &Job{ID: "docker", Definition: pipeline.Job{RunsOn: []string{"linux", "docker"}}})
+	go d.Submit(context.Background(), &Job{ID: "linux", Definition: pipeline.Job{RunsOn: []string{"linux"}}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	linux := Runner{ID: "linux", Name: "linux", Labels: []string{"linux", "amd64"}}
+	lease, err := d.Lease(ctx, linux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Job.ID != "linux" {
+		t.Fatalf("runner without the docker label leased job %v", lease.Job.ID)
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer shortCancel()
+	if _, err := d.Lease(shortCtx, linux); err == nil {
+		t.Fatal("runner without the docker label should not lease the remaining job")
+	}
+
+	docker := Runner{ID: "docker", Name: "docker", Labels: []string{"docker", "linux"}}
+	if lease, err := d.Lease(ctx, docker); err != nil || lease.Job.ID != "docker" {
+		t.Fatalf("unexpected lease %+v: %v", lease, err)
+	}
+}
+
+func TestExpiredLeaseIsRequeued(t *testing.T) {
+	d := New(time.Minute)
+	var expired *Lease
+	d.OnExpire = func(lease *Lease) { expired = lease }
+	go d.Submit(context.Background(), &Job{ID: "job"})
+
+	first, err := d.Lease(context.Background(), remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.expire(time.Now().Add(2 * time.Minute))
+	if expired != first {
+		t.Fatal("OnExpire was not called for the expired lease")
+	}
+	if first.Context().Err() == nil {
+		t.Fatal("expired lease context was not cancelled")
+	}
+	if _, err := d.Heartbeat(first.ID, remote.ID); !errors.Is(err, ErrLeaseNotFound) {
+		t.Fatalf("expired lease should be gone, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	second, err := d.Lease(ctx, remote)
+	if err != nil {
+		t.Fatalf("job was not requeued: %v", err)
+	}
+	if second.Job.ID != "job" || second.ID == first.ID {
+		t.Fatalf("unexpected lease %+v", second)
+	}
+}
+
+func TestCancelledSubmitCancelsLease(t *testing.T) {
+	d := New(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := d.Submit(ctx, &Job{ID: "job"})
+		done <- err
+	}()
+
+	lease, err := d.Lease(context.Background(), remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-lease.Context().Done()
+
+	cancelled, err := d.Heartbeat(lease.ID, remote.ID)
+	if err != nil || !cancelled {
+		t.Fatalf("heartbeat should report the cancellation, got %v, %v", cancelled, err)
+	}
+	d.Complete(lease.ID, remote.ID, Result{ExitCode: -1})
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
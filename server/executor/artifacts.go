@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+)
+
+// ArtifactFiles lists the files of a workspace matching the artifacts declared
+// by the steps of a job that were run, sorted by path.
+//
+// Directories are listed with all the files under them. Files reached through
+// a symbolic link leading outside of the workspace are never listed.
+func ArtifactFiles(workspace string, job pipeline.Job, result JobResult) ([]string, error) {
+	root, err := os.OpenRoot(workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	var patterns []string
+	for _, step := range job.Steps[:min(len(result.Steps), len(job.Steps))] {
+		patterns = append(patterns, step.Artifacts...)
+	}
+	return matchFiles(root.FS(), patterns)
+}
+
+// matchFiles lists the regular files of fsys matching the glob patterns,
+// sorted by path. Directories are listed with all the files under them.
+func matchFiles(fsys fs.FS, patterns []string) ([]string, error) {
+	files := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, path.Clean(filepath.ToSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			err := fs.WalkDir(fsys, match, func(name string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if entry.Type().IsRegular() {
+					files[name] = true
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(files)), nil
+}
+
+// ArchiveArtifacts writes a gzipped tar archive of files, relative to the
+// workspace, to archive. It is also used to archive the cached paths of jobs.
+func ArchiveArtifacts(workspace string, files []string, archive io.Writer) error {
+	root, err := os.OpenRoot(workspace)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	compressed := gzip.NewWriter(archive)
+	writer := tar.NewWriter(compressed)
+	for _, name := range files {
+		if err := addFile(writer, root, name); err != nil {
+			return fmt.Errorf("failed to archive %s: %w", name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return compressed.Close()
+}
+
+// addFile writes a regular file of root to a tar archive.
+func addFile(writer *tar.Writer, root *os.Root, name string) error {
+	file, err := root.Open(filepath.FromSlash(name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := writer.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.CopyN(writer, file, header.Size)
+	return err
+}
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+)
+
+// restoreCache resolves the cache key of a job and extracts the archive stored
+// for it into the workspace. Keys are scoped to the build repository, so that
+// a repository never restores the cache of another one.
+//
+// Cache failures are reported to output, but never fail the job.
+//
+// Returns the scoped key, empty when the job has no cache or its key could not
+// be resolved, and whether an archive was restored.
+func (e *Executor) restoreCache(workspace string, build Build, job pipeline.Job, output io.Writer) (string, bool) {
+	if e.Cache == nil || job.Cache == nil {
+		return "", false
+	}
+	key, err := job.Cache.ResolveKey(func(patterns []string) (string, error) {
+		return hashFiles(workspace, patterns)
+	})
+	if err != nil {
+		fmt.Fprintf(output, "Failed to resolve cache key: %v\n", err)
+		return "", false
+	}
+
+	scopedKey := build.Repository + "/" + key
+	file, err := e.Cache.Open(scopedKey)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(output, "Cache not found for key %s\n", key)
+		return scopedKey, false
+	}
+	if err != nil {
+		fmt.Fprintf(output, "Failed to restore cache: %v\n", err)
+		return scopedKey, false
+	}
+	defer file.Close()
+
+	archive, err := gzip.NewReader(file)
+	if err == nil {
+		err = extractTar(workspace, archive)
+	}
+	if err != nil {
+		fmt.Fprintf(output, "Failed to restore cache: %v\n", err)
+		return scopedKey, false
+	}
+	fmt.Fprintf(output, "Cache restored from key %s\n", key)
+	return scopedKey, true
+}
+
+// saveCache stores an archive of the cached paths of a job under key.
+//
+// Cache failures are reported to output, but never fail the job.
+func (e *Executor) saveCache(workspace, key string, job pipeline.Job, output io.Writer) {
+	root, err := os.OpenRoot(workspace)
+	if err != nil {
+		fmt.Fprintf(output, "Failed to save cache: %v\n", err)
+		return
+	}
+	defer root.Close()
+
+	files, err := matchFiles(root.FS(), job.Cache.Paths)
+	if err != nil {
+		fmt.Fprintf(output, "Failed to save cache: %v\n", err)
+		return
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(output, "Cache not saved: no files match its paths")
+		return
+	}
+
+	size, err := e.Cache.Save(key, func(w io.Writer) error {
+		return ArchiveArtifacts(workspace, files, w)
+	})
+	if err != nil {
+		fmt.Fprintf(output, "Failed to save cache: %v\n", err)
+		return
+	}
+	fmt.Fprintf(output, "Cache saved with %d files (%d bytes)\n", len(files), size)
+}
+
+// hashFiles returns a hash of the paths and content of the workspace files
+// matching the glob patterns, or an empty string if none matches.
+func hashFiles(workspace string, patterns []string) (string, error) {
+	root, err := os.OpenRoot(workspace)
+	if err != nil {
+		return "", err
+	}
+	defer root.Close()
+	fsys := root.FS()
+
+	files, err := matchFiles(fsys, patterns)
+	if err != nil || len(files) == 0 {
+		return "", err
+	}
+
+	hash := sha256.New()
+	for _, name := range files {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", err
+		}
+		fileHash := sha256.Sum256(content)
+		fmt.Fprintf(hash, "%s\x00%x\n", name, fileHash)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/cache"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+)
+
+// runFirstJob runs the first job of the build pipeline in a fresh workspace,
+// which is removed once the job finishes.
+func runFirstJob(t *testing.T, executor *Executor, build Build, output *bytes.Buffer) JobResult {
+	t.Helper()
+	workspace, err := executor.PrepareWorkspace(build.ID, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer executor.Cleanup(workspace)
+	return executor.RunJob(context.Background(), workspace, build, build.Pipeline.Jobs()[0], output)
+}
+
+func TestRunJobRestoresAndSavesCache(t *testing.T) {
+	repoPath, commit := createRepository(t, map[string]string{"go.sum": "module v1.0.0"})
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: build
+        cache:
+          key: deps-{{ hashFiles('go.sum') }}
+          paths: [deps]
+        steps:
+          - run: test -f deps/module || { echo downloading; mkdir deps && echo module > deps/module; }
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(t.TempDir())
+	executor.Cache = cache.New(t.TempDir(), 0)
+	build := Build{ID: "build", Repository: "repo", RepositoryPath: repoPath, Revision: commit, Pipeline: definition}
+
+	var outputs []string
+	for range 2 {
+		var output bytes.Buffer
+		if result := runFirstJob(t, executor, build, &output); !result.Success {
+			t.Fatalf("unexpected result %+v\n%s", result, output.String())
+		}
+		outputs = append(outputs, output.String())
+	}
+
+	if !strings.Contains(outputs[0], "Cache not found") || !strings.Contains(outputs[0], "\ndownloading\n") || !strings.Contains(outputs[0], "Cache saved with 1 files") {
+		t.Fatalf("unexpected output of the first run: %s", outputs[0])
+	}
+	if !strings.Contains(outputs[1], "Cache restored from key deps-") || strings.Contains(outputs[1], "\ndownloading\n") || strings.Contains(outputs[1], "Cache saved") {
+		t.Fatalf("unexpected output of the second run: %s", outputs[1])
+	}
+
+	build.Repository = "other"
+	var output bytes.Buffer
+	runFirstJob(t, executor, build, &output)
+	if !strings.Contains(output.String(), "\ndownloading\n") {
+		t.Fatalf("expected the cache of another repository not to be restored: %s", output.String())
+	}
+}
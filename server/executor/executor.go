@@ -0,0 +1,363 @@
+package executor
+
+import (
+	"archive/tar"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/cache"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+)
+
+const (
+	// DefaultStepTimeout is the maximum time a step may run when neither the
+	// step nor the executor has a timeout configured.
+	DefaultStepTimeout = time.Hour
+	// DefaultGracePeriod is the time a stopped step is given to exit after
+	// SIGTERM before it is killed, when the executor has none configured.
+	DefaultGracePeriod = 10 * time.Second
+	// outputWaitDelay bounds how long a killed step may keep its output open,
+	// e.g. when a child process escaped the step process group.
+	outputWaitDelay = 5 * time.Second
+	// shell is the interpreter used to run step commands. Steps run with -e so
+	// multi-line commands stop at the first failing line.
+	shell = "/bin/sh"
+)
+
+// Executor runs pipeline jobs as local subprocesses, each in its own workspace
+// under WorkspaceRoot.
+//
+// StepTimeout bounds the steps that do not declare their own timeout, and
+// GracePeriod is the time a stopped step is given to exit before it is killed.
+// The caches declared by jobs are kept in Cache. A nil Cache disables them.
+type Executor struct {
+	WorkspaceRoot string
+	StepTimeout   time.Duration
+	GracePeriod   time.Duration
+	Cache         *cache.Cache
+}
+
+// Build describes a single triggered build of a repository revision.
+type Build struct {
+	ID             string
+	Repository     string
+	RepositoryPath string
+	Revision       string
+	Branch         string
+	Tag            string
+	Parameters     map[string]string
+	Secrets        map[string]string
+	Pipeline       *pipeline.Pipeline
+}
+
+// StepResult holds the outcome of a single step. TimedOut reports whether the
+// step was stopped because its timeout, or the timeout of its job or build,
+// expired.
+type StepResult struct {
+	Name     string
+	ExitCode int
+	Err      error
+	TimedOut bool
+}
+
+// JobResult holds the outcome of a job and of each step that was run.
+type JobResult struct {
+	Name     string
+	Steps    []StepResult
+	Success  bool
+	TimedOut bool
+}
+
+// ExitCode returns the exit code of the last step run by the job.
+func (r JobResult) ExitCode() int {
+	if len(r.Steps) == 0 {
+		return 0
+	}
+	return r.Steps[len(r.Steps)-1].ExitCode
+}
+
+// NewExecutor creates an Executor that keeps its workspaces under workspaceRoot.
+func NewExecutor(workspaceRoot string) *Executor {
+	return &Executor{
+		WorkspaceRoot: workspaceRoot,
+		StepTimeout:   DefaultStepTimeout,
+		GracePeriod:   DefaultGracePeriod,
+	}
+}
+
+// PrepareWorkspace creates a fresh workspace with the given name and checks
+// out the build revision into it.
+//
+// Any leftover workspace with the same name is removed first.
+func (e *Executor) PrepareWorkspace(name string, build Build) (string, error) {
+	workspace, err := e.newWorkspace(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Remove(workspace); err != nil {
+		return "", fmt.Errorf("failed to clean workspace: %w", err)
+	}
+	if err := git.CloneAtRevision(build.RepositoryPath, workspace, build.Revision); err != nil {
+		return "", fmt.Errorf("failed to prepare workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// ExtractWorkspace creates a fresh workspace with the given name and extracts
+// a tar archive of the build sources into it. It is used where the repository
+// itself is not reachable, such as on remote runners.
+//
+// Any leftover workspace with the same name is removed first.
+func (e *Executor) ExtractWorkspace(name string, archive io.Reader) (string, error) {
+	workspace, err := e.newWorkspace(name)
+	if err != nil {
+		return "", err
+	}
+	if err := extractTar(workspace, archive); err != nil {
+		e.Cleanup(workspace)
+		return "", fmt.Errorf("failed to extract workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// newWorkspace creates an empty workspace directory with the given name,
+// removing any leftover one.
+func (e *Executor) newWorkspace(name string) (string, error) {
+	workspace := filepath.Join(e.WorkspaceRoot, name)
+	if err := os.RemoveAll(workspace); err != nil {
+		return "", fmt.Errorf("failed to clean workspace: %w", err)
+	}
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		return "", fmt.Errorf("failed to create workspace: %w", err)
+	}
+	return workspace, nil
+}
+
+// Cleanup removes a workspace and everything in it.
+func (e *Executor) Cleanup(workspace string) error {
+	if err := os.RemoveAll(workspace); err != nil {
+		return fmt.Errorf("failed to remove workspace: %w", err)
+	}
+	return nil
+}
+
+// RunJob runs the steps of a job in the given workspace, stopping at the first
+// step that fails.
+//
+// The cache of the job is restored before its steps run and, unless it was
+// restored, saved once they all succeeded. If the job declares a timeout, the
+// running step is stopped once it expires.
+func (e *Executor) RunJob(ctx context.Context, workspace string, build Build, job pipeline.Job, output io.Writer) JobResult {
+	result := JobResult{Name: job.Name, Success: true}
+	fmt.Fprintf(output, "==> Job %s\n", job.Name)
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+	cacheKey, restored := e.restoreCache(workspace, build, job, output)
+
+	for _, step := range job.Steps {
+		env := environment(workspace, build, job, step)
+		stepResult := e.runStep(ctx, workspace, step, env, output)
+		result.Steps = append(result.Steps, stepResult)
+		if stepResult.Err != nil || stepResult.ExitCode != 0 {
+			result.Success = false
+			result.TimedOut = stepResult.TimedOut
+			break
+		}
+	}
+	if result.Success && cacheKey != "" && !restored {
+		e.saveCache(workspace, cacheKey, job, output)
+	}
+	return result
+}
+
+// runStep runs the command of a step as a subprocess in its own process group.
+//
+// If the step exceeds its timeout, which defaults to the executor step timeout,
+// or ctx is cancelled, the whole process group is sent SIGTERM, then SIGKILL
+// if it is still running after the grace period, so that no child process
+// outlives the step.
+func (e *Executor) runStep(ctx context.Context, workspace string, step pipeline.Step, env []string, output io.Writer) StepResult {
+	result := StepResult{Name: stepName(step)}
+	fmt.Fprintf(output, "$ %s\n", step.Run)
+
+	timeout := cmp.Or(step.Timeout, e.StepTimeout)
+	if timeout <= 0 {
+		timeout = DefaultStepTimeout
+	}
+	gracePeriod := e.GracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(stepCtx, shell, "-e", "-c", step.Run)
+	cmd.Dir = workspace
+	cmd.Env = env
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	var kill *time.Timer
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		kill = time.AfterFunc(gracePeriod, func() {
+			syscall.Kill(-pgid, syscall.SIGKILL)
+		})
+		return syscall.Kill(-pgid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = gracePeriod + outputWaitDelay
+
+	err := cmd.Run()
+	if kill != nil {
+		// The step was stopped: kill what is left of its process group, such
+		// as children that ignored SIGTERM, right away.
+		kill.Stop()
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.ExitCode = 0
+	case errors.Is(stepCtx.Err(), context.DeadlineExceeded):
+		result.ExitCode = -1
+		result.Err = stepCtx.Err()
+		result.TimedOut = true
+		fmt.Fprintf(output, "Step %q timed out\n", result.Name)
+	case stepCtx.Err() != nil:
+		result.ExitCode = -1
+		result.Err = stepCtx.Err()
+		fmt.Fprintf(output, "Step %q cancelled\n", result.Name)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		fmt.Fprintf(output, "Step %q exited with code %d\n", result.Name, result.ExitCode)
+	default:
+		result.ExitCode = -1
+		result.Err = err
+		fmt.Fprintf(output, "Step %q failed to run: %v\n", result.Name, err)
+	}
+	return result
+}
+
+// environment builds the controlled environment of a step.
+//
+// The server environment is not inherited, so that its configuration and
+// secrets never leak into builds. Only PATH is kept, and the build information
+// is exposed through OPHELIA_CI_* variables, with each build parameter in an
+// OPHELIA_CI_PARAM_<NAME> variable. The secrets of the repository are exposed
+// under their own name. Pipeline, job and step variables are applied in that
+// order, each overriding the previous ones.
+func environment(workspace string, build Build, job pipeline.Job, step pipeline.Step) []string {
+	variables := map[string]string{
+		"PATH":                  os.Getenv("PATH"),
+		"HOME":                  workspace,
+		"CI":                    "true",
+		"OPHELIA_CI":            "true",
+		"OPHELIA_CI_BUILD_ID":   build.ID,
+		"OPHELIA_CI_REPOSITORY": build.Repository,
+		"OPHELIA_CI_COMMIT":     build.Revision,
+		"OPHELIA_CI_BRANCH":     build.Branch,
+		"OPHELIA_CI_TAG":        build.Tag,
+		"OPHELIA_CI_JOB":        job.Name,
+		"OPHELIA_CI_WORKSPACE":  workspace,
+	}
+	for name, value := range build.Parameters {
+		variables["OPHELIA_CI_PARAM_"+strings.ToUpper(name)] = value
+	}
+	for name, value := range build.Secrets {
+		variables[name] = value
+	}
+	for _, overrides := range []map[string]string{build.Pipeline.Env, job.Env, step.Env} {
+		for key, value := range overrides {
+			variables[key] = value
+		}
+	}
+
+	env := make([]string, 0, len(variables))
+	for key, value := range variables {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// extractTar extracts the regular files, directories and symbolic links of a
+// tar archive into root, rejecting entries that would be written outside of it,
+// either directly or through a symbolic link extracted before them.
+func extractTar(root string, archive io.Reader) error {
+	reader := tar.NewReader(archive)
+	symlinks := make(map[string]bool)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path %q in archive", header.Name)
+		}
+		for parent := filepath.Dir(name); parent != "."; parent = filepath.Dir(parent) {
+			if symlinks[parent] {
+				return fmt.Errorf("invalid path %q through symbolic link in archive", header.Name)
+			}
+		}
+		path := filepath.Join(root, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			err = writeFile(path, reader, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			symlinks[name] = true
+			if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+				err = os.Symlink(header.Linkname, path)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeFile writes the content read from reader to a new file at path.
+func writeFile(path string, reader io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// stepName returns the display name of a step, falling back to its command.
+func stepName(step pipeline.Step) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return step.Run
+}
@@ -0,0 +1,267 @@
+package executor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+)
+
+// createRepository creates a bare repository with a single commit containing
+// the given files and returns its path and the commit hash.
+func createRepository(t *testing.T, files map[string]string) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	source := filepath.Join(root, "source")
+	bare := filepath.Join(root, "repo.git")
+
+	run := func(dir string, args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	if err := os.MkdirAll(source, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(source, "init", "--quiet")
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(source, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run(source, "add", ".")
+	run(source, "commit", "--quiet", "-m", "test")
+	run(root, "clone", "--quiet", "--bare", source, bare)
+	return bare, run(source, "rev-parse", "HEAD")
+}
+
+func TestRunJob(t *testing.T) {
+	repoPath, commit := createRepository(t, map[string]string{"hello.txt": "hello"})
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: read
+        env: {GREETING: hi}
+        steps:
+          - run: cat hello.txt && echo " $GREETING from $OPHELIA_CI_JOB for $OPHELIA_CI_PARAM_VERSION"
+      - name: fail
+        steps:
+          - run: exit 3
+          - run: echo unreachable
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(t.TempDir())
+	build := Build{
+		ID:             "build",
+		RepositoryPath: repoPath,
+		Revision:       commit,
+		Parameters:     map[string]string{"version": "1.2.0"},
+		Pipeline:       definition,
+	}
+	workspace, err := executor.PrepareWorkspace(build.ID, build)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var output bytes.Buffer
+	jobs := definition.Jobs()
+	read := executor.RunJob(context.Background(), workspace, build, jobs[0], &output)
+	if !read.Success || read.ExitCode() != 0 {
+		t.Fatalf("unexpected result: %+v", read)
+	}
+	if !strings.Contains(output.String(), "hello hi from read for 1.2.0") {
+		t.Fatalf("unexpected output: %s", output.String())
+	}
+	fail := executor.RunJob(context.Background(), workspace, build, jobs[1], &output)
+	if fail.Success || fail.ExitCode() != 3 || len(fail.Steps) != 1 {
+		t.Fatalf("unexpected result: %+v", fail)
+	}
+
+	if err := executor.Cleanup(workspace); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(executor.WorkspaceRoot, "build")); !os.IsNotExist(err) {
+		t.Fatalf("workspace was not cleaned up: %v", err)
+	}
+}
+
+func TestRunJobStepTimeoutKillsProcessGroup(t *testing.T) {
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: slow
+        steps:
+          - run: sleep 30 & sleep 30
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(t.TempDir())
+	executor.StepTimeout = 100 * time.Millisecond
+	start := time.Now()
+	result := executor.RunJob(context.Background(), t.TempDir(), Build{Pipeline: definition}, definition.Jobs()[0], &bytes.Buffer{})
+	if result.Success || result.Steps[0].Err == nil || !result.TimedOut {
+		t.Fatalf("expected a timed out step, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("step was not killed in time: %v", elapsed)
+	}
+}
+
+func TestRunJobTimeoutKillsAfterGracePeriod(t *testing.T) {
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: stubborn
+        timeout: 100ms
+        steps:
+          - run: trap '' TERM; sleep 30
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewExecutor(t.TempDir())
+	executor.GracePeriod = 200 * time.Millisecond
+	start := time.Now()
+	var output bytes.Buffer
+	result := executor.RunJob(context.Background(), t.TempDir(), Build{Pipeline: definition}, definition.Jobs()[0], &output)
+	if result.Success || !result.TimedOut || !strings.Contains(output.String(), `Step "trap '' TERM; sleep 30" timed out`) {
+		t.Fatalf("expected a timed out job, got %+v\n%s", result, output.String())
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("step ignoring SIGTERM was not killed in time: %v", elapsed)
+	}
+}
+
+func TestRunJobCancelTerminatesGracefully(t *testing.T) {
+	definition, err := pipeline.Parse([]byte(`
+stages:
+  - name: test
+    jobs:
+      - name: graceful
+        steps:
+          - run: trap 'echo cleaning up; exit 0' TERM; sleep 30 & wait
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	var output bytes.Buffer
+	result := NewExecutor(t.TempDir()).RunJob(ctx, t.TempDir(), Build{Pipeline: definition}, definition.Jobs()[0], &output)
+	if result.Success || result.TimedOut || !strings.Contains(output.String(), "cleaning up") || !strings.Contains(output.String(), "cancelled") {
+		t.Fatalf("expected a gracefully cancelled job, got %+v\n%s", result, output.String())
+	}
+}
+
+func TestExtractWorkspace(t *testing.T) {
+	repoPath, commit := createRepository(t, map[string]string{"hello.txt": "hello\n"})
+	var archive bytes.Buffer
+	if err := git.ArchiveAtRevision(repoPath, commit, &archive); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewExecutor(t.TempDir())
+	workspace, err := e.ExtractWorkspace("lease", &archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(workspace, "hello.txt"))
+	if err != nil || string(content) != "hello\n" {
+		t.Fatalf("unexpected content %q: %v", content, err)
+	}
+}
+
+func TestExtractWorkspaceRejectsEscapingPaths(t *testing.T) {
+	for _, entries := range [][]tar.Header{
+		{{Name: "../escaped", Typeflag: tar.TypeReg}},
+		{{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/tmp"}, {Name: "link/escaped", Typeflag: tar.TypeReg}},
+	} {
+		var archive bytes.Buffer
+		writer := tar.NewWriter(&archive)
+		for _, header := range entries {
+			if err := writer.WriteHeader(&header); err != nil {
+				t.Fatal(err)
+			}
+		}
+		writer.Close()
+
+		e := NewExecutor(t.TempDir())
+		if _, err := e.ExtractWorkspace("lease", &archive); err == nil {
+			t.Fatalf("archive with %v should be rejected", entries[len(entries)-1].Name)
+		}
+	}
+}
+
+func TestArchiveArtifacts(t *testing.T) {
+	workspace := t.TempDir()
+	for name, content := range map[string]string{"bin/app": "app", "report/unit.xml": "<xml/>", "notes.txt": "notes"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(workspace, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(workspace, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	job := pipeline.Job{Steps: []pipeline.Step{
+		{Run: "make", Artifacts: []string{"bin/*", "missing/*"}},
+		{Run: "make test", Artifacts: []string{"report"}},
+		{Run: "make notes", Artifacts: []string{"notes.txt"}},
+	}}
+
+	// The last step did not run, so its artifacts are not collected.
+	files, err := ArtifactFiles(workspace, job, JobResult{Steps: make([]StepResult, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(files, ",") != "bin/app,report/unit.xml" {
+		t.Fatalf("unexpected artifact files: %v", files)
+	}
+
+	var archive bytes.Buffer
+	if err := ArchiveArtifacts(workspace, files, &archive); err != nil {
+		t.Fatal(err)
+	}
+	extracted, err := NewExecutor(t.TempDir()).ExtractWorkspace("artifacts", gunzip(t, &archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(extracted, "report", "unit.xml"))
+	if err != nil || string(content) != "<xml/>" {
+		t.Fatalf("unexpected content %q: %v", content, err)
+	}
+}
+
+// gunzip returns a reader of the decompressed content of a gzip stream.
+func gunzip(t *testing.T, compressed io.Reader) io.Reader {
+	t.Helper()
+	reader, err := gzip.NewReader(compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
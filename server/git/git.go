@@ -1,8 +1,11 @@
 package git
 
 import (
+	"crypto/rand"
 	"embed"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -17,6 +20,29 @@ import (
 //go:embed templates/*
 var templates embed.FS
 
+// hookSecretKey is the Git configuration key under which the secret used by the
+// post-receive hook to authenticate its commit signals is stored.
+const hookSecretKey = "ophelia-ci.hooksecret"
+
+// ErrFileNotFound is returned when a file does not exist at the requested revision.
+var ErrFileNotFound = errors.New("file not found at revision")
+
+// ErrInvalidRevision is returned when a revision that must be a commit hash is
+// not a full hex object id.
+var ErrInvalidRevision = errors.New("revision is not a full object id")
+
+// objectIdPattern matches the full hex object ids of SHA-1 and SHA-256
+// repositories.
+var objectIdPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// IsObjectId reports whether revision is a full hex object id, such as the
+// commit hashes sent by the post-receive hook or the zero id sent for new refs.
+// Revisions received from clients are checked with it before they are passed
+// to Git, so that they cannot be read as options.
+func IsObjectId(revision string) bool {
+	return objectIdPattern.MatchString(revision)
+}
+
 // CreateGitRepository initializes a new bare Git repository at the specified path
 // and sets up a post-receive hook.
 //
@@ -24,6 +50,7 @@ var templates embed.FS
 // 	1. Creates a bare Git repository in the given directory.
 // 	2. Reads the post-receive template content from the embedded templates.
 // 	3. Creates a post-receive hook using the template content.
+// 	4. Stores a random hook secret in the repository configuration.
 //
 // If any step fails, an error is returned with details.
 func CreateGitRepository(repoPath, gitignore string) error {
@@ -39,6 +66,56 @@ func CreateGitRepository(repoPath, gitignore string) error {
 		return fmt.Errorf("failed creating post-receive hook: %w", err)
 	}
 
+	if err := ensureHookSecret(repoPath); err != nil {
+		return fmt.Errorf("failed creating hook secret: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePostReceiveHook replaces the post-receive hook of an existing bare Git
+// repository with the current template, and creates its hook secret if it has
+// none, so that repositories created by older versions send complete and
+// authenticated commit signals.
+//
+// If the hook or its secret cannot be written, an error is returned with details.
+func UpdatePostReceiveHook(repoPath string) error {
+	if err := createPostReceiveHook(repoPath); err != nil {
+		return fmt.Errorf("failed updating post-receive hook: %w", err)
+	}
+	if err := ensureHookSecret(repoPath); err != nil {
+		return fmt.Errorf("failed creating hook secret: %w", err)
+	}
+	return nil
+}
+
+// HookSecret returns the secret the post-receive hook of a bare Git repository
+// sends with its commit signals, as stored in the repository configuration.
+//
+// If the repository has no hook secret, an error is returned.
+func HookSecret(repoPath string) (string, error) {
+	cmd := exec.Command("git", "--git-dir", repoPath, "config", "--get", hookSecretKey)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read hook secret: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ensureHookSecret stores a random hook secret in the configuration of a bare
+// Git repository, unless it already has one.
+func ensureHookSecret(repoPath string) error {
+	if _, err := HookSecret(repoPath); err == nil {
+		return nil
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	cmd := exec.Command("git", "--git-dir", repoPath, "config", hookSecretKey, hex.EncodeToString(secret))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w\n%s", err, output)
+	}
 	return nil
 }
 
@@ -70,6 +147,148 @@ func DeleteGitRepository(repoPath string) error {
 	return nil
 }
 
+// ReadFileAtRevision reads the content of a file as it exists at the given
+// revision of a bare Git repository.
+//
+// The function will:
+//   - Resolve the revision to a commit with ResolveRevision
+//   - Check that the file exists at the commit with `git cat-file -e`
+//   - Return the file content with `git show <commit>:<filePath>`
+//
+// If the file does not exist at the revision, ErrFileNotFound is returned.
+// Any other failure, such as a revision that does not exist, is returned as is.
+func ReadFileAtRevision(repoPath, revision, filePath string) ([]byte, error) {
+	commit, err := ResolveRevision(repoPath, revision)
+	if err != nil {
+		return nil, err
+	}
+	object := fmt.Sprintf("%s:%s", commit, filePath)
+
+	existsCmd := exec.Command("git", "--git-dir", repoPath, "cat-file", "-e", object)
+	var exitErr *exec.ExitError
+	if err := existsCmd.Run(); errors.As(err, &exitErr) {
+		return nil, ErrFileNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to check %s: %w", object, err)
+	}
+
+	showCmd := exec.Command("git", "--git-dir", repoPath, "show", object)
+	content, err := showCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", object, err)
+	}
+	return content, nil
+}
+
+// ResolveRevision resolves a revision of a bare Git repository, such as a
+// branch name, to the hash of the commit it points to.
+//
+// If the revision does not exist, an error is returned.
+func ResolveRevision(repoPath, revision string) (string, error) {
+	cmd := exec.Command("git", "--git-dir", repoPath, "rev-parse", "--verify", "--quiet", revision+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve revision %s: %w", revision, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsAncestor reports whether the commit ancestor is reachable from revision in
+// a bare Git repository, with `git merge-base --is-ancestor`. A ref update whose
+// old commit is not an ancestor of the new one is a force-push.
+//
+// If a commit is not a full object id, ErrInvalidRevision is returned. If the
+// commits cannot be compared, an error is returned with details.
+func IsAncestor(repoPath, ancestor, revision string) (bool, error) {
+	if !IsObjectId(ancestor) || !IsObjectId(revision) {
+		return false, fmt.Errorf("%w: %q, %q", ErrInvalidRevision, ancestor, revision)
+	}
+	cmd := exec.Command("git", "--git-dir", repoPath, "merge-base", "--is-ancestor", "--end-of-options", ancestor, revision)
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to compare %s and %s: %w", ancestor, revision, err)
+	}
+	return true, nil
+}
+
+// ChangedFiles lists the files changed between two revisions of a bare Git
+// repository, with `git diff --name-only`.
+//
+// If oldRevision is empty or the null revision sent for new refs, the files
+// changed by newRevision itself are listed instead.
+//
+// If a revision is not a full object id, ErrInvalidRevision is returned. If the
+// revisions cannot be compared, an error is returned with details.
+func ChangedFiles(repoPath, oldRevision, newRevision string) ([]string, error) {
+	if !IsObjectId(newRevision) || (oldRevision != "" && !IsObjectId(oldRevision)) {
+		return nil, fmt.Errorf("%w: %q, %q", ErrInvalidRevision, oldRevision, newRevision)
+	}
+	cmd := exec.Command("git", "--git-dir", repoPath, "diff", "--name-only", "-z", "--end-of-options", oldRevision, newRevision)
+	if strings.Trim(oldRevision, "0") == "" {
+		cmd = exec.Command("git", "--git-dir", repoPath, "diff-tree", "--no-commit-id", "--name-only", "-z", "-r", "--root", "--end-of-options", newRevision)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files changed by %s: %w", newRevision, err)
+	}
+	return strings.FieldsFunc(string(output), func(r rune) bool { return r == 0 }), nil
+}
+
+// DefaultBranch returns the name of the default branch of a bare Git
+// repository, which its HEAD points to.
+//
+// If HEAD does not point to a branch, an error is returned.
+func DefaultBranch(repoPath string) (string, error) {
+	cmd := exec.Command("git", "--git-dir", repoPath, "symbolic-ref", "--short", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read default branch: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// CloneAtRevision clones the repository at repoPath into workspacePath and
+// checks out the given revision in detached HEAD mode.
+//
+// The commands run with their working directory set explicitly, so the
+// process working directory is never changed and concurrent clones are safe.
+//
+// If any of the steps fail, an error is returned with details.
+func CloneAtRevision(repoPath, workspacePath, revision string) error {
+	cloneCmd := exec.Command("git", "clone", "--quiet", "--no-checkout", repoPath, workspacePath)
+	cloneOutput, err := cloneCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to clone %s: %w\n%s", repoPath, err, cloneOutput)
+	}
+
+	checkoutCmd := exec.Command("git", "checkout", "--quiet", "--detach", revision)
+	checkoutCmd.Dir = workspacePath
+	checkoutOutput, err := checkoutCmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to checkout %s: %w\n%s", revision, err, checkoutOutput)
+	}
+	return nil
+}
+
+// ArchiveAtRevision writes a tar archive of the files of a bare Git repository
+// at the given revision to output, with `git archive`.
+//
+// If the archive cannot be created, an error is returned with details.
+func ArchiveAtRevision(repoPath, revision string, output io.Writer) error {
+	var stderr strings.Builder
+	cmd := exec.Command("git", "--git-dir", repoPath, "archive", "--format=tar", revision)
+	cmd.Stdout = output
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to archive %s: %w\n%s", revision, err, stderr.String())
+	}
+	return nil
+}
+
 // createBareGitRepository creates a bare Git repository at the given path.
 //
 // The function will:
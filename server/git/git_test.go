@@ -0,0 +1,136 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// createHistory creates a repository with two commits, the first adding README
+// and the second adding main.go, and returns its Git directory with the hashes
+// of both commits.
+func createHistory(t *testing.T) (repoPath, first, second string) {
+	t.Helper()
+	work := t.TempDir()
+	runGit(t, work, "init", "--quiet")
+	if err := os.WriteFile(filepath.Join(work, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", "README")
+	runGit(t, work, "commit", "--quiet", "-m", "Add README")
+	first = strings.TrimSpace(runGit(t, work, "rev-parse", "HEAD"))
+	if err := os.WriteFile(filepath.Join(work, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, work, "add", "main.go")
+	runGit(t, work, "commit", "--quiet", "-m", "Add main.go")
+	second = strings.TrimSpace(runGit(t, work, "rev-parse", "HEAD"))
+	return filepath.Join(work, ".git"), first, second
+}
+
+func TestChangedFiles(t *testing.T) {
+	repoPath, first, second := createHistory(t)
+
+	tests := []struct {
+		name        string
+		oldRevision string
+		expected    []string
+	}{
+		{name: "Update", oldRevision: first, expected: []string{"main.go"}},
+		{name: "New ref", oldRevision: strings.Repeat("0", 40), expected: []string{"main.go"}},
+		{name: "No old revision", oldRevision: "", expected: []string{"main.go"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files, err := ChangedFiles(repoPath, test.oldRevision, second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(files, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, files)
+			}
+		})
+	}
+}
+
+func TestChangedFilesRejectsInvalidRevisions(t *testing.T) {
+	repoPath, first, second := createHistory(t)
+	output := filepath.Join(t.TempDir(), "output")
+
+	for _, revisions := range [][2]string{
+		{"--output=" + output, second},
+		{first, "--output=" + output},
+		{"main", second},
+		{first[:7], second},
+	} {
+		_, err := ChangedFiles(repoPath, revisions[0], revisions[1])
+		if !errors.Is(err, ErrInvalidRevision) {
+			t.Errorf("expected ErrInvalidRevision for %q, got %v", revisions, err)
+		}
+	}
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("expected no file written by git, got %v", err)
+	}
+}
+
+func TestIsObjectId(t *testing.T) {
+	tests := []struct {
+		revision string
+		expected bool
+	}{
+		{strings.Repeat("a1", 20), true},
+		{strings.Repeat("0", 40), true},
+		{strings.Repeat("b2", 32), true},
+		{"", false},
+		{"main", false},
+		{strings.Repeat("a", 39), false},
+		{strings.Repeat("A", 40), false},
+		{"--output=" + strings.Repeat("a", 31), false},
+	}
+	for _, test := range tests {
+		if got := IsObjectId(test.revision); got != test.expected {
+			t.Errorf("IsObjectId(%q): expected %v, got %v", test.revision, test.expected, got)
+		}
+	}
+}
+
+func TestIsAncestor(t *testing.T) {
+	repoPath, first, second := createHistory(t)
+
+	if isAncestor, err := IsAncestor(repoPath, first, second); err != nil || !isAncestor {
+		t.Errorf("expected the first commit to be an ancestor of the second, got %v (%v)", isAncestor, err)
+	}
+	if isAncestor, err := IsAncestor(repoPath, second, first); err != nil || isAncestor {
+		t.Errorf("expected the second commit not to be an ancestor of the first, got %v (%v)", isAncestor, err)
+	}
+	for _, ancestor := range []string{"--output=" + filepath.Join(t.TempDir(), "output"), "HEAD~1", ""} {
+		if _, err := IsAncestor(repoPath, ancestor, second); !errors.Is(err, ErrInvalidRevision) {
+			t.Errorf("expected ErrInvalidRevision for %q, got %v", ancestor, err)
+		}
+	}
+}
+
+func TestReadFileAtRevision(t *testing.T) {
+	repoPath, first, second := createHistory(t)
+
+	if content, err := ReadFileAtRevision(repoPath, second, "main.go"); err != nil || string(content) != "package main\n" {
+		t.Fatalf("expected the content of main.go, got %q (%v)", content, err)
+	}
+	if content, err := ReadFileAtRevision(repoPath, "HEAD", "README"); err != nil || string(content) != "hello\n" {
+		t.Fatalf("expected the content of README, got %q (%v)", content, err)
+	}
+	if _, err := ReadFileAtRevision(repoPath, first, "main.go"); !errors.Is(err, ErrFileNotFound) {
+		t.Errorf("expected ErrFileNotFound for a file added later, got %v", err)
+	}
+
+	missing := strings.Repeat("1", 40)
+	if _, err := ReadFileAtRevision(repoPath, missing, "main.go"); err == nil || errors.Is(err, ErrFileNotFound) {
+		t.Errorf("expected the error of a missing revision, got %v", err)
+	}
+	if _, err := ReadFileAtRevision(filepath.Join(t.TempDir(), "missing.git"), second, "main.go"); err == nil || errors.Is(err, ErrFileNotFound) {
+		t.Errorf("expected the error of a missing repository, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package git
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// UploadPack is the Git service fetching from a repository.
+	UploadPack = "git-upload-pack"
+	// ReceivePack is the Git service pushing to a repository.
+	ReceivePack = "git-receive-pack"
+
+	// infoRefsPath is the path, relative to a repository, under which the
+	// refs of the repository are advertised.
+	infoRefsPath = "info/refs"
+)
+
+// HTTPHandler serves bare Git repositories with the Git smart HTTP protocol,
+// so that they can be cloned, fetched and pushed with
+// `git clone https://<host>/<name>.git`.
+//
+// The dumb HTTP protocol is not supported. Pushes run the hooks of the
+// repository, with OPHELIA_CI_PUSHER set to the authenticated user.
+type HTTPHandler struct {
+	// RepoPath returns the path of the bare repository with the given name,
+	// or an error if there is no such repository.
+	RepoPath func(name string) (string, error)
+	// Authenticate returns the user making a request, or an error if its
+	// credentials are missing or invalid.
+	Authenticate func(r *http.Request) (string, error)
+	// Authorize returns an error unless the user may run the given service on
+	// the named repository. Every authenticated user may if it is nil.
+	Authorize func(username, name, service string) error
+}
+
+// ServeHTTP serves the refs advertisement and the upload-pack and
+// receive-pack services of a repository.
+//
+// Requests without valid credentials are answered with 401 Unauthorized and a
+// Basic challenge, so that Git asks for credentials, and requests the user may
+// not make with 403 Forbidden, before the repository is looked up.
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, action, ok := parseHTTPPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	service := action
+	if action == infoRefsPath {
+		service = r.URL.Query().Get("service")
+		if service != UploadPack && service != ReceivePack {
+			http.Error(w, "only the smart HTTP protocol is supported", http.StatusForbidden)
+			return
+		}
+	}
+	if (action == infoRefsPath && r.Method != http.MethodGet) || (action != infoRefsPath && r.Method != http.MethodPost) {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	username, err := h.Authenticate(r)
+	if err != nil {
+		log.Printf("Rejected %v of %v over HTTP: %v", service, name, err)
+		w.Header().Set("WWW-Authenticate", `Basic realm="Ophelia CI"`)
+		http.Error(w, "authentication required", http.StatusUnauthorized)
+		return
+	}
+	if h.Authorize != nil {
+		if err := h.Authorize(username, name, service); err != nil {
+			log.Printf("Denied %v of %v over HTTP to %v: %v", service, name, username, err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	repoPath, err := h.RepoPath(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	if action == infoRefsPath {
+		h.advertiseRefs(w, r, repoPath, service, username)
+		return
+	}
+	h.serviceRPC(w, r, repoPath, service, username)
+}
+
+// advertiseRefs answers the info/refs request starting a fetch or a push with
+// the refs of the repository.
+func (h *HTTPHandler) advertiseRefs(w http.ResponseWriter, r *http.Request, repoPath, service, username string) {
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-advertisement", service))
+	// Protocol version 2 clients expect the capabilities without the service
+	// announcement.
+	if !strings.Contains(r.Header.Get("Git-Protocol"), "version=2") {
+		announcement := fmt.Sprintf("# service=%s\n", service)
+		fmt.Fprintf(w, "%04x%s0000", len(announcement)+4, announcement)
+	}
+	if err := runService(r, w, nil, repoPath, service, username, "--advertise-refs"); err != nil {
+		log.Printf("Error advertising refs of %v: %v", repoPath, err)
+	}
+}
+
+// serviceRPC runs the upload-pack or receive-pack service with the request
+// sent by the client.
+func (h *HTTPHandler) serviceRPC(w http.ResponseWriter, r *http.Request, repoPath, service, username string) {
+	if r.Header.Get("Content-Type") != fmt.Sprintf("application/x-%s-request", service) {
+		http.Error(w, "unexpected content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	body := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			http.Error(w, "invalid gzip body", http.StatusBadRequest)
+			return
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-result", service))
+	if err := runService(r, w, body, repoPath, service, username); err != nil {
+		log.Printf("Error running %v for %v: %v", service, repoPath, err)
+	}
+}
+
+// runService runs a Git service in stateless RPC mode on a repository, with
+// the request body as its input and the response as its output.
+func runService(r *http.Request, output io.Writer, input io.Reader, repoPath, service, username string, args ...string) error {
+	args = append([]string{strings.TrimPrefix(service, "git-"), "--stateless-rpc"}, args...)
+	var stderr strings.Builder
+	cmd := exec.CommandContext(r.Context(), "git", append(args, repoPath)...)
+	cmd.Env = serviceEnv(username)
+	if protocol := r.Header.Get("Git-Protocol"); protocol != "" {
+		cmd.Env = append(cmd.Env, "GIT_PROTOCOL="+protocol)
+	}
+	cmd.Stdin = input
+	cmd.Stdout = output
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w\n%s", err, stderr.String())
+	}
+	return nil
+}
+
+// serviceEnv returns the environment of a Git service run for a user.
+//
+// Only the variables Git and the post-receive hook need are taken from the
+// server environment: PATH, HOME, the Git variables and the Ophelia CI client
+// settings the hook sends its commit signals with. The other variables, such as
+// the server secret, are not exposed to the hooks. The user is passed to the
+// hook as the pusher of the commits.
+func serviceEnv(username string) []string {
+	var env []string
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if name == "PATH" || name == "HOME" || strings.HasPrefix(name, "GIT_") || strings.HasPrefix(name, "OPHELIA_CI_") {
+			env = append(env, variable)
+		}
+	}
+	return append(env, "OPHELIA_CI_PUSHER="+username)
+}
+
+// parseHTTPPath splits the path of a smart HTTP request into the name of the
+// repository, with its ".git" suffix removed, and the action requested.
+//
+// Returns false if the path is not a smart HTTP request for a repository.
+func parseHTTPPath(path string) (name, action string, ok bool) {
+	for _, action := range []string{infoRefsPath, UploadPack, ReceivePack} {
+		prefix, found := strings.CutSuffix(path, "/"+action)
+		if !found {
+			continue
+		}
+		if name, ok := repositoryName(prefix); ok {
+			return name, action, true
+		}
+		return "", "", false
+	}
+	return "", "", false
+}
+
+// repositoryName returns the name of the repository a path sent by a Git
+// client refers to, without its leading slash and ".git" suffix.
+//
+// Returns false if the path does not name a single repository.
+func repositoryName(path string) (string, bool) {
+	name := strings.TrimSuffix(strings.TrimPrefix(path, "/"), ".git")
+	if name == "" || filepath.Base(name) != name || strings.HasPrefix(name, ".") {
+		return "", false
+	}
+	return name, true
+}
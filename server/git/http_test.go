@@ -0,0 +1,163 @@
+package git
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runGit runs a Git command in dir, failing the test if it fails.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := gitCommand(dir, args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
+
+// gitCommand returns a Git command run in dir, which never prompts for
+// credentials.
+func gitCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	return cmd
+}
+
+func TestHTTPHandlerClonesAndPushes(t *testing.T) {
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "project.git")
+	runGit(t, root, "init", "--quiet", "--bare", repoPath)
+	pusherFile := filepath.Join(root, "pusher")
+	hook := "#!/bin/sh\necho \"$OPHELIA_CI_PUSHER\" > " + pusherFile + "\n"
+	if err := os.WriteFile(filepath.Join(repoPath, "hooks", "post-receive"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &HTTPHandler{
+		RepoPath: func(name string) (string, error) {
+			if name != "project" {
+				return "", errors.New("repository not found")
+			}
+			return repoPath, nil
+		},
+		Authenticate: func(r *http.Request) (string, error) {
+			if _, password, _ := r.BasicAuth(); password != "token" {
+				return "", errors.New("invalid token")
+			}
+			return "alice", nil
+		},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	authenticatedURL := strings.Replace(server.URL, "http://", "http://git:token@", 1)
+
+	work := t.TempDir()
+	if output, err := gitCommand(work, "clone", server.URL+"/project.git", "anonymous").CombinedOutput(); err == nil {
+		t.Fatalf("expected a clone without credentials to fail, got:\n%s", output)
+	}
+	if output, err := gitCommand(work, "clone", authenticatedURL+"/missing.git", "missing").CombinedOutput(); err == nil {
+		t.Fatalf("expected a clone of a missing repository to fail, got:\n%s", output)
+	}
+
+	runGit(t, work, "clone", "--quiet", authenticatedURL+"/project.git", "clone")
+	clone := filepath.Join(work, "clone")
+	if err := os.WriteFile(filepath.Join(clone, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, clone, "add", "README")
+	runGit(t, clone, "commit", "--quiet", "-m", "Add README")
+	runGit(t, clone, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+
+	pushed := strings.TrimSpace(runGit(t, clone, "rev-parse", "HEAD"))
+	if revision, err := ResolveRevision(repoPath, "main"); err != nil || revision != pushed {
+		t.Fatalf("expected main to be %s, got %q (%v)", pushed, revision, err)
+	}
+	if pusher, err := os.ReadFile(pusherFile); err != nil || string(pusher) != "alice\n" {
+		t.Fatalf("expected the post-receive hook to run for alice, got %q (%v)", pusher, err)
+	}
+
+	runGit(t, work, "clone", "--quiet", "--branch", "main", authenticatedURL+"/project", "again")
+	if content, err := os.ReadFile(filepath.Join(work, "again", "README")); err != nil || string(content) != "hello\n" {
+		t.Fatalf("expected the pushed file in a new clone, got %q (%v)", content, err)
+	}
+}
+
+func TestServiceEnv(t *testing.T) {
+	t.Setenv("APP_OPHELIA_CI_SERVER_SECRET", "secret")
+	t.Setenv("GIT_TRACE", "0")
+	t.Setenv("OPHELIA_CI_SERVER", "localhost:50051")
+	t.Setenv("OPHELIA_CI_PUSHER", "mallory")
+
+	variables := make(map[string]string)
+	for _, variable := range serviceEnv("alice") {
+		name, value, _ := strings.Cut(variable, "=")
+		variables[name] = value
+	}
+	if _, ok := variables["APP_OPHELIA_CI_SERVER_SECRET"]; ok {
+		t.Error("expected the server secret not to be passed to Git")
+	}
+	if variables["PATH"] != os.Getenv("PATH") || variables["GIT_TRACE"] != "0" || variables["OPHELIA_CI_SERVER"] != "localhost:50051" {
+		t.Errorf("expected the variables needed by Git and the hook, got %v", variables)
+	}
+	if variables["OPHELIA_CI_PUSHER"] != "alice" {
+		t.Errorf("expected alice to be the pusher, got %q", variables["OPHELIA_CI_PUSHER"])
+	}
+}
+
+func TestParseHTTPPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		name   string
+		action string
+		ok     bool
+	}{
+		{"/project.git/info/refs", "project", infoRefsPath, true},
+		{"/project/git-upload-pack", "project", UploadPack, true},
+		{"/project.git/git-receive-pack", "project", ReceivePack, true},
+		{"/group/project.git/info/refs", "", "", false},
+		{"/../project.git/info/refs", "", "", false},
+		{"/.git/info/refs", "", "", false},
+		{"/project.git/HEAD", "", "", false},
+	}
+	for _, test := range tests {
+		name, action, ok := parseHTTPPath(test.path)
+		if name != test.name || action != test.action || ok != test.ok {
+			t.Errorf("parseHTTPPath(%q) = %q, %q, %v, expected %q, %q, %v", test.path, name, action, ok, test.name, test.action, test.ok)
+		}
+	}
+}
+
+func TestHTTPHandlerAuthorizes(t *testing.T) {
+	handler := &HTTPHandler{
+		RepoPath: func(name string) (string, error) {
+			return "", errors.New("repository not found")
+		},
+		Authenticate: func(r *http.Request) (string, error) {
+			return "alice", nil
+		},
+		Authorize: func(username, name, service string) error {
+			if service == ReceivePack {
+				return errors.New("git-receive-pack needs the DEVELOPER role")
+			}
+			return nil
+		},
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/project.git/info/refs?service=git-receive-pack", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected a push to be forbidden, got status %d", recorder.Code)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/project.git/info/refs?service=git-upload-pack", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected an authorized fetch to reach the repository lookup, got status %d", recorder.Code)
+	}
+}
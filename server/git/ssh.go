@@ -0,0 +1,246 @@
+package git
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshHandshakeTimeout bounds how long a client may take to authenticate.
+	sshHandshakeTimeout = 30 * time.Second
+	// usernameExtension is the permission extension holding the username of
+	// an authenticated SSH connection.
+	usernameExtension = "ophelia-ci-username"
+)
+
+// SSHServer serves bare Git repositories over SSH, so that they can be cloned,
+// fetched and pushed with `git clone ssh://<user>@<host>:<port>/<name>.git`.
+//
+// Clients authenticate with a public key, as the user they connect as. Only
+// the git-upload-pack and git-receive-pack commands are run; shells, other
+// commands, subsystems and port forwarding are rejected. Pushes run the hooks
+// of the repository, with OPHELIA_CI_PUSHER set to the authenticated user.
+type SSHServer struct {
+	// HostKey is the key the server identifies itself with.
+	HostKey ssh.Signer
+	// RepoPath returns the path of the bare repository with the given name,
+	// or an error if there is no such repository.
+	RepoPath func(name string) (string, error)
+	// Authenticate returns an error unless key is a public key of the user.
+	Authenticate func(username string, key ssh.PublicKey) error
+	// Authorize returns an error unless the user may run the given service on
+	// the named repository. Every authenticated user may if it is nil.
+	Authorize func(username, name, service string) error
+}
+
+// Serve accepts SSH connections on listener, serving each one in its own
+// goroutine, until the listener fails.
+func (s *SSHServer) Serve(listener net.Listener) error {
+	config := &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-OpheliaCI",
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if err := s.Authenticate(conn.User(), key); err != nil {
+				log.Printf("Rejected SSH key %v of %v from %v: %v", ssh.FingerprintSHA256(key), conn.User(), conn.RemoteAddr(), err)
+				return nil, err
+			}
+			return &ssh.Permissions{Extensions: map[string]string{usernameExtension: conn.User()}}, nil
+		},
+	}
+	config.AddHostKey(s.HostKey)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handleConn(conn, config)
+	}
+}
+
+// handleConn authenticates an SSH connection and serves its sessions.
+func (s *SSHServer) handleConn(conn net.Conn, config *ssh.ServerConfig) {
+	conn.SetDeadline(time.Now().Add(sshHandshakeTimeout))
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		log.Printf("SSH handshake with %v failed: %v", conn.RemoteAddr(), err)
+		conn.Close()
+		return
+	}
+	conn.SetDeadline(time.Time{})
+	defer sshConn.Close()
+	username := sshConn.Permissions.Extensions[usernameExtension]
+
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			log.Printf("Error accepting SSH session of %v: %v", username, err)
+			continue
+		}
+		go s.handleSession(channel, channelRequests, username)
+	}
+}
+
+// handleSession runs the Git command requested in an SSH session, and sends
+// its exit status.
+//
+// Only the GIT_PROTOCOL environment variable is accepted, so that clients can
+// use protocol version 2.
+func (s *SSHServer) handleSession(channel ssh.Channel, requests <-chan *ssh.Request, username string) {
+	defer channel.Close()
+	var env []string
+	for req := range requests {
+		switch req.Type {
+		case "env":
+			var variable struct{ Name, Value string }
+			accepted := ssh.Unmarshal(req.Payload, &variable) == nil && variable.Name == "GIT_PROTOCOL"
+			if accepted {
+				env = append(env, "GIT_PROTOCOL="+variable.Value)
+			}
+			req.Reply(accepted, nil)
+		case "exec":
+			var command struct{ Command string }
+			if err := ssh.Unmarshal(req.Payload, &command); err != nil {
+				req.Reply(false, nil)
+				return
+			}
+			req.Reply(true, nil)
+			go ssh.DiscardRequests(requests)
+			status := s.exec(channel, command.Command, username, env)
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			return
+		case "shell":
+			fmt.Fprintf(channel.Stderr(), "Hi %s! You've successfully authenticated, but Ophelia CI does not provide shell access.\n", username)
+			req.Reply(false, nil)
+			return
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// exec runs a Git command sent by an SSH client on the repository it names,
+// with the session as its input and output.
+//
+// Returns the exit status of the command.
+func (s *SSHServer) exec(channel ssh.Channel, command, username string, env []string) uint32 {
+	name, service, err := parseSSHCommand(command)
+	if err != nil {
+		log.Printf("Rejected SSH command %q of %v: %v", command, username, err)
+		fmt.Fprintf(channel.Stderr(), "fatal: %v\n", err)
+		return 128
+	}
+	if s.Authorize != nil {
+		if err := s.Authorize(username, name, service); err != nil {
+			log.Printf("Denied %v of %v over SSH to %v: %v", service, name, username, err)
+			fmt.Fprintf(channel.Stderr(), "fatal: %v\n", err)
+			return 128
+		}
+	}
+	repoPath, err := s.RepoPath(name)
+	if err != nil {
+		fmt.Fprintf(channel.Stderr(), "fatal: repository %s not found\n", name)
+		return 128
+	}
+	log.Printf("Running %v of %v over SSH for %v", service, name, username)
+
+	cmd := exec.Command("git", strings.TrimPrefix(service, "git-"), repoPath)
+	cmd.Env = append(serviceEnv(username), env...)
+	cmd.Stdout = channel
+	cmd.Stderr = channel.Stderr()
+	// The input is copied by hand, as the client may keep the session open
+	// after the command exits, and Wait would then block on the copy.
+	stdin, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		log.Printf("Error starting %v for %v: %v", service, repoPath, err)
+		return 128
+	}
+	go func() {
+		io.Copy(stdin, channel)
+		stdin.Close()
+	}()
+
+	var exitErr *exec.ExitError
+	if err := cmd.Wait(); errors.As(err, &exitErr) {
+		return uint32(exitErr.ExitCode())
+	} else if err != nil {
+		log.Printf("Error running %v for %v: %v", service, repoPath, err)
+		return 128
+	}
+	return 0
+}
+
+// parseSSHCommand parses the command sent by a Git client over SSH, such as
+// `git-upload-pack '/project.git'`, into the name of the repository and the
+// Git service requested.
+//
+// Returns an error for any other command.
+func parseSSHCommand(command string) (name, service string, err error) {
+	service, path, _ := strings.Cut(command, " ")
+	if service != UploadPack && service != ReceivePack {
+		return "", "", fmt.Errorf("only %s and %s are supported", UploadPack, ReceivePack)
+	}
+	if len(path) >= 2 && path[0] == '\'' && path[len(path)-1] == '\'' {
+		path = path[1 : len(path)-1]
+	}
+	name, ok := repositoryName(path)
+	if !ok {
+		return "", "", fmt.Errorf("invalid repository path %s", path)
+	}
+	return name, service, nil
+}
+
+// LoadHostKey loads the SSH host key of the server from the PEM file at path,
+// creating an Ed25519 key if the file does not exist, so that the server
+// keeps its identity across restarts.
+//
+// If the key cannot be read, created or parsed, an error is returned.
+func LoadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate host key: %w", err)
+		}
+		block, err := ssh.MarshalPrivateKey(privateKey, "ophelia-ci")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode host key: %w", err)
+		}
+		data = pem.EncodeToMemory(block)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create host key directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write host key: %w", err)
+		}
+		log.Printf("Created SSH host key %s", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read host key: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse host key: %w", err)
+	}
+	return signer, nil
+}
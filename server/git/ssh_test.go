@@ -0,0 +1,164 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// startSSHServer serves the bare repository at repoPath as "project" over SSH
+// to alice, whose private key file is returned with the address of the server.
+func startSSHServer(t *testing.T, repoPath string) (address, keyPath string) {
+	t.Helper()
+	dir := t.TempDir()
+	hostKey, err := LoadHostKey(filepath.Join(dir, "host_key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath = filepath.Join(dir, "id_ed25519")
+	userKey, err := LoadHostKey(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	server := &SSHServer{
+		HostKey: hostKey,
+		RepoPath: func(name string) (string, error) {
+			if name != "project" {
+				return "", errors.New("repository not found")
+			}
+			return repoPath, nil
+		},
+		Authenticate: func(username string, key ssh.PublicKey) error {
+			if username != "alice" || !bytes.Equal(key.Marshal(), userKey.PublicKey().Marshal()) {
+				return errors.New("unknown key")
+			}
+			return nil
+		},
+	}
+	go server.Serve(listener)
+	return listener.Addr().String(), keyPath
+}
+
+func TestSSHServerClonesAndPushes(t *testing.T) {
+	t.Setenv("APP_OPHELIA_CI_SERVER_SECRET", "secret")
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "project.git")
+	runGit(t, root, "init", "--quiet", "--bare", repoPath)
+	pusherFile := filepath.Join(root, "pusher")
+	hook := "#!/bin/sh\necho \"$OPHELIA_CI_PUSHER$APP_OPHELIA_CI_SERVER_SECRET\" > " + pusherFile + "\n"
+	if err := os.WriteFile(filepath.Join(repoPath, "hooks", "post-receive"), []byte(hook), 0755); err != nil {
+		t.Fatal(err)
+	}
+	address, keyPath := startSSHServer(t, repoPath)
+	host, port, _ := net.SplitHostPort(address)
+	sshCommand := "ssh -i " + keyPath + " -p " + port +
+		" -o IdentitiesOnly=yes -o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o BatchMode=yes -o LogLevel=ERROR"
+
+	work := t.TempDir()
+	sshGit := func(args ...string) ([]byte, error) {
+		cmd := gitCommand(work, args...)
+		cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+sshCommand)
+		return cmd.CombinedOutput()
+	}
+	if output, err := sshGit("clone", "ssh://bob@"+host+"/project.git", "bob"); err == nil {
+		t.Fatalf("expected a clone as another user to fail, got:\n%s", output)
+	}
+	if output, err := sshGit("clone", "ssh://alice@"+host+"/missing.git", "missing"); err == nil || !strings.Contains(string(output), "repository missing not found") {
+		t.Fatalf("expected a clone of a missing repository to fail, got %v:\n%s", err, output)
+	}
+	if output, err := sshGit("clone", "--quiet", "ssh://alice@"+host+"/project.git", "clone"); err != nil {
+		t.Fatalf("clone failed: %v\n%s", err, output)
+	}
+
+	clone := filepath.Join(work, "clone")
+	if err := os.WriteFile(filepath.Join(clone, "README"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, clone, "add", "README")
+	runGit(t, clone, "commit", "--quiet", "-m", "Add README")
+	cmd := gitCommand(clone, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+	cmd.Env = append(cmd.Env, "GIT_SSH_COMMAND="+sshCommand)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("push failed: %v\n%s", err, output)
+	}
+
+	pushed := strings.TrimSpace(runGit(t, clone, "rev-parse", "HEAD"))
+	if revision, err := ResolveRevision(repoPath, "main"); err != nil || revision != pushed {
+		t.Fatalf("expected main to be %s, got %q (%v)", pushed, revision, err)
+	}
+	if pusher, err := os.ReadFile(pusherFile); err != nil || string(pusher) != "alice\n" {
+		t.Fatalf("expected the post-receive hook to run for alice without the server secret, got %q (%v)", pusher, err)
+	}
+}
+
+func TestSSHServerRejectsOtherCommands(t *testing.T) {
+	root := t.TempDir()
+	repoPath := filepath.Join(root, "project.git")
+	runGit(t, root, "init", "--quiet", "--bare", repoPath)
+	address, keyPath := startSSHServer(t, repoPath)
+
+	key, err := LoadHostKey(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := ssh.Dial("tcp", address, &ssh.ClientConfig{
+		User:            "alice",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for _, command := range []string{"ls /", "git-upload-pack '../project.git'", "git-upload-archive 'project.git'"} {
+		session, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stderr bytes.Buffer
+		session.Stderr = &stderr
+		var exitErr *ssh.ExitError
+		if err := session.Run(command); !errors.As(err, &exitErr) || exitErr.ExitStatus() != 128 {
+			t.Errorf("expected %q to fail with status 128, got %v", command, err)
+		}
+		if !strings.HasPrefix(stderr.String(), "fatal: ") {
+			t.Errorf("expected an error message for %q, got %q", command, stderr.String())
+		}
+		session.Close()
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	if err := session.Shell(); err == nil {
+		t.Error("expected shell access to be rejected")
+	}
+	if _, _, err := client.OpenChannel("direct-tcpip", nil); err == nil {
+		t.Error("expected port forwarding to be rejected")
+	}
+}
+
+func TestParseSSHCommand(t *testing.T) {
+	name, service, err := parseSSHCommand("git-receive-pack '/project.git'")
+	if err != nil || name != "project" || service != ReceivePack {
+		t.Fatalf("unexpected result %q, %q, %v", name, service, err)
+	}
+	if _, _, err := parseSSHCommand("git-upload-pack 'group/project.git'"); err == nil {
+		t.Fatal("expected a nested path to be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+)
+
+// serveGitHTTP serves the repositories with the Git smart HTTP protocol on the
+// given port, over TLS when a certificate is configured, so that they can be
+// cloned and pushed with `git clone http(s)://<host>:<port>/<name>.git`.
+//
+// It blocks until the listener fails, which is fatal.
+func (s *server) serveGitHTTP(port int, certFile, keyFile string) {
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", port),
+		Handler:           &git.HTTPHandler{RepoPath: s.gitRepoPath, Authenticate: authenticateGitHTTP, Authorize: s.authorizeGit},
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	log.Printf("Serving Git over HTTP on port %d\n", port)
+
+	var err error
+	if certFile != "" && keyFile != "" {
+		err = httpServer.ListenAndServeTLS(certFile, keyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	log.Fatalf("Failed to serve Git over HTTP: %v", err)
+}
+
+// gitRepoPath returns the path of the bare repository of a registered
+// repository, so that only the repositories known to the server are served.
+func (s *server) gitRepoPath(name string) (string, error) {
+	if _, err := s.repositorieStore.GetRepositoryByName(name); err != nil {
+		return "", err
+	}
+	return getRepoPath(name), nil
+}
+
+// authenticateGitHTTP authenticates a Git smart HTTP request with the user JWT
+// issued by the authentication service.
+//
+// The token is read from a Bearer authorization header, as sent when
+// http.extraHeader is configured, or else from the password of a Basic
+// authorization header, as sent by Git credential helpers, whose username is
+// ignored.
+//
+// Returns:
+// - string: The username of the authenticated user.
+// - error: An error if the token is missing or invalid.
+func authenticateGitHTTP(r *http.Request) (string, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		_, token, ok = r.BasicAuth()
+	}
+	if !ok || token == "" {
+		return "", errors.New("no token found")
+	}
+	return verifyUserJWT(token)
+}
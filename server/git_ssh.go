@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"golang.org/x/crypto/ssh"
+)
+
+// serveGitSSH serves the repositories over SSH on the given port, so that
+// users can clone and push with `git clone ssh://<username>@<host>:<port>/<name>.git`
+// using the public key registered for them.
+//
+// The host key is read from hostKeyPath, and created on first start. It blocks
+// until the listener fails, which is fatal.
+func (s *server) serveGitSSH(port int, hostKeyPath string) {
+	hostKey, err := git.LoadHostKey(hostKeyPath)
+	if err != nil {
+		log.Fatalf("Failed to load SSH host key: %v", err)
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+	if err != nil {
+		log.Fatalf("Failed to listen for SSH: %v", err)
+	}
+	log.Printf("Serving Git over SSH on port %d with host key %v\n", port, ssh.FingerprintSHA256(hostKey.PublicKey()))
+
+	sshServer := &git.SSHServer{
+		HostKey:      hostKey,
+		RepoPath:     s.gitRepoPath,
+		Authenticate: s.authenticateGitSSH,
+		Authorize:    s.authorizeGit,
+	}
+	log.Fatalf("Failed to serve Git over SSH: %v", sshServer.Serve(lis))
+}
+
+// authenticateGitSSH verifies that key is one of the public keys of the user
+// that have not expired, as with the authentication challenge.
+//
+// Returns:
+// - error: An error if the user does not exist or key is not one of their keys.
+func (s *server) authenticateGitSSH(username string, key ssh.PublicKey) error {
+	storedKeys, err := s.userPublicKeys(username)
+	if err != nil {
+		return err
+	}
+	for _, storedKey := range storedKeys {
+		if bytes.Equal(storedKey.Marshal(), key.Marshal()) {
+			return nil
+		}
+	}
+	return errors.New("public key does not match")
+}
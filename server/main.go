@@ -1,16 +1,28 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
 	"fmt"
 	"log"
 	"net"
+	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"database/sql"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/artifact"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/buildlog"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/cache"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/dispatch"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/executor"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/queue"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/redact"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/secret"
 	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -22,18 +34,50 @@ type server struct {
 	pb.UnimplementedAuthServiceServer
 	pb.UnimplementedHealthServiceServer
 	pb.UnimplementedSignalsServer
-
-	userStore        store.UserStore
-	repositorieStore store.RepositoryStore
-	challenges       sync.Map
+	pb.UnimplementedBuildServiceServer
+	pb.UnimplementedRunnerServiceServer
+	pb.UnimplementedSecretServiceServer
+
+	userStore         store.UserStore
+	repositorieStore  store.RepositoryStore
+	buildStore        store.BuildStore
+	runnerStore       store.RunnerStore
+	scheduleStore     store.ScheduleStore
+	artifactStore     store.ArtifactStore
+	secretStore       store.SecretStore
+	roleStore         store.RoleStore
+	bootstrapStore    store.BootstrapStore
+	userKeyStore      store.UserKeyStore
+	challenges        sync.Map
+	executor          *executor.Executor
+	buildLogs         *buildlog.Manager
+	artifacts         *artifact.Manager
+	secrets           *secret.Cipher
+	queue             *queue.Queue
+	dispatcher        *dispatch.Dispatcher
+	localRunner       *dispatch.Runner
+	coalesceBuilds    bool
+	registrationToken string
+	artifactRetention int
 }
 
 // Main starts the Ophelia CI Server Service.
 func main() {
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
+	}
 	log.Println("Ophelia CI Server Service started!")
 
 	config := LoadConfig()
 
+	redactor, err := redact.New(config.Server.RedactPatterns...)
+	if err != nil {
+		log.Fatalf("Failed to load redact patterns: %v", err)
+	}
+	redactor = redactor.WithValues(config.Server.Secret, config.Server.MasterKey, config.Runner.RegistrationToken)
+	log.SetOutput(redactor.Writer(log.Writer()))
+
 	db, err := sql.Open("sqlite3", config.Server.HomePath + "/ophelia.db")
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
@@ -42,13 +86,55 @@ func main() {
 
 	repoStore := store.NewSQLRepositoryStore(db)
 	userStore := store.NewSQLUserStore(db)
+	userKeyStore := store.NewSQLUserKeyStore(db)
+	roleStore := store.NewSQLRoleStore(db)
+	bootstrapStore := store.NewSQLBootstrapStore(db)
+	buildStore := store.NewSQLBuildStore(db)
+	runnerStore := store.NewSQLRunnerStore(db)
+	scheduleStore := store.NewSQLScheduleStore(db)
+	artifactStore := store.NewSQLArtifactStore(db)
+	secretStore := store.NewSQLSecretStore(db)
+
+	var secrets *secret.Cipher
+	if config.Server.MasterKey != "" {
+		secrets, err = secret.NewCipher(config.Server.MasterKey)
+		if err != nil {
+			log.Fatalf("Failed to derive the secrets key: %v", err)
+		}
+	} else {
+		log.Println("No master key is configured, repository secrets are disabled")
+	}
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", config.Server.Port))
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	opts := []grpc.ServerOption{grpc.UnaryInterceptor(AuthInterceptor)}
+	mainServer := &server{
+		repositorieStore:  repoStore,
+		userStore:         userStore,
+		buildStore:        buildStore,
+		runnerStore:       runnerStore,
+		scheduleStore:     scheduleStore,
+		artifactStore:     artifactStore,
+		secretStore:       secretStore,
+		roleStore:         roleStore,
+		bootstrapStore:    bootstrapStore,
+		userKeyStore:      userKeyStore,
+		secrets:           secrets,
+		executor:          executor.NewExecutor(filepath.Join(config.Server.HomePath, "workspaces")),
+		buildLogs:         buildlog.NewManager(filepath.Join(config.Server.HomePath, "logs"), redactor),
+		artifacts:         artifact.NewManager(filepath.Join(config.Server.HomePath, "artifacts")),
+		dispatcher:        dispatch.New(time.Duration(config.Runner.LeaseTimeout) * time.Second),
+		coalesceBuilds:    config.Runner.CoalesceBuilds,
+		registrationToken: config.Runner.RegistrationToken,
+		artifactRetention: cmp.Or(config.Server.ArtifactRetentionDays, defaultArtifactRetentionDays),
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(mainServer.AuthInterceptor),
+		grpc.StreamInterceptor(mainServer.AuthStreamInterceptor),
+	}
 
 	if config.SSL.CertFile != "" && config.SSL.KeyFile != "" {
 		log.Println("Using SSL")
@@ -64,17 +150,47 @@ func main() {
 	}
 
 	s := grpc.NewServer(opts...)
-
-	mainServer := &server{
-		repositorieStore: repoStore,
-		userStore:        userStore,
+	mainServer.executor.Cache = cache.New(filepath.Join(config.Server.HomePath, "cache"), cmp.Or(int64(config.Server.CacheMaxSize)<<20, cache.DefaultMaxSize))
+	mainServer.queue = queue.New(buildStore, config.Runner.MaxConcurrentBuilds, mainServer.executeBuild)
+	mainServer.dispatcher.OnExpire = mainServer.leaseExpired
+	mainServer.updateHooks()
+	mainServer.recoverBuilds()
+	mainServer.dispatcher.Start(context.Background())
+	if config.Runner.LocalExecutor {
+		mainServer.startLocalRunners(context.Background(), config.Runner.MaxConcurrentBuilds, config.Runner.Labels)
 	}
+	mainServer.queue.Start(context.Background())
+	mainServer.startScheduler(context.Background())
+	mainServer.startArtifactSweeper(context.Background())
+
 	pb.RegisterRepositoryServiceServer(s, mainServer)
 	pb.RegisterUserServiceServer(s, mainServer)
 	pb.RegisterAuthServiceServer(s, mainServer)
 	pb.RegisterHealthServiceServer(s, mainServer)
+	pb.RegisterBuildServiceServer(s, mainServer)
+	pb.RegisterRunnerServiceServer(s, mainServer)
+	pb.RegisterSecretServiceServer(s, mainServer)
+	pb.RegisterSignalsServer(s, mainServer)
+	if config.Server.HTTPPort > 0 {
+		go mainServer.serveGitHTTP(config.Server.HTTPPort, config.SSL.CertFile, config.SSL.KeyFile)
+	} else {
+		log.Println("No HTTP port is configured, Git over HTTP is disabled")
+	}
+	if config.Server.SSHPort > 0 {
+		go mainServer.serveGitSSH(config.Server.SSHPort, filepath.Join(config.Server.HomePath, "ssh_host_ed25519_key"))
+	} else {
+		log.Println("No SSH port is configured, Git over SSH is disabled")
+	}
 	log.Printf("Listening on port %d\n", config.Server.Port)
-	log.Printf("For logging in for the first time, use the following key: %v", uniqueKey)
+	uniqueKey, err = issueUniqueKey(bootstrapStore)
+	if err != nil {
+		log.Fatalf("Failed to read the bootstrap: %v", err)
+	}
+	if uniqueKey == "" {
+		log.Println("The server is bootstrapped, the unique key login is disabled")
+	} else {
+		log.Printf("For creating the first administrator, use the following key: %v", uniqueKey)
+	}
 
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
@@ -0,0 +1,103 @@
+package permission
+
+import (
+	"fmt"
+	"strings"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+)
+
+// Rule is the role a user needs to call a method.
+type Rule struct {
+	// Role is the least role allowed to call the method.
+	Role pb.Role
+	// Repository reports whether the role may be granted on the repository
+	// the request is for, instead of globally.
+	Repository bool
+}
+
+// methods maps the full names of the methods called with user tokens to the
+// role they need. The methods missing from it are denied.
+var methods = map[string]Rule{
+	"/repository.RepositoryService/CreateRepository":       {Role: pb.Role_ADMIN},
+	"/repository.RepositoryService/UpdateRepository":       {Role: pb.Role_MAINTAINER, Repository: true},
+	"/repository.RepositoryService/ListRepository":         {Role: pb.Role_NONE},
+	"/repository.RepositoryService/GetRepository":          {Role: pb.Role_READER, Repository: true},
+	"/repository.RepositoryService/DeleteRepository":       {Role: pb.Role_ADMIN},
+	"/repository.RepositoryService/GrantRepositoryAccess":  {Role: pb.Role_MAINTAINER, Repository: true},
+	"/repository.RepositoryService/RevokeRepositoryAccess": {Role: pb.Role_MAINTAINER, Repository: true},
+
+	"/user.UserService/CreateUser":  {Role: pb.Role_ADMIN},
+	"/user.UserService/UpdateUser":  {Role: pb.Role_ADMIN},
+	"/user.UserService/ListUser":    {Role: pb.Role_READER},
+	"/user.UserService/GetUser":     {Role: pb.Role_READER},
+	"/user.UserService/DeleteUser":  {Role: pb.Role_ADMIN},
+	"/user.UserService/SetUserRole": {Role: pb.Role_ADMIN},
+
+	// Users manage their own keys, and the handlers only let administrators
+	// manage the keys of other users.
+	"/user.UserService/AddUserKey":    {Role: pb.Role_NONE},
+	"/user.UserService/ListUserKeys":  {Role: pb.Role_NONE},
+	"/user.UserService/RemoveUserKey": {Role: pb.Role_NONE},
+
+	"/build.BuildService/ListBuilds":       {Role: pb.Role_READER, Repository: true},
+	"/build.BuildService/GetBuild":         {Role: pb.Role_READER, Repository: true},
+	"/build.BuildService/StreamBuildLogs":  {Role: pb.Role_READER, Repository: true},
+	"/build.BuildService/DownloadArtifact": {Role: pb.Role_READER, Repository: true},
+	"/build.BuildService/TriggerBuild":     {Role: pb.Role_DEVELOPER, Repository: true},
+	"/build.BuildService/RetryBuild":       {Role: pb.Role_DEVELOPER, Repository: true},
+	"/build.BuildService/CancelBuild":      {Role: pb.Role_DEVELOPER, Repository: true},
+
+	"/secret.SecretService/ListSecretNames": {Role: pb.Role_DEVELOPER, Repository: true},
+	"/secret.SecretService/SetSecret":       {Role: pb.Role_MAINTAINER, Repository: true},
+	"/secret.SecretService/DeleteSecret":    {Role: pb.Role_MAINTAINER, Repository: true},
+
+	"/signal.Signals/CommitSignal": {Role: pb.Role_DEVELOPER, Repository: true},
+}
+
+// Git services of the Git transports, and the roles they need on the
+// repository.
+var gitServices = map[string]pb.Role{
+	"git-upload-pack":  pb.Role_READER,
+	"git-receive-pack": pb.Role_DEVELOPER,
+}
+
+// ForMethod returns the rule of a method called with a user token.
+//
+// Returns false if the method is unknown, and must be denied.
+func ForMethod(method string) (Rule, bool) {
+	rule, ok := methods[method]
+	return rule, ok
+}
+
+// ForGitService returns the rule of a Git service, such as git-upload-pack,
+// run on a repository through the Git transports.
+//
+// Returns false if the service is unknown, and must be denied.
+func ForGitService(service string) (Rule, bool) {
+	role, ok := gitServices[service]
+	return Rule{Role: role, Repository: true}, ok
+}
+
+// Allows reports whether a user with the given role may call a method needing
+// the required role.
+func Allows(role, required pb.Role) bool {
+	return role >= required
+}
+
+// Effective returns the role of a user on a repository, which is the highest
+// of their global role and of the role granted to them on the repository.
+func Effective(global, repository pb.Role) pb.Role {
+	return max(global, repository)
+}
+
+// ParseRole parses the name of a role, case insensitively.
+//
+// Returns an error if the name is not the name of a role.
+func ParseRole(name string) (pb.Role, error) {
+	role, ok := pb.Role_value[strings.ToUpper(name)]
+	if !ok {
+		return pb.Role_NONE, fmt.Errorf("invalid role %q, expected one of reader, developer, maintainer, admin or none", name)
+	}
+	return pb.Role(role), nil
+}
@@ -0,0 +1,68 @@
+package permission
+
+import (
+	"testing"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestEveryUserMethodHasARule(t *testing.T) {
+	files := []protoreflect.FileDescriptor{
+		pb.File_repository_proto,
+		pb.File_user_proto,
+		pb.File_build_proto,
+		pb.File_secret_proto,
+		pb.File_signal_proto,
+	}
+	for _, file := range files {
+		services := file.Services()
+		for i := range services.Len() {
+			service := services.Get(i)
+			// The authentication service is called before users have a token.
+			if service.FullName() == "user.AuthService" {
+				continue
+			}
+			methods := service.Methods()
+			for j := range methods.Len() {
+				method := "/" + string(service.FullName()) + "/" + string(methods.Get(j).Name())
+				if _, ok := ForMethod(method); !ok {
+					t.Errorf("%s has no permission rule", method)
+				}
+			}
+		}
+	}
+
+	if _, ok := ForMethod("/runner.RunnerService/LeaseJob"); ok {
+		t.Error("expected runner methods to have no user rule")
+	}
+}
+
+func TestRoles(t *testing.T) {
+	rule, _ := ForMethod("/build.BuildService/TriggerBuild")
+	if !rule.Repository || Allows(pb.Role_READER, rule.Role) || !Allows(pb.Role_DEVELOPER, rule.Role) {
+		t.Fatalf("unexpected TriggerBuild rule %+v", rule)
+	}
+	rule, _ = ForMethod("/user.UserService/DeleteUser")
+	if rule.Repository || Allows(pb.Role_MAINTAINER, rule.Role) || !Allows(pb.Role_ADMIN, rule.Role) {
+		t.Fatalf("unexpected DeleteUser rule %+v", rule)
+	}
+	if Effective(pb.Role_READER, pb.Role_MAINTAINER) != pb.Role_MAINTAINER || Effective(pb.Role_ADMIN, pb.Role_NONE) != pb.Role_ADMIN {
+		t.Fatal("expected the highest role to be effective")
+	}
+
+	push, ok := ForGitService("git-receive-pack")
+	if !ok || Allows(pb.Role_READER, push.Role) || !Allows(pb.Role_DEVELOPER, push.Role) {
+		t.Fatalf("unexpected push rule %+v", push)
+	}
+	if _, ok := ForGitService("git-upload-archive"); ok {
+		t.Fatal("expected unknown Git services to be denied")
+	}
+
+	if role, err := ParseRole("Maintainer"); err != nil || role != pb.Role_MAINTAINER {
+		t.Fatalf("expected maintainer to parse, got %v (%v)", role, err)
+	}
+	if _, err := ParseRole("owner"); err == nil {
+		t.Fatal("expected an unknown role to be rejected")
+	}
+}
@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// cacheExpression matches the expressions of a cache key.
+	cacheExpression = regexp.MustCompile(`\{\{(.*?)\}\}`)
+	// hashFilesCall matches a call to hashFiles with its arguments.
+	hashFilesCall = regexp.MustCompile(`^\s*hashFiles\((.*)\)\s*$`)
+)
+
+// Cache declares workspace paths that are restored before a job runs and
+// saved after it succeeds, such as dependency directories.
+//
+// Key identifies the saved content. It may contain `{{ hashFiles('go.sum') }}`
+// expressions, replaced by a hash of the content of the workspace files matching
+// the given glob patterns, so that the cache changes along with them.
+type Cache struct {
+	Key   string   `yaml:"key"`
+	Paths []string `yaml:"paths"`
+}
+
+// ResolveKey returns the cache key, with every hashFiles expression replaced
+// by the result of hashFiles for its patterns.
+func (c *Cache) ResolveKey(hashFiles func(patterns []string) (string, error)) (string, error) {
+	var errs []error
+	key := cacheExpression.ReplaceAllStringFunc(c.Key, func(expression string) string {
+		patterns, err := parseHashFiles(cacheExpression.FindStringSubmatch(expression)[1])
+		if err != nil {
+			errs = append(errs, err)
+			return ""
+		}
+		hash, err := hashFiles(patterns)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return hash
+	})
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return key, nil
+}
+
+// validate checks the key expressions and the paths of the cache of the job at
+// path.
+func (c *Cache) validate(path string) error {
+	var errs []error
+	if strings.TrimSpace(c.Key) == "" {
+		errs = append(errs, fmt.Errorf("%s.cache.key: key is required", path))
+	}
+	_, err := c.ResolveKey(func(patterns []string) (string, error) {
+		for _, pattern := range patterns {
+			if err := validatePattern("hashFiles pattern", pattern); err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s.cache.key: %w", path, err))
+	}
+
+	if len(c.Paths) == 0 {
+		errs = append(errs, fmt.Errorf("%s.cache.paths: cache must declare at least one path", path))
+	}
+	for i, cachePath := range c.Paths {
+		if err := validatePattern("cache path", cachePath); err != nil {
+			errs = append(errs, fmt.Errorf("%s.cache.paths[%d]: %w", path, i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseHashFiles parses a hashFiles expression, whose arguments are quoted
+// glob patterns, and returns the patterns.
+func parseHashFiles(expression string) ([]string, error) {
+	call := hashFilesCall.FindStringSubmatch(expression)
+	if call == nil {
+		return nil, fmt.Errorf("unsupported expression %q, only hashFiles is supported", strings.TrimSpace(expression))
+	}
+
+	var patterns []string
+	for _, argument := range strings.Split(call[1], ",") {
+		argument = strings.TrimSpace(argument)
+		if len(argument) < 2 || argument[0] != argument[len(argument)-1] || (argument[0] != '\'' && argument[0] != '"') {
+			return nil, fmt.Errorf("hashFiles argument %q must be a quoted pattern", argument)
+		}
+		patterns = append(patterns, argument[1:len(argument)-1])
+	}
+	return patterns, nil
+}
@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const cachePipeline = `
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        cache:
+          key: go-{{ hashFiles('go.sum', "tools/go.sum") }}-v1
+          paths: [go/pkg/mod, .cache/go-build]
+        steps: [{run: "go test ./..."}]
+`
+
+func TestResolveCacheKey(t *testing.T) {
+	pipeline, err := Parse([]byte(cachePipeline))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cache := pipeline.Stages[0].Jobs[0].Cache
+	key, err := cache.ResolveKey(func(patterns []string) (string, error) {
+		return strings.Join(patterns, "+"), nil
+	})
+	if err != nil || key != "go-go.sum+tools/go.sum-v1" {
+		t.Fatalf("unexpected key %q: %v", key, err)
+	}
+
+	_, err = cache.ResolveKey(func(patterns []string) (string, error) {
+		return "", errors.New("unreadable")
+	})
+	if err == nil {
+		t.Fatal("expected the hashing error to be reported")
+	}
+}
+
+func TestParseRejectsInvalidCaches(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		message  string
+	}{
+		"missing key": {
+			old:     `key: go-{{ hashFiles('go.sum', "tools/go.sum") }}-v1`,
+			new:     `key: " "`,
+			message: `stages[0].jobs[0].cache.key: key is required`,
+		},
+		"unsupported expression": {
+			old:     "hashFiles('go.sum', \"tools/go.sum\")",
+			new:     "env.GOOS",
+			message: `stages[0].jobs[0].cache.key: unsupported expression "env.GOOS", only hashFiles is supported`,
+		},
+		"unquoted pattern": {
+			old:     "'go.sum'",
+			new:     "go.sum",
+			message: `stages[0].jobs[0].cache.key: hashFiles argument "go.sum" must be a quoted pattern`,
+		},
+		"pattern outside workspace": {
+			old:     "'go.sum'",
+			new:     "'../go.sum'",
+			message: `stages[0].jobs[0].cache.key: hashFiles pattern "../go.sum" must be a relative path inside the workspace`,
+		},
+		"no paths": {
+			old:     "paths: [go/pkg/mod, .cache/go-build]",
+			new:     "paths: []",
+			message: `stages[0].jobs[0].cache.paths: cache must declare at least one path`,
+		},
+		"path outside workspace": {
+			old:     ".cache/go-build",
+			new:     "/root/.cache",
+			message: `stages[0].jobs[0].cache.paths[1]: cache path "/root/.cache" must be a relative path inside the workspace`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(strings.Replace(cachePipeline, test.old, test.new, 1)))
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Fatalf("expected error containing %q, got %v", test.message, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// matrixReference matches the references to matrix values in the runs-on
+// labels of a job, such as `${{ matrix.os }}`.
+var matrixReference = regexp.MustCompile(`\$\{\{\s*matrix\.([^\s}]*)\s*\}\}`)
+
+// Matrix expands a job into one job per combination of the values of its axes.
+//
+// The combinations matching every value of an Exclude entry are dropped, and
+// each Include entry adds a combination of its own. Unless FailFast is set to
+// false, the other jobs of the matrix are cancelled as soon as one of them
+// fails.
+//
+// In a definition file, a matrix is a mapping from the axis names to their
+// values, along with the include, exclude and fail-fast keys:
+//
+//	matrix:
+//	  go: ["1.23", "1.24"]
+//	  os: [linux, darwin]
+//	  exclude:
+//	    - {go: "1.23", os: darwin}
+type Matrix struct {
+	Axes     []Axis
+	Include  []map[string]string
+	Exclude  []map[string]string
+	FailFast *bool
+}
+
+// Axis is a named dimension of a matrix.
+type Axis struct {
+	Name   string
+	Values []string
+}
+
+// MatrixCell describes a job expanded from a matrix.
+type MatrixCell struct {
+	// Job is the name of the job declaring the matrix.
+	Job string
+	// Values holds the value of every axis for this job.
+	Values   map[string]string
+	FailFast bool
+}
+
+// UnmarshalYAML decodes a matrix, keeping its axes in declaration order.
+func (m *Matrix) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: matrix must be a mapping", node.Line)
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		var err error
+		switch key {
+		case "include":
+			err = value.Decode(&m.Include)
+		case "exclude":
+			err = value.Decode(&m.Exclude)
+		case "fail-fast":
+			err = value.Decode(&m.FailFast)
+		default:
+			axis := Axis{Name: key}
+			err = value.Decode(&axis.Values)
+			m.Axes = append(m.Axes, axis)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// combinations returns the value of every axis for each job of the matrix.
+func (m *Matrix) combinations() []map[string]string {
+	combinations := []map[string]string{{}}
+	for _, axis := range m.Axes {
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range axis.Values {
+				extended := maps.Clone(combination)
+				extended[axis.Name] = value
+				next = append(next, extended)
+			}
+		}
+		combinations = next
+	}
+	if len(m.Axes) == 0 {
+		combinations = nil
+	}
+
+	combinations = slices.DeleteFunc(combinations, func(combination map[string]string) bool {
+		return slices.ContainsFunc(m.Exclude, func(exclude map[string]string) bool {
+			return matchesCombination(combination, exclude)
+		})
+	})
+	for _, include := range m.Include {
+		combinations = append(combinations, maps.Clone(include))
+	}
+	return combinations
+}
+
+// keys returns the names of the axes of the matrix followed by the other keys
+// used by its include entries, in sorted order.
+func (m *Matrix) keys() []string {
+	var keys []string
+	for _, axis := range m.Axes {
+		keys = append(keys, axis.Name)
+	}
+	var extra []string
+	for _, include := range m.Include {
+		for key := range include {
+			if !slices.Contains(keys, key) && !slices.Contains(extra, key) {
+				extra = append(extra, key)
+			}
+		}
+	}
+	slices.Sort(extra)
+	return append(keys, extra...)
+}
+
+// matchesCombination reports whether a combination has every value of entry.
+func matchesCombination(combination, entry map[string]string) bool {
+	for key, value := range entry {
+		if combination[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the axes and entries of the matrix of the job at path, and
+// the matrix values referenced by its runs-on labels.
+func (m *Matrix) validate(path string, job Job) error {
+	var errs []error
+	for _, axis := range m.Axes {
+		if !parameterName.MatchString(axis.Name) {
+			errs = append(errs, fmt.Errorf("%s.matrix.%s: axis name must contain only letters, digits and underscores", path, axis.Name))
+		}
+		if len(axis.Values) == 0 {
+			errs = append(errs, fmt.Errorf("%s.matrix.%s: axis must have at least one value", path, axis.Name))
+		}
+		for i, value := range axis.Values {
+			if strings.TrimSpace(value) == "" {
+				errs = append(errs, fmt.Errorf("%s.matrix.%s[%d]: value must not be blank", path, axis.Name, i))
+			}
+		}
+	}
+	axes := m.keys()
+	for i, exclude := range m.Exclude {
+		if len(exclude) == 0 {
+			errs = append(errs, fmt.Errorf("%s.matrix.exclude[%d]: entry must not be empty", path, i))
+		}
+		for _, key := range slices.Sorted(maps.Keys(exclude)) {
+			if !slices.ContainsFunc(m.Axes, func(axis Axis) bool { return axis.Name == key }) {
+				errs = append(errs, fmt.Errorf("%s.matrix.exclude[%d]: unknown axis %q", path, i, key))
+			}
+		}
+	}
+	for i, include := range m.Include {
+		if len(include) == 0 {
+			errs = append(errs, fmt.Errorf("%s.matrix.include[%d]: entry must not be empty", path, i))
+		}
+		for _, key := range slices.Sorted(maps.Keys(include)) {
+			if !parameterName.MatchString(key) {
+				errs = append(errs, fmt.Errorf("%s.matrix.include[%d]: axis name %q must contain only letters, digits and underscores", path, i, key))
+			}
+		}
+	}
+	if len(errs) == 0 && len(m.combinations()) == 0 {
+		errs = append(errs, fmt.Errorf("%s.matrix: matrix does not expand to any job", path))
+	}
+
+	for i, label := range job.RunsOn {
+		for _, reference := range matrixReference.FindAllStringSubmatch(label, -1) {
+			if !slices.Contains(axes, reference[1]) {
+				errs = append(errs, fmt.Errorf("%s.runs-on[%d]: unknown matrix axis %q", path, i, reference[1]))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expandMatrices replaces every job declaring a matrix with one job per
+// combination of the matrix, and the needs naming such a job with all the jobs
+// expanded from it.
+//
+// Each expanded job is named after the job and its matrix values, has every
+// value in an OPHELIA_CI_MATRIX_<AXIS> variable, and has the references to
+// matrix values in its runs-on labels replaced.
+func (p *Pipeline) expandMatrices() {
+	expanded := make(map[string][]string)
+	for i := range p.Stages {
+		var jobs []Job
+		for _, job := range p.Stages[i].Jobs {
+			if job.Matrix == nil {
+				jobs = append(jobs, job)
+				continue
+			}
+			for _, combination := range job.Matrix.combinations() {
+				cell := expandJob(job, combination)
+				jobs = append(jobs, cell)
+				expanded[job.Name] = append(expanded[job.Name], cell.Name)
+			}
+		}
+		p.Stages[i].Jobs = jobs
+	}
+
+	for i := range p.Stages {
+		for j := range p.Stages[i].Jobs {
+			job := &p.Stages[i].Jobs[j]
+			var needs []string
+			for _, need := range job.Needs {
+				if cells, ok := expanded[need]; ok {
+					needs = append(needs, cells...)
+				} else {
+					needs = append(needs, need)
+				}
+			}
+			job.Needs = needs
+		}
+	}
+}
+
+// expandJob returns the job of a matrix for one combination of its values.
+func expandJob(job Job, combination map[string]string) Job {
+	var values []string
+	for _, key := range job.Matrix.keys() {
+		if value, ok := combination[key]; ok {
+			values = append(values, value)
+		}
+	}
+
+	cell := job
+	cell.Name = fmt.Sprintf("%s (%s)", job.Name, strings.Join(values, ", "))
+	cell.Matrix = nil
+	cell.Cell = &MatrixCell{
+		Job:      job.Name,
+		Values:   combination,
+		FailFast: job.Matrix.FailFast == nil || *job.Matrix.FailFast,
+	}
+
+	cell.Env = make(map[string]string, len(job.Env)+len(combination))
+	for key, value := range combination {
+		cell.Env["OPHELIA_CI_MATRIX_"+strings.ToUpper(key)] = value
+	}
+	maps.Copy(cell.Env, job.Env)
+
+	cell.RunsOn = make([]string, len(job.RunsOn))
+	for i, label := range job.RunsOn {
+		cell.RunsOn[i] = matrixReference.ReplaceAllStringFunc(label, func(reference string) string {
+			return combination[matrixReference.FindStringSubmatch(reference)[1]]
+		})
+	}
+	if job.RunsOn == nil {
+		cell.RunsOn = nil
+	}
+	return cell
+}
@@ -0,0 +1,92 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+const matrixPipeline = `
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        runs-on: ["${{ matrix.os }}"]
+        matrix:
+          go: [1.23, "1.24"]
+          os: [linux, darwin]
+          exclude:
+            - {go: 1.23, os: darwin}
+          include:
+            - {go: "1.22", os: linux, experimental: "true"}
+        steps: [{run: "go test ./..."}]
+  - name: release
+    jobs:
+      - name: package
+        needs: [unit]
+        steps: [{run: "true"}]
+`
+
+func TestExpandMatrix(t *testing.T) {
+	pipeline, err := Parse([]byte(matrixPipeline))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	jobs := pipeline.Stages[0].Jobs
+	var names []string
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+	expected := "unit (1.23, linux),unit (1.24, linux),unit (1.24, darwin),unit (1.22, linux, true)"
+	if got := strings.Join(names, ","); got != expected {
+		t.Fatalf("expected jobs %q, got %q", expected, got)
+	}
+
+	cell := jobs[2]
+	if cell.Cell == nil || cell.Cell.Job != "unit" || !cell.Cell.FailFast || cell.Cell.Values["os"] != "darwin" {
+		t.Fatalf("unexpected matrix cell: %+v", cell.Cell)
+	}
+	if cell.Env["OPHELIA_CI_MATRIX_GO"] != "1.24" || len(cell.RunsOn) != 1 || cell.RunsOn[0] != "darwin" {
+		t.Fatalf("unexpected expanded job: %+v", cell)
+	}
+	if needs := pipeline.Stages[1].Jobs[0].Needs; len(needs) != 4 || needs[3] != "unit (1.22, linux, true)" {
+		t.Fatalf("expected package to need every unit job, got %v", needs)
+	}
+}
+
+func TestParseRejectsInvalidMatrices(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		message  string
+	}{
+		"unknown excluded axis": {
+			old:     "{go: 1.23, os: darwin}",
+			new:     "{go: 1.23, arch: arm64}",
+			message: `stages[0].jobs[0].matrix.exclude[0]: unknown axis "arch"`,
+		},
+		"empty axis": {
+			old:     "os: [linux, darwin]",
+			new:     "os: []",
+			message: `stages[0].jobs[0].matrix.os: axis must have at least one value`,
+		},
+		"unknown referenced axis": {
+			old:     "matrix.os",
+			new:     "matrix.arch",
+			message: `stages[0].jobs[0].runs-on[0]: unknown matrix axis "arch"`,
+		},
+		"reference without matrix": {
+			old:     "needs: [unit]",
+			new:     "needs: [unit]\n        runs-on: [\"${{ matrix.os }}\"]",
+			message: `stages[1].jobs[0].runs-on[0]: job "package" does not declare a matrix`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(strings.Replace(matrixPipeline, test.old, test.new, 1)))
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Fatalf("expected error containing %q, got %v", test.message, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dependencies returns the names of the jobs each job waits for, keyed by job
+// name.
+//
+// A job that declares needs waits for exactly those jobs. Any other job waits
+// for every job of the previous stage, so that stages keep running in order.
+func (p *Pipeline) Dependencies() map[string][]string {
+	dependencies := make(map[string][]string)
+	var previous []string
+	for _, stage := range p.Stages {
+		var current []string
+		for _, job := range stage.Jobs {
+			if len(job.Needs) > 0 {
+				dependencies[job.Name] = job.Needs
+			} else {
+				dependencies[job.Name] = previous
+			}
+			current = append(current, job.Name)
+		}
+		previous = current
+	}
+	return dependencies
+}
+
+// SortedJobs returns all jobs of the pipeline ordered so that every job comes
+// after the jobs it waits for. Jobs that do not depend on each other keep
+// their declaration order.
+func (p *Pipeline) SortedJobs() []Job {
+	dependencies := p.Dependencies()
+	pending := p.Jobs()
+	sorted := make([]Job, 0, len(pending))
+	done := make(map[string]bool)
+
+	for len(pending) > 0 {
+		next := 0
+		for i, job := range pending {
+			if allDone(dependencies[job.Name], done) {
+				next = i
+				break
+			}
+		}
+		job := pending[next]
+		pending = append(pending[:next], pending[next+1:]...)
+		sorted = append(sorted, job)
+		done[job.Name] = true
+	}
+	return sorted
+}
+
+// allDone reports whether every one of names is in done.
+func allDone(names []string, done map[string]bool) bool {
+	for _, name := range names {
+		if !done[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateNeeds checks that the needs of every job name other existing jobs,
+// and that the job dependencies, including the ones implied by the stage
+// order, have no cycles.
+func (p *Pipeline) validateNeeds() []error {
+	var errs []error
+
+	jobPaths := make(map[string]string)
+	var names []string
+	for i, stage := range p.Stages {
+		for j, job := range stage.Jobs {
+			if _, ok := jobPaths[job.Name]; !ok {
+				jobPaths[job.Name] = fmt.Sprintf("stages[%d].jobs[%d]", i, j)
+				names = append(names, job.Name)
+			}
+		}
+	}
+
+	for i, stage := range p.Stages {
+		for j, job := range stage.Jobs {
+			needed := make(map[string]bool)
+			for k, need := range job.Needs {
+				needPath := fmt.Sprintf("stages[%d].jobs[%d].needs[%d]", i, j, k)
+				switch _, exists := jobPaths[need]; {
+				case need == job.Name:
+					errs = append(errs, fmt.Errorf("%s: job %q cannot need itself", needPath, need))
+				case !exists:
+					errs = append(errs, fmt.Errorf("%s: unknown job %q", needPath, need))
+				case needed[need]:
+					errs = append(errs, fmt.Errorf("%s: duplicated need %q", needPath, need))
+				}
+				needed[need] = true
+			}
+		}
+	}
+
+	dependencies := p.Dependencies()
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var stack []string
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dependency := range dependencies[name] {
+			if _, exists := jobPaths[dependency]; !exists || dependency == name {
+				continue
+			}
+			switch state[dependency] {
+			case unvisited:
+				visit(dependency)
+			case visiting:
+				cycle := cyclePath(stack, dependency)
+				errs = append(errs, fmt.Errorf(
+					"%s: job %q is part of a dependency cycle: %s",
+					jobPaths[dependency], dependency, strings.Join(cycle, " -> "),
+				))
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return errs
+}
+
+// cyclePath returns the cycle of the dependency stack that starts and ends at
+// name, in the direction of the dependencies.
+func cyclePath(stack []string, name string) []string {
+	start := len(stack) - 1
+	for stack[start] != name {
+		start--
+	}
+	return append(append([]string{}, stack[start:]...), name)
+}
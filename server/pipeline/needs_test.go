@@ -0,0 +1,94 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+const dagPipeline = `
+stages:
+  - name: check
+    jobs:
+      - name: test
+        steps: [{run: "true"}]
+      - name: lint
+        steps: [{run: "true"}]
+  - name: build
+    jobs:
+      - name: binary
+        needs: [test]
+        steps: [{run: "true"}]
+      - name: docs
+        steps: [{run: "true"}]
+  - name: release
+    jobs:
+      - name: package
+        needs: [binary, lint]
+        steps: [{run: "true"}]
+`
+
+func TestDependencies(t *testing.T) {
+	pipeline, err := Parse([]byte(dagPipeline))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dependencies := pipeline.Dependencies()
+	expected := map[string]string{
+		"test":    "",
+		"lint":    "",
+		"binary":  "test",
+		"docs":    "test,lint",
+		"package": "binary,lint",
+	}
+	for job, want := range expected {
+		if got := strings.Join(dependencies[job], ","); got != want {
+			t.Errorf("expected %v to wait for %q, got %q", job, want, got)
+		}
+	}
+
+	var names []string
+	for _, job := range pipeline.SortedJobs() {
+		names = append(names, job.Name)
+	}
+	if got := strings.Join(names, ","); got != "test,lint,binary,docs,package" {
+		t.Fatalf("unexpected job order: %v", got)
+	}
+}
+
+func TestParseRejectsInvalidNeeds(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		message  string
+	}{
+		"unknown job": {
+			old:     "needs: [test]",
+			new:     "needs: [tests]",
+			message: `stages[1].jobs[0].needs[0]: unknown job "tests"`,
+		},
+		"self reference": {
+			old:     "needs: [test]",
+			new:     "needs: [binary]",
+			message: `stages[1].jobs[0].needs[0]: job "binary" cannot need itself`,
+		},
+		"explicit cycle": {
+			old:     "name: test\n",
+			new:     "name: test\n        needs: [package]\n",
+			message: `stages[0].jobs[0]: job "test" is part of a dependency cycle: test -> package -> binary -> test`,
+		},
+		"cycle through a later stage": {
+			old:     "name: lint\n",
+			new:     "name: lint\n        needs: [docs]\n",
+			message: `stages[0].jobs[1]: job "lint" is part of a dependency cycle: lint -> docs -> lint`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(strings.Replace(dagPipeline, test.old, test.new, 1)))
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Fatalf("expected error containing %q, got %v", test.message, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// Parameter types accepted in pipeline definitions.
+const (
+	ParameterString  = "string"
+	ParameterBoolean = "boolean"
+	ParameterNumber  = "number"
+	ParameterChoice  = "choice"
+)
+
+// parameterName matches the names of parameters, which must be usable in
+// environment variable names.
+var parameterName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Parameter is a value supplied by the user who triggers a build manually.
+//
+// Type is one of string (the default), boolean, number or choice, in which
+// case the value must be one of Options. Builds triggered by a push, and
+// manual builds that do not supply the parameter, use Default, which falls
+// back to the zero value of the type, or to the first option of a choice.
+type Parameter struct {
+	Type        string   `yaml:"type"`
+	Default     string   `yaml:"default"`
+	Description string   `yaml:"description"`
+	Options     []string `yaml:"options"`
+}
+
+// ResolveParameters checks the values supplied for the parameters of the
+// pipeline, and completes them with the defaults of the parameters that were
+// not supplied.
+//
+// Returns the value of every parameter, or an error if a value is supplied for
+// an undeclared parameter or does not match the type of its parameter.
+func (p *Pipeline) ResolveParameters(values map[string]string) (map[string]string, error) {
+	var errs []error
+	resolved := make(map[string]string, len(p.Parameters))
+	for name, parameter := range p.Parameters {
+		value, ok := values[name]
+		if !ok {
+			resolved[name] = parameter.defaultValue()
+			continue
+		}
+		if err := parameter.check(value); err != nil {
+			errs = append(errs, fmt.Errorf("parameter %q: %w", name, err))
+		}
+		resolved[name] = value
+	}
+	for name := range values {
+		if _, ok := p.Parameters[name]; !ok {
+			errs = append(errs, fmt.Errorf("parameter %q is not declared by the pipeline", name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(sortErrors(errs)...)
+	}
+	return resolved, nil
+}
+
+// validateParameters checks the declarations of the parameters of the pipeline.
+func (p *Pipeline) validateParameters() error {
+	var errs []error
+	for name, parameter := range p.Parameters {
+		path := fmt.Sprintf("parameters.%s", name)
+		if !parameterName.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%s: name must contain only letters, digits and underscores", path))
+		}
+		switch parameter.Type {
+		case "", ParameterString, ParameterBoolean, ParameterNumber:
+			if len(parameter.Options) > 0 {
+				errs = append(errs, fmt.Errorf("%s: only choice parameters may declare options", path))
+			}
+		case ParameterChoice:
+			if len(parameter.Options) == 0 {
+				errs = append(errs, fmt.Errorf("%s: choice parameter must declare at least one option", path))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("%s: unknown type %q", path, parameter.Type))
+			continue
+		}
+		if parameter.Default != "" {
+			if err := parameter.check(parameter.Default); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid default: %w", path, err))
+			}
+		}
+	}
+	return errors.Join(sortErrors(errs)...)
+}
+
+// check reports whether a value matches the type of the parameter.
+func (p Parameter) check(value string) error {
+	switch p.Type {
+	case ParameterBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	case ParameterNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case ParameterChoice:
+		if !slices.Contains(p.Options, value) {
+			return fmt.Errorf("%q is not one of %v", value, p.Options)
+		}
+	}
+	return nil
+}
+
+// defaultValue returns the value of the parameter when none is supplied.
+func (p Parameter) defaultValue() string {
+	if p.Default != "" {
+		return p.Default
+	}
+	switch p.Type {
+	case ParameterBoolean:
+		return "false"
+	case ParameterNumber:
+		return "0"
+	case ParameterChoice:
+		return p.Options[0]
+	}
+	return ""
+}
+
+// sortErrors sorts errors by message, since parameters are kept in a map and
+// would otherwise be reported in a random order.
+func sortErrors(errs []error) []error {
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+const parametersPipeline = `
+parameters:
+  version:
+    description: Version to release
+  dry_run:
+    type: boolean
+    default: true
+  target:
+    type: choice
+    options: [staging, production]
+stages:
+  - name: release
+    jobs:
+      - name: publish
+        steps: [{run: "true"}]
+`
+
+func TestResolveParameters(t *testing.T) {
+	pipeline, err := Parse([]byte(parametersPipeline))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	values, err := pipeline.ResolveParameters(map[string]string{"version": "1.2.0", "target": "production"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["version"] != "1.2.0" || values["dry_run"] != "true" || values["target"] != "production" {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	defaults, err := pipeline.ResolveParameters(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaults["version"] != "" || defaults["target"] != "staging" {
+		t.Fatalf("unexpected defaults: %v", defaults)
+	}
+
+	_, err = pipeline.ResolveParameters(map[string]string{"dry_run": "maybe", "other": "x"})
+	if err == nil || !strings.Contains(err.Error(), `parameter "dry_run": "maybe" is not a boolean`) ||
+		!strings.Contains(err.Error(), `parameter "other" is not declared`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseRejectsInvalidParameters(t *testing.T) {
+	content := strings.Replace(parametersPipeline, "default: true", "default: sometimes", 1)
+	content = strings.Replace(content, "options: [staging, production]", "options: []", 1)
+	_, err := Parse([]byte(content))
+	if err == nil || !strings.Contains(err.Error(), `parameters.dry_run: invalid default: "sometimes" is not a boolean`) ||
+		!strings.Contains(err.Error(), "parameters.target: choice parameter must declare at least one option") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
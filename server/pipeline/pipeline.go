@@ -0,0 +1,263 @@
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are the pipeline definition files looked up at the root of a
+// repository, in order of precedence.
+var FileNames = []string{".ophelia-ci.yml", ".ophelia-ci.yaml"}
+
+// ErrNotFound is returned when a revision has no pipeline definition file.
+var ErrNotFound = errors.New("pipeline definition file not found")
+
+// Pipeline is the typed representation of a pipeline definition file.
+//
+// A pipeline is made of stages that run in order. Each stage contains jobs,
+// and each job is a sequence of steps whose commands run in the job workspace.
+// The pushes that create builds of the pipeline can be restricted with On, and
+// builds triggered manually can be given the values of Parameters.
+//
+// Timeout bounds the duration of the whole build, such as "1h30m". Jobs and
+// steps can also declare their own timeout. Zero means no timeout.
+type Pipeline struct {
+	Name       string               `yaml:"name"`
+	On         Triggers             `yaml:"on"`
+	Parameters map[string]Parameter `yaml:"parameters"`
+	Env        map[string]string    `yaml:"env"`
+	Timeout    time.Duration        `yaml:"timeout"`
+	Stages     []Stage              `yaml:"stages"`
+}
+
+// Stage is a named group of jobs. Unless they declare Needs, the jobs of a stage
+// start once all jobs of the previous stage succeeded.
+type Stage struct {
+	Name string `yaml:"name"`
+	Jobs []Job  `yaml:"jobs"`
+}
+
+// Job is a sequence of steps executed in the same workspace.
+//
+// RunsOn lists the labels a runner must have to run the job. A job without
+// labels can run on any runner.
+//
+// Needs lists the jobs that must succeed before the job starts, in any stage.
+// A job that declares them no longer waits for the previous stage.
+//
+// A job declaring a Matrix is replaced by the jobs expanded from it when the
+// pipeline is parsed, each of them described by Cell.
+//
+// Cache declares workspace paths kept from one run of the job to the next.
+type Job struct {
+	Name    string            `yaml:"name"`
+	Env     map[string]string `yaml:"env"`
+	RunsOn  []string          `yaml:"runs-on"`
+	Needs   []string          `yaml:"needs"`
+	Matrix  *Matrix           `yaml:"matrix"`
+	Cache   *Cache            `yaml:"cache"`
+	Timeout time.Duration     `yaml:"timeout"`
+	Steps   []Step            `yaml:"steps"`
+	Cell    *MatrixCell       `yaml:"-"`
+}
+
+// Step is a single shell command run as part of a job.
+//
+// Artifacts lists the files and directories of the workspace, or glob patterns
+// matching them, that are kept once the job finishes.
+type Step struct {
+	Name      string            `yaml:"name"`
+	Run       string            `yaml:"run"`
+	Env       map[string]string `yaml:"env"`
+	Artifacts []string          `yaml:"artifacts"`
+	Timeout   time.Duration     `yaml:"timeout"`
+}
+
+// Load reads the pipeline definition file of the bare repository at repoPath
+// as it exists at the given revision, then parses and validates it.
+//
+// If none of FileNames exists at the revision, ErrNotFound is returned.
+func Load(repoPath, revision string) (*Pipeline, error) {
+	for _, fileName := range FileNames {
+		content, err := git.ReadFileAtRevision(repoPath, revision, fileName)
+		if errors.Is(err, git.ErrFileNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pipeline, err := Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", fileName, err)
+		}
+		return pipeline, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Parse decodes a pipeline definition from YAML, validates it and expands the
+// jobs declaring a matrix.
+//
+// Unknown keys are rejected so that typos in the definition file are reported
+// instead of silently ignored.
+func Parse(content []byte) (*Pipeline, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	var pipeline Pipeline
+	if err := decoder.Decode(&pipeline); err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline: %w", err)
+	}
+
+	if err := pipeline.Validate(); err != nil {
+		return nil, err
+	}
+	pipeline.expandMatrices()
+	if err := pipeline.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid matrix expansion: %w", err)
+	}
+	return &pipeline, nil
+}
+
+// Validate checks the pipeline for structural errors.
+//
+// It ensures that:
+//   - There is at least one stage, and every stage has a unique name
+//   - Every stage has at least one job, and job names are unique in the pipeline
+//   - Every job has at least one step, and every step has a command to run
+//   - The artifacts of every step are valid patterns inside the workspace
+//   - The caches have a key, only using hashFiles expressions, and valid paths
+//     inside the workspace
+//   - The runs-on labels of every job are not blank, and only reference the
+//     values of the matrix of the job
+//   - The matrices have valid axes, and expand to at least one job
+//   - The needs of every job name other jobs, without dependency cycles
+//   - The trigger patterns are valid globs, and the schedules valid cron expressions
+//   - The parameters have valid names, types and defaults
+//   - The timeouts of the pipeline, jobs and steps are not negative
+//
+// All problems found are returned joined in a single error.
+func (p *Pipeline) Validate() error {
+	var errs []error
+
+	if err := p.On.validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := p.validateParameters(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if p.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("timeout: timeout must not be negative"))
+	}
+
+	if len(p.Stages) == 0 {
+		errs = append(errs, fmt.Errorf("pipeline must declare at least one stage"))
+	}
+
+	stageNames := make(map[string]bool)
+	jobNames := make(map[string]bool)
+	for i, stage := range p.Stages {
+		stagePath := fmt.Sprintf("stages[%d]", i)
+		if strings.TrimSpace(stage.Name) == "" {
+			errs = append(errs, fmt.Errorf("%s: stage name is required", stagePath))
+		} else if stageNames[stage.Name] {
+			errs = append(errs, fmt.Errorf("%s: duplicated stage name %q", stagePath, stage.Name))
+		}
+		stageNames[stage.Name] = true
+
+		if len(stage.Jobs) == 0 {
+			errs = append(errs, fmt.Errorf("%s: stage %q must declare at least one job", stagePath, stage.Name))
+		}
+
+		for j, job := range stage.Jobs {
+			jobPath := fmt.Sprintf("%s.jobs[%d]", stagePath, j)
+			if strings.TrimSpace(job.Name) == "" {
+				errs = append(errs, fmt.Errorf("%s: job name is required", jobPath))
+			} else if jobNames[job.Name] {
+				errs = append(errs, fmt.Errorf("%s: duplicated job name %q", jobPath, job.Name))
+			}
+			jobNames[job.Name] = true
+
+			for k, label := range job.RunsOn {
+				if strings.TrimSpace(label) == "" {
+					errs = append(errs, fmt.Errorf("%s.runs-on[%d]: label must not be blank", jobPath, k))
+				} else if job.Matrix == nil && matrixReference.MatchString(label) {
+					errs = append(errs, fmt.Errorf("%s.runs-on[%d]: job %q does not declare a matrix", jobPath, k, job.Name))
+				}
+			}
+
+			if job.Matrix != nil {
+				if err := job.Matrix.validate(jobPath, job); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if job.Cache != nil {
+				if err := job.Cache.validate(jobPath); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if job.Timeout < 0 {
+				errs = append(errs, fmt.Errorf("%s.timeout: timeout must not be negative", jobPath))
+			}
+
+			if len(job.Steps) == 0 {
+				errs = append(errs, fmt.Errorf("%s: job %q must declare at least one step", jobPath, job.Name))
+			}
+
+			for k, step := range job.Steps {
+				if strings.TrimSpace(step.Run) == "" {
+					errs = append(errs, fmt.Errorf("%s.steps[%d]: step must declare a command to run", jobPath, k))
+				}
+				if step.Timeout < 0 {
+					errs = append(errs, fmt.Errorf("%s.steps[%d].timeout: timeout must not be negative", jobPath, k))
+				}
+				for l, artifact := range step.Artifacts {
+					if err := validatePattern("artifact", artifact); err != nil {
+						errs = append(errs, fmt.Errorf("%s.steps[%d].artifacts[%d]: %w", jobPath, k, l, err))
+					}
+				}
+			}
+		}
+	}
+
+	errs = append(errs, p.validateNeeds()...)
+
+	return errors.Join(errs...)
+}
+
+// Jobs returns all jobs of the pipeline in declaration order.
+func (p *Pipeline) Jobs() []Job {
+	var jobs []Job
+	for _, stage := range p.Stages {
+		jobs = append(jobs, stage.Jobs...)
+	}
+	return jobs
+}
+
+// validatePattern checks that a pattern is a valid glob pattern relative to
+// the workspace, that cannot match files outside of it. kind names what the
+// pattern designates in error messages, such as "artifact".
+func validatePattern(kind, pattern string) error {
+	if strings.TrimSpace(pattern) == "" {
+		return fmt.Errorf("%s must not be blank", kind)
+	}
+	if !filepath.IsLocal(pattern) {
+		return fmt.Errorf("%s %q must be a relative path inside the workspace", kind, pattern)
+	}
+	if _, err := path.Match(filepath.ToSlash(pattern), ""); err != nil {
+		return fmt.Errorf("invalid %s pattern %q", kind, pattern)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `
+name: CI
+env:
+  GOFLAGS: -mod=mod
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        steps:
+          - name: Run tests
+            run: go test ./...
+  - name: build
+    jobs:
+      - name: binary
+        runs-on: [linux, amd64]
+        steps:
+          - run: go build ./...
+`
+	pipeline, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pipeline.Name != "CI" || len(pipeline.Stages) != 2 {
+		t.Fatalf("unexpected pipeline: %+v", pipeline)
+	}
+	if jobs := pipeline.Jobs(); len(jobs) != 2 || jobs[1].Name != "binary" {
+		t.Fatalf("unexpected jobs: %+v", jobs)
+	}
+	if labels := pipeline.Stages[1].Jobs[0].RunsOn; len(labels) != 2 || labels[1] != "amd64" {
+		t.Fatalf("unexpected runs-on labels: %v", labels)
+	}
+}
+
+func TestParseRejectsInvalidPipelines(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		message string
+	}{
+		"no stages": {
+			content: "name: CI\n",
+			message: "at least one stage",
+		},
+		"unknown key": {
+			content: "stages: []\nstep: {}\n",
+			message: "field step not found",
+		},
+		"duplicated job": {
+			content: `
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        steps: [{run: "true"}]
+      - name: unit
+        steps: [{run: "true"}]
+`,
+			message: `stages[0].jobs[1]: duplicated job name "unit"`,
+		},
+		"empty step": {
+			content: `
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        steps: [{name: nothing}]
+`,
+			message: "stages[0].jobs[0].steps[0]: step must declare a command to run",
+		},
+		"blank label": {
+			content: `
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        runs-on: [linux, ""]
+        steps: [{run: "true"}]
+`,
+			message: "stages[0].jobs[0].runs-on[1]: label must not be blank",
+		},
+		"artifact outside workspace": {
+			content: `
+stages:
+  - name: build
+    jobs:
+      - name: binary
+        steps: [{run: "go build", artifacts: [bin/*, ../secrets]}]
+`,
+			message: `stages[0].jobs[0].steps[0].artifacts[1]: artifact "../secrets" must be a relative path inside the workspace`,
+		},
+		"negative timeout": {
+			content: `
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        timeout: 10m
+        steps: [{run: "go test ./...", timeout: -1s}]
+`,
+			message: "stages[0].jobs[0].steps[0].timeout: timeout must not be negative",
+		},
+		"invalid timeout": {
+			content: `
+timeout: forever
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        steps: [{run: "true"}]
+`,
+			message: "failed to parse pipeline",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(test.content))
+			if err == nil || !strings.Contains(err.Error(), test.message) {
+				t.Fatalf("expected error containing %q, got %v", test.message, err)
+			}
+		})
+	}
+}
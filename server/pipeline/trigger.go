@@ -0,0 +1,169 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/schedule"
+)
+
+// Triggers holds the `on` section of a pipeline definition, which restricts the
+// pushes that create builds and schedules builds at given times.
+//
+// A pipeline without a push trigger is built on every push, unless it declares
+// schedules, in which case it is only built on schedule.
+type Triggers struct {
+	Push     *PushTrigger      `yaml:"push"`
+	Schedule []ScheduleTrigger `yaml:"schedule"`
+}
+
+// ScheduleTrigger builds the heads of Branches at the times matching a cron
+// expression, evaluated in UTC. Without branches, the default branch of the
+// repository is built.
+type ScheduleTrigger struct {
+	Cron     string   `yaml:"cron"`
+	Branches []string `yaml:"branches"`
+}
+
+// MatchesPush reports whether a push creates a build of the pipeline.
+//
+// Returns an error if the files changed by the push are needed and cannot be
+// listed.
+func (t Triggers) MatchesPush(push Push) (bool, error) {
+	if t.Push == nil {
+		return len(t.Schedule) == 0, nil
+	}
+	return t.Push.Matches(push)
+}
+
+// validate checks the push filters and the cron expressions of the schedules.
+func (t Triggers) validate() error {
+	var errs []error
+	if t.Push != nil {
+		errs = append(errs, t.Push.validate())
+	}
+	for i, trigger := range t.Schedule {
+		if _, err := schedule.Parse(trigger.Cron); err != nil {
+			errs = append(errs, fmt.Errorf("on.schedule[%d]: %w", i, err))
+		}
+		for j, branch := range trigger.Branches {
+			if strings.TrimSpace(branch) == "" {
+				errs = append(errs, fmt.Errorf("on.schedule[%d].branches[%d]: branch must not be blank", i, j))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PushTrigger filters the pushes that create builds with glob patterns.
+//
+// A pattern is matched segment by segment, split on "/": `*` matches within a
+// single segment and a `**` segment matches any number of segments.
+//
+// A push to a branch creates a build when its branch matches Branches and one of
+// the files it changes matches Paths. A push of a tag creates a build when its
+// tag matches Tags. An empty list matches everything, except that a trigger
+// listing only Tags ignores branch pushes, and one listing only Branches ignores
+// tag pushes.
+type PushTrigger struct {
+	Branches []string `yaml:"branches"`
+	Tags     []string `yaml:"tags"`
+	Paths    []string `yaml:"paths"`
+}
+
+// Push describes a push that may create a build.
+type Push struct {
+	Branch string
+	Tag    string
+	// ChangedFiles lists the files changed by the push. It is only read when
+	// the trigger has path filters.
+	ChangedFiles func() ([]string, error)
+}
+
+// Matches reports whether a push matches the filters of the trigger.
+//
+// Returns an error if the files changed by the push are needed and cannot be
+// listed.
+func (t PushTrigger) Matches(push Push) (bool, error) {
+	if push.Tag != "" {
+		if len(t.Tags) == 0 {
+			return len(t.Branches) == 0, nil
+		}
+		return matchAny(t.Tags, push.Tag), nil
+	}
+
+	if len(t.Branches) == 0 && len(t.Tags) > 0 {
+		return false, nil
+	}
+	if len(t.Branches) > 0 && !matchAny(t.Branches, push.Branch) {
+		return false, nil
+	}
+	if len(t.Paths) == 0 {
+		return true, nil
+	}
+
+	changedFiles, err := push.ChangedFiles()
+	if err != nil {
+		return false, err
+	}
+	for _, file := range changedFiles {
+		if matchAny(t.Paths, file) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validate checks that every pattern of the trigger is well formed.
+func (t PushTrigger) validate() error {
+	var errs []error
+	fields := []struct {
+		name     string
+		patterns []string
+	}{{"branches", t.Branches}, {"tags", t.Tags}, {"paths", t.Paths}}
+	for _, field := range fields {
+		for i, pattern := range field.patterns {
+			if strings.TrimSpace(pattern) == "" {
+				errs = append(errs, fmt.Errorf("on.push.%s[%d]: pattern must not be blank", field.name, i))
+			} else if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("on.push.%s[%d]: invalid pattern %q", field.name, i, pattern))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// matchAny reports whether name matches one of the glob patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches the segments of a name against the segments of a pattern,
+// where a `**` segment matches any number of segments.
+func matchGlob(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(name); skip++ {
+				if matchGlob(pattern[1:], name[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
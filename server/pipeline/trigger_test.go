@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPushTriggerMatches(t *testing.T) {
+	changed := func(files ...string) func() ([]string, error) {
+		return func() ([]string, error) { return files, nil }
+	}
+	tests := map[string]struct {
+		trigger PushTrigger
+		push    Push
+		want    bool
+	}{
+		"no filters": {
+			push: Push{Branch: "wip"},
+			want: true,
+		},
+		"matching branch": {
+			trigger: PushTrigger{Branches: []string{"main", "release/**"}},
+			push:    Push{Branch: "release/1.x/hotfix"},
+			want:    true,
+		},
+		"other branch": {
+			trigger: PushTrigger{Branches: []string{"main", "release/*"}},
+			push:    Push{Branch: "release/1.x/hotfix"},
+		},
+		"branch push with only tag filters": {
+			trigger: PushTrigger{Tags: []string{"v*"}},
+			push:    Push{Branch: "main"},
+		},
+		"matching tag": {
+			trigger: PushTrigger{Branches: []string{"main"}, Tags: []string{"v*"}},
+			push:    Push{Tag: "v1.2.0"},
+			want:    true,
+		},
+		"tag push with only branch filters": {
+			trigger: PushTrigger{Branches: []string{"main"}},
+			push:    Push{Tag: "v1.2.0"},
+		},
+		"matching path": {
+			trigger: PushTrigger{Paths: []string{"server/**/*.go"}},
+			push:    Push{Branch: "main", ChangedFiles: changed("README.md", "server/pipeline/trigger.go")},
+			want:    true,
+		},
+		"other paths": {
+			trigger: PushTrigger{Paths: []string{"server/**/*.go"}},
+			push:    Push{Branch: "main", ChangedFiles: changed("README.md", "client/main.go")},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.trigger.Matches(test.push)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseTriggers(t *testing.T) {
+	content := `
+on:
+  push:
+    branches: [main]
+    paths: ["server/**"]
+stages:
+  - name: test
+    jobs:
+      - name: unit
+        steps: [{run: "true"}]
+`
+	pipeline, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if push := pipeline.On.Push; push == nil || len(push.Branches) != 1 || len(push.Paths) != 1 {
+		t.Fatalf("unexpected push trigger: %+v", push)
+	}
+
+	_, err = Parse([]byte(strings.Replace(content, `"server/**"`, `"server/["`, 1)))
+	if err == nil || !strings.Contains(err.Error(), `on.push.paths[0]: invalid pattern "server/["`) {
+		t.Fatalf("expected invalid pattern error, got %v", err)
+	}
+}
+
+func TestScheduledPipelinesIgnorePushes(t *testing.T) {
+	content := `
+on:
+  schedule:
+    - cron: "0 2 * * *"
+      branches: [main]
+stages:
+  - name: test
+    jobs:
+      - name: integration
+        steps: [{run: "true"}]
+`
+	pipeline, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if triggered, err := pipeline.On.MatchesPush(Push{Branch: "main"}); err != nil || triggered {
+		t.Fatalf("scheduled pipeline should not be built on push, got %v, %v", triggered, err)
+	}
+
+	_, err = Parse([]byte(strings.Replace(content, "0 2 * * *", "0 25 * * *", 1)))
+	if err == nil || !strings.Contains(err.Error(), "on.schedule[0]: invalid hour") {
+		t.Fatalf("expected invalid cron error, got %v", err)
+	}
+	_, err = Parse([]byte(strings.Replace(content, "0 2 * * *", "0 0 31 2 *", 1)))
+	if err == nil || !strings.Contains(err.Error(), "never matches") {
+		t.Fatalf("expected unsatisfiable cron error, got %v", err)
+	}
+}
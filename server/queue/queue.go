@@ -0,0 +1,132 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+)
+
+// Runner runs a build taken from the queue. It is called in its own goroutine
+// with a context that is cancelled when the build is cancelled.
+type Runner func(ctx context.Context, build *pb.BuildResponse)
+
+// Queue dispatches the queued builds recorded in the build store to a bounded
+// number of concurrent runners.
+//
+// Builds are started in the order they were queued, and at most one build of
+// each repository runs at a time, so the builds of a repository run in FIFO
+// order. Since the queue state lives in the build store, queued builds survive
+// a server restart.
+type Queue struct {
+	store         store.BuildStore
+	run           Runner
+	maxConcurrent int
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	busy    map[string]bool
+	wake    chan struct{}
+}
+
+// New creates a Queue running at most maxConcurrent builds at a time with the
+// given runner. A maxConcurrent lower than one is treated as one.
+func New(buildStore store.BuildStore, maxConcurrent int, run Runner) *Queue {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &Queue{
+		store:         buildStore,
+		run:           run,
+		maxConcurrent: maxConcurrent,
+		running:       make(map[string]context.CancelFunc),
+		busy:          make(map[string]bool),
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Start dispatches queued builds in the background until ctx is cancelled.
+//
+// The builds already queued in the store are dispatched right away.
+func (q *Queue) Start(ctx context.Context) {
+	q.Notify()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.wake:
+				q.dispatch()
+			}
+		}
+	}()
+}
+
+// Notify wakes the queue up so that newly queued builds are dispatched.
+func (q *Queue) Notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Cancel cancels the context of a running build.
+//
+// Returns whether the build was running.
+func (q *Queue) Cancel(buildID string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cancel, ok := q.running[buildID]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// dispatch starts the oldest queued builds of the repositories that have no
+// running build, until the concurrency limit is reached.
+func (q *Queue) dispatch() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.running) >= q.maxConcurrent {
+		return
+	}
+
+	queued, err := q.store.ListBuildsByStatus(pb.BuildStatus_QUEUED)
+	if err != nil {
+		log.Printf("Error listing queued builds: %v", err)
+		return
+	}
+
+	for _, build := range queued.Builds {
+		if len(q.running) >= q.maxConcurrent {
+			return
+		}
+		if q.busy[build.RepositoryId] {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		q.running[build.Id] = cancel
+		q.busy[build.RepositoryId] = true
+		log.Printf("Dispatching build %v (%d/%d running)", build.Id, len(q.running), q.maxConcurrent)
+
+		go func(build *pb.BuildResponse) {
+			defer q.done(build, cancel)
+			q.run(ctx, build)
+		}(build)
+	}
+}
+
+// done releases the slot of a finished build and wakes the queue up.
+func (q *Queue) done(build *pb.BuildResponse, cancel context.CancelFunc) {
+	cancel()
+	q.mu.Lock()
+	delete(q.running, build.Id)
+	delete(q.busy, build.RepositoryId)
+	q.mu.Unlock()
+	q.Notify()
+}
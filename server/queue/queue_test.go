@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+)
+
+func TestQueueRunsBuildsInOrderWithinLimit(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "ophelia.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	buildStore := store.NewSQLBuildStore(db)
+
+	var ids []string
+	for _, repo := range []string{"a", "a", "b"} {
+		build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: repo, CommitHash: "abc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, build.Id)
+	}
+
+	var (
+		mu      sync.Mutex
+		order   []string
+		running int
+		peak    int
+	)
+	finished := make(chan struct{}, len(ids))
+	q := New(buildStore, 2, func(ctx context.Context, build *pb.BuildResponse) {
+		mu.Lock()
+		order = append(order, build.Id)
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(200 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		buildStore.UpdateBuildStatus(build.Id, pb.BuildStatus_SUCCESS)
+		finished <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.Start(ctx)
+
+	for range ids {
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			t.Fatal("queued builds were not run")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if peak != 2 {
+		t.Fatalf("expected 2 concurrent builds, got %d", peak)
+	}
+	// The second build of repository a waits for the first one, so it runs
+	// after the build of repository b.
+	if order[2] != ids[1] {
+		t.Fatalf("unexpected order %v for builds %v", order, ids)
+	}
+}
@@ -0,0 +1,159 @@
+package redact
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Replacement replaces the redacted text.
+const Replacement = "***"
+
+const (
+	// privateKeyBegin and privateKeyEnd match the lines delimiting a PEM
+	// private key block.
+	privateKeyBegin = `-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----`
+	privateKeyEnd   = `-----END [A-Z0-9 ]*PRIVATE KEY-----`
+)
+
+var (
+	// DefaultPatterns are always redacted, in addition to the configured
+	// patterns: JSON web tokens and private key blocks, which are redacted up
+	// to the end of the text when they are not terminated.
+	DefaultPatterns = []string{
+		`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+		`(?s)` + privateKeyBegin + `.*?(` + privateKeyEnd + `|\z)`,
+	}
+
+	beginPattern = regexp.MustCompile(privateKeyBegin)
+	endPattern   = regexp.MustCompile(privateKeyEnd)
+)
+
+// Redactor removes sensitive values, their base64 forms, and the text matching
+// its patterns from log output.
+//
+// A Redactor is immutable, and may be used by several goroutines at once. The
+// methods of a nil Redactor leave the text unchanged.
+type Redactor struct {
+	values   []string
+	patterns []*regexp.Regexp
+}
+
+// New creates a Redactor for DefaultPatterns and the given regular
+// expressions.
+func New(patterns ...string) (*Redactor, error) {
+	redactor := &Redactor{}
+	for _, pattern := range append(slices.Clone(DefaultPatterns), patterns...) {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		redactor.patterns = append(redactor.patterns, compiled)
+	}
+	return redactor, nil
+}
+
+// WithValues returns a copy of the Redactor that also redacts the given values
+// and their base64 forms. Empty values are ignored.
+//
+// Each line of a multi-line value is also redacted on its own, so that the
+// value is redacted from output split in lines.
+func (r *Redactor) WithValues(values ...string) *Redactor {
+	if r == nil {
+		r = &Redactor{}
+	}
+	redactor := &Redactor{values: slices.Clone(r.values), patterns: r.patterns}
+	for _, value := range values {
+		forms := []string{
+			value,
+			base64.StdEncoding.EncodeToString([]byte(value)),
+			base64.URLEncoding.EncodeToString([]byte(value)),
+			base64.RawStdEncoding.EncodeToString([]byte(value)),
+			base64.RawURLEncoding.EncodeToString([]byte(value)),
+		}
+		forms = append(forms, strings.Split(value, "\n")...)
+		for _, form := range forms {
+			if form = strings.TrimSuffix(form, "\r"); form != "" && !slices.Contains(redactor.values, form) {
+				redactor.values = append(redactor.values, form)
+			}
+		}
+	}
+	// Longer values are redacted first, so that a value containing another one
+	// is redacted as a whole.
+	slices.SortStableFunc(redactor.values, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	return redactor
+}
+
+// Redact returns text with the sensitive values and the text matching the
+// patterns replaced by Replacement.
+func (r *Redactor) Redact(text string) string {
+	if r == nil {
+		return text
+	}
+	for _, value := range r.values {
+		text = strings.ReplaceAll(text, value, Replacement)
+	}
+	for _, pattern := range r.patterns {
+		text = pattern.ReplaceAllLiteralString(text, Replacement)
+	}
+	return text
+}
+
+// Writer returns a writer redacting each write before passing it to w. Each
+// write must hold whole entries, as the log package does.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	return &writer{redactor: r, w: w}
+}
+
+// Lines returns a Lines redacting a stream of output split in lines with the
+// Redactor.
+func (r *Redactor) Lines() *Lines {
+	return &Lines{redactor: r}
+}
+
+// Lines redacts a stream of output split in lines. Unlike Redact, it also
+// redacts the private key blocks spanning several lines.
+//
+// A Lines must only be used for a single stream.
+type Lines struct {
+	redactor *Redactor
+	inBlock  bool
+}
+
+// Redact returns the next line of the stream, redacted.
+func (l *Lines) Redact(line string) string {
+	if l.redactor == nil {
+		return line
+	}
+	if l.inBlock {
+		end := endPattern.FindStringIndex(line)
+		if end == nil {
+			return Replacement
+		}
+		l.inBlock = false
+		line = Replacement + line[end[1]:]
+	}
+	if begin := beginPattern.FindStringIndex(line); begin != nil && !endPattern.MatchString(line[begin[1]:]) {
+		l.inBlock = true
+		line = line[:begin[0]] + Replacement
+	}
+	return l.redactor.Redact(line)
+}
+
+// writer redacts the writes of the log package.
+type writer struct {
+	redactor *Redactor
+	w        io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.w, w.redactor.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -6,16 +6,23 @@ import (
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
 	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // CreateRepository creates a new repository with the given information.
 //
 // The request must contain the repository name, description and gitignore.
 // The gitignore is used to generate the base .gitignore file for the repository.
+// The request may contain the number of days the build artifacts of the
+// repository are kept, which otherwise defaults to the server configuration.
 //
 // The response will contain the created repository information.
 func (s *server) CreateRepository(ctx context.Context, req *pb.CreateRepositoryRequest) (*pb.RepositoryResponse, error) {
 	log.Printf("Creating repository with request: %v", req)
+	if req.ArtifactRetentionDays < 0 {
+		return nil, status.Error(codes.InvalidArgument, "artifact retention days must not be negative")
+	}
 	log.Printf("Creating git repository for %v", req.Name)
 	err := git.CreateGitRepository(getRepoPath(req.Name), req.Gitignore)
 	if err != nil {
@@ -28,6 +35,9 @@ func (s *server) CreateRepository(ctx context.Context, req *pb.CreateRepositoryR
 		log.Printf("Error creating repository: %v", err)
 		return nil, err
 	}
+	if err := s.setArtifactRetention(&response, req.ArtifactRetentionDays); err != nil {
+		return nil, err
+	}
 	return &response, err
 }
 
@@ -36,10 +46,15 @@ func (s *server) CreateRepository(ctx context.Context, req *pb.CreateRepositoryR
 // The request must contain the repository ID, name and description.
 // The ID is used to identify the repository to be updated.
 // The name and description are used to update the repository information.
+// The number of days the build artifacts of the repository are kept is only
+// updated if it is set.
 //
 // The response will contain the updated repository information.
 func (s *server) UpdateRepository(ctx context.Context, req *pb.UpdateRepositoryRequest) (*pb.RepositoryResponse, error) {
 	log.Printf("Updating repository with request: %v", req)
+	if req.ArtifactRetentionDays < 0 {
+		return nil, status.Error(codes.InvalidArgument, "artifact retention days must not be negative")
+	}
 	log.Printf("Getting repository with id: %v", req.Id)
 	old_repo, err := s.repositorieStore.GetRepository(req.Id)
 	if err != nil {
@@ -63,10 +78,13 @@ func (s *server) UpdateRepository(ctx context.Context, req *pb.UpdateRepositoryR
 		}
 		return nil, err
 	}
+	if err := s.setArtifactRetention(&response, req.ArtifactRetentionDays); err != nil {
+		return nil, err
+	}
 	return &response, err
 }
 
-// ListRepository lists all existing repositories.
+// ListRepository lists the existing repositories the caller may read.
 //
 // The request must contain an empty request message.
 // The response will contain a list of existing repositories.
@@ -76,7 +94,15 @@ func (s *server) ListRepository(ctx context.Context, req *pb.Empty) (*pb.ListRep
 		log.Printf("Error listing repositories: %v", err)
 		return nil, err
 	}
-	return &repos, err
+	readable := &pb.ListRepositoryResponse{}
+	for _, repo := range repos.Repositories {
+		if !s.canRead(ctx, repo.Id) {
+			continue
+		}
+		repo.ArtifactRetentionDays = int32(s.retentionDays(repo.Id))
+		readable.Repositories = append(readable.Repositories, repo)
+	}
+	return readable, err
 }
 
 // GetRepository gets a repository by either its ID or name.
@@ -97,12 +123,14 @@ func (s *server) GetRepository(ctx context.Context, req *pb.GetRepositoryRequest
 		log.Printf("Error getting repository: %v", err)
 		return nil, err
 	}
+	response.ArtifactRetentionDays = int32(s.retentionDays(response.Id))
 	return response, err
 }
 
 // DeleteRepository deletes an existing repository.
 //
-// The request must contain the ID of the repository to be deleted.
+// The request must contain the ID of the repository to be deleted. Its
+// secrets and the roles granted on it are deleted with it.
 //
 // The response will contain an empty message on success.
 func (s *server) DeleteRepository(ctx context.Context, req *pb.DeleteRepositoryRequest) (*pb.Empty, error) {
@@ -133,10 +161,30 @@ func (s *server) DeleteRepository(ctx context.Context, req *pb.DeleteRepositoryR
 		}
 		return nil, err
 	}
+	if err := s.secretStore.DeleteSecrets(old_repo.Id); err != nil {
+		log.Printf("Error deleting secrets of repository: %v", err)
+	}
+	if err := s.roleStore.DeleteRepositoryRoles(old_repo.Id); err != nil {
+		log.Printf("Error deleting roles of repository: %v", err)
+	}
 
 	return &pb.Empty{}, err
 }
 
+// setArtifactRetention sets the number of days the build artifacts of a
+// repository are kept if days is not zero, and fills the effective number of
+// days in the repository response.
+func (s *server) setArtifactRetention(repo *pb.RepositoryResponse, days int32) error {
+	if days > 0 {
+		if err := s.artifactStore.SetRetention(repo.Id, int(days)); err != nil {
+			log.Printf("Error setting artifact retention: %v", err)
+			return err
+		}
+	}
+	repo.ArtifactRetentionDays = int32(s.retentionDays(repo.Id))
+	return nil
+}
+
 // getRepoPath constructs the file path for the Git repository.
 //
 // Parameters:
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/permission"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GrantRepositoryAccess grants a role on a repository to a user, replacing any
+// role previously granted to them on the repository.
+//
+// The role granted may not be higher than the role of the caller on the
+// repository, so that maintainers cannot make other users administrators.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the repository name, the username and the
+//     role to grant.
+//
+// Returns:
+//   - *pb.Empty: An empty response.
+//   - error: An error if the repository or the user does not exist, or the
+//     role is invalid.
+func (s *server) GrantRepositoryAccess(ctx context.Context, req *pb.GrantRepositoryAccessRequest) (*pb.Empty, error) {
+	log.Printf("Granting %v on repository %v to %v", req.Role, req.Repository, req.Username)
+	if _, ok := pb.Role_name[int32(req.Role)]; !ok || req.Role == pb.Role_NONE {
+		return nil, status.Error(codes.InvalidArgument, "a role is required")
+	}
+	repo, err := s.namedRepository(req.Repository)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.roleUser(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if caller := usernameFromContext(ctx); caller != "" {
+		role, err := s.userRole(caller, repo.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !permission.Allows(role, req.Role) {
+			return nil, status.Errorf(codes.PermissionDenied, "cannot grant a role higher than %v", role)
+		}
+	}
+
+	if err := s.roleStore.SetRepositoryRole(repo.Id, user.Id, req.Role); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// RevokeRepositoryAccess revokes the role granted to a user on a repository.
+// The global role of the user is left unchanged.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the repository name and the username.
+//
+// Returns:
+//   - *pb.Empty: An empty response.
+//   - error: An error if the repository or the user does not exist, or the user
+//     has no role on the repository.
+func (s *server) RevokeRepositoryAccess(ctx context.Context, req *pb.RevokeRepositoryAccessRequest) (*pb.Empty, error) {
+	log.Printf("Revoking access to repository %v from %v", req.Repository, req.Username)
+	repo, err := s.namedRepository(req.Repository)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.roleUser(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.roleStore.DeleteRepositoryRole(repo.Id, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, status.Errorf(codes.NotFound, "%s has no role on %s", user.Username, repo.Name)
+	}
+	return &pb.Empty{}, nil
+}
+
+// SetUserRole sets the global role of a user, which applies to every
+// repository. Setting the NONE role leaves the user with the roles granted to
+// them on repositories only.
+//
+// Users cannot change their own role, so that the last administrator cannot
+// lock everyone out.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the username and the role.
+//
+// Returns:
+//   - *pb.Empty: An empty response.
+//   - error: An error if the user does not exist or the role is invalid.
+func (s *server) SetUserRole(ctx context.Context, req *pb.SetUserRoleRequest) (*pb.Empty, error) {
+	log.Printf("Setting role of %v to %v", req.Username, req.Role)
+	if _, ok := pb.Role_name[int32(req.Role)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %v", req.Role)
+	}
+	if req.Username == usernameFromContext(ctx) {
+		return nil, status.Error(codes.FailedPrecondition, "cannot change your own role")
+	}
+	user, err := s.roleUser(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roleStore.SetUserRole(user.Id, req.Role); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// roleUser gets the user whose roles are managed by username.
+func (s *server) roleUser(username string) (*pb.UserResponse, error) {
+	user, err := s.userStore.GetUserByUsername(username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", username)
+	}
+	if err != nil {
+		log.Printf("Error getting user: %v", err)
+		return nil, err
+	}
+	return user, nil
+}
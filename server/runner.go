@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/dispatch"
+)
+
+// startLocalRunners starts workers leasing jobs from the dispatcher and running
+// them with the local executor, until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: The context stopping the workers.
+//   - count: The number of jobs run at the same time.
+//   - labels: The labels of the local executor, matched against the runs-on
+//     labels of jobs.
+func (s *server) startLocalRunners(ctx context.Context, count int, labels []string) {
+	s.localRunner = &dispatch.Runner{ID: "local", Name: "local", Labels: labels, Local: true}
+	for range count {
+		go func() {
+			for {
+				lease, err := s.dispatcher.Lease(ctx, *s.localRunner)
+				if err != nil {
+					return
+				}
+				s.jobLeased(lease)
+				result := s.runLocalJob(lease)
+				if err := s.dispatcher.Complete(lease.ID, s.localRunner.ID, result); err != nil {
+					log.Printf("Error completing lease %v: %v", lease.ID, err)
+				}
+			}
+		}()
+	}
+}
+
+// checkSchedulable checks that the local executor or a registered runner has
+// all the labels required by a job, so that jobs no runner can ever lease are
+// reported instead of waiting forever.
+//
+// Registered runners that have not called the server within the lease timeout
+// are ignored, as they are likely gone. Runners waiting for a job call the
+// server at least every maxLeaseWait.
+//
+// Returns an error explaining why the job cannot be scheduled, or nil.
+func (s *server) checkSchedulable(labels []string) error {
+	if len(labels) == 0 && s.localRunner != nil {
+		return nil
+	}
+	runners := []dispatch.Runner{}
+	if s.localRunner != nil {
+		runners = append(runners, *s.localRunner)
+	}
+	registered, err := s.runnerStore.ListRunners()
+	if err != nil {
+		return fmt.Errorf("failed to list runners: %w", err)
+	}
+	seenSince := time.Now().Add(-max(s.dispatcher.Timeout(), maxLeaseWait))
+	for _, runner := range registered {
+		if runner.LastSeen == nil || runner.LastSeen.AsTime().Before(seenSince) {
+			continue
+		}
+		runners = append(runners, dispatch.Runner{ID: runner.Id, Name: runner.Name, Labels: runner.Labels})
+	}
+
+	if len(runners) == 0 {
+		return fmt.Errorf("no runner is connected and the local executor is disabled")
+	}
+	for _, runner := range runners {
+		if runner.CanRun(labels) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no runner has all the labels %v", labels)
+}
+
+// runLocalJob runs a leased job in a fresh workspace cloned from the
+// repository, storing its artifacts and removing the workspace once the job
+// finishes.
+func (s *server) runLocalJob(lease *dispatch.Lease) dispatch.Result {
+	job := lease.Job
+	workspace, err := s.executor.PrepareWorkspace(lease.ID, job.Build)
+	if err != nil {
+		log.Printf("Error preparing workspace for job %v: %v", job.ID, err)
+		fmt.Fprintf(job.Output, "Failed to prepare workspace: %v\n", err)
+		return dispatch.Result{ExitCode: -1}
+	}
+	defer s.executor.Cleanup(workspace)
+
+	result := s.executor.RunJob(lease.Context(), workspace, job.Build, job.Definition, job.Output)
+	s.collectArtifacts(job, workspace, result)
+	return dispatch.Result{Success: result.Success, ExitCode: int32(result.ExitCode()), TimedOut: result.TimedOut}
+}
+
+// jobLeased records that a job was handed to a runner.
+func (s *server) jobLeased(lease *dispatch.Lease) {
+	log.Printf("Job %v leased to runner %v", lease.Job.ID, lease.Runner.Name)
+	s.buildStore.UpdateJobStatus(lease.Job.ID, pb.BuildStatus_RUNNING, 0)
+	fmt.Fprintf(lease.Job.Output, "Running on runner %s\n", lease.Runner.Name)
+}
+
+// leaseExpired records that the runner of a job stopped sending heartbeats,
+// before the job is handed to another runner.
+func (s *server) leaseExpired(lease *dispatch.Lease) {
+	s.buildStore.UpdateJobStatus(lease.Job.ID, pb.BuildStatus_QUEUED, 0)
+	fmt.Fprintf(lease.Job.Output, "Runner %s stopped responding, job requeued\n", lease.Runner.Name)
+}
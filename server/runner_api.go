@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/dispatch"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// maxLeaseWait bounds how long LeaseJob waits for a job before returning
+	// an empty lease, so that runners poll again periodically.
+	maxLeaseWait = 30 * time.Second
+	// sourceChunkSize is the size of the chunks of the source archives sent
+	// to runners, and of the artifacts archives sent to clients.
+	sourceChunkSize = 64 * 1024
+)
+
+// RegisterRunner registers a runner and returns the token it uses to call the
+// other methods of the runner service.
+//
+// The request must contain the registration token configured on the server,
+// the name of the runner and the labels it advertises. A runner registering
+// again with the same name keeps its ID and has its labels replaced.
+//
+// The response will contain the ID of the runner and its token.
+func (s *server) RegisterRunner(ctx context.Context, req *pb.RegisterRunnerRequest) (*pb.RegisterRunnerResponse, error) {
+	log.Printf("Registering runner %v with labels %v", req.Name, req.Labels)
+	if s.registrationToken == "" {
+		return nil, status.Error(codes.PermissionDenied, "runner registration is disabled")
+	}
+	if subtle.ConstantTimeCompare([]byte(req.RegistrationToken), []byte(s.registrationToken)) != 1 {
+		log.Printf("Invalid registration token for runner %v", req.Name)
+		return nil, status.Error(codes.PermissionDenied, "invalid registration token")
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "runner name is required")
+	}
+
+	runner, err := s.runnerStore.RegisterRunner(req.Name, req.Labels)
+	if err != nil {
+		log.Printf("Error registering runner: %v", err)
+		return nil, err
+	}
+
+	token, err := generateRunnerJWT(runner.Id, LoadConfig().Server.ExpirationTime)
+	if err != nil {
+		log.Printf("Error generating runner token: %v", err)
+		return nil, err
+	}
+	return &pb.RegisterRunnerResponse{Id: runner.Id, Token: token}, nil
+}
+
+// LeaseJob hands the next queued job to the calling runner.
+//
+// The call waits for a job for at most the requested number of seconds, bounded
+// by the server. If no job becomes available, the response has an empty lease ID.
+//
+// The response will contain the lease ID, the build information, the job
+// definition and the interval at which the runner must send heartbeats. The
+// secrets of the repository are only sent to runners connected over TLS, while
+// the local executor reads them in the server process.
+func (s *server) LeaseJob(ctx context.Context, req *pb.LeaseJobRequest) (*pb.LeaseJobResponse, error) {
+	runner, err := s.runnerStore.GetRunner(runnerFromContext(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "unknown runner")
+	}
+	s.runnerStore.UpdateLastSeen(runner.Id)
+
+	wait := time.Duration(req.WaitSeconds) * time.Second
+	if wait <= 0 || wait > maxLeaseWait {
+		wait = maxLeaseWait
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	lease, err := s.dispatcher.Lease(waitCtx, dispatch.Runner{ID: runner.Id, Name: runner.Name, Labels: runner.Labels})
+	if err != nil {
+		return &pb.LeaseJobResponse{}, nil
+	}
+	s.jobLeased(lease)
+
+	job := lease.Job
+	secrets := job.Build.Secrets
+	if len(secrets) > 0 && !secureConnection(ctx) {
+		log.Printf("Not sending the secrets of job %v to runner %v, which is connected without TLS", job.ID, runner.Name)
+		fmt.Fprintf(job.Output, "Secrets are not sent to runner %s, since it is connected without TLS\n", runner.Name)
+		secrets = nil
+	}
+	return &pb.LeaseJobResponse{
+		LeaseId:           lease.ID,
+		BuildId:           job.Build.ID,
+		JobId:             job.ID,
+		Repository:        job.Build.Repository,
+		CommitHash:        job.Build.Revision,
+		Branch:            job.Build.Branch,
+		Tag:               job.Build.Tag,
+		Env:               job.Build.Pipeline.Env,
+		Job:               jobDefinition(job),
+		HeartbeatInterval: int64(s.dispatcher.Timeout().Seconds() / 3),
+		Parameters:        job.Build.Parameters,
+		Secrets:           secrets,
+	}, nil
+}
+
+// secureConnection reports whether the call in ctx was received over TLS, so
+// that secrets are never sent in plaintext.
+func secureConnection(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	_, ok = p.AuthInfo.(credentials.TLSInfo)
+	return ok
+}
+
+// Heartbeat renews a lease held by the calling runner.
+//
+// The request must contain the ID of the lease.
+//
+// The response tells whether the job was cancelled, in which case the runner
+// must stop it and complete the lease.
+func (s *server) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	runnerID := runnerFromContext(ctx)
+	s.runnerStore.UpdateLastSeen(runnerID)
+	cancelled, err := s.dispatcher.Heartbeat(req.LeaseId, runnerID)
+	if err != nil {
+		return nil, leaseError(err)
+	}
+	return &pb.HeartbeatResponse{Cancelled: cancelled}, nil
+}
+
+// DownloadSource streams a tar archive of the repository files at the commit
+// of a job leased by the calling runner, so that runners never access the
+// repositories directly.
+//
+// The request must contain the ID of the lease.
+func (s *server) DownloadSource(req *pb.DownloadSourceRequest, stream pb.RunnerService_DownloadSourceServer) error {
+	lease, err := s.dispatcher.Get(req.LeaseId, runnerFromContext(stream.Context()))
+	if err != nil {
+		return leaseError(err)
+	}
+
+	build := lease.Job.Build
+	writer := &sourceWriter{stream: stream}
+	if err := git.ArchiveAtRevision(build.RepositoryPath, build.Revision, writer); err != nil {
+		log.Printf("Error sending source of %v at %v: %v", build.Repository, build.Revision, err)
+		return err
+	}
+	return writer.flush()
+}
+
+// UploadArtifact stores the artifacts archive of a job, replacing any archive
+// previously uploaded for it.
+//
+// The stream must contain the gzipped tar archive, split in chunks that all
+// carry the ID of the lease.
+func (s *server) UploadArtifact(stream pb.RunnerService_UploadArtifactServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	lease, err := s.dispatcher.Get(req.LeaseId, runnerFromContext(stream.Context()))
+	if err != nil {
+		return leaseError(err)
+	}
+
+	err = s.saveArtifacts(lease.Job, func(w io.Writer) error {
+		for {
+			if _, err := w.Write(req.Data); err != nil {
+				return err
+			}
+			req, err = stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if req.LeaseId != lease.ID {
+				return status.Error(codes.InvalidArgument, "artifact chunks must belong to a single lease")
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.Empty{})
+}
+
+// UploadLogChunk appends output of a job to the build log.
+//
+// The request must contain the ID of the lease and the output. Chunks do not
+// need to end at line boundaries.
+func (s *server) UploadLogChunk(ctx context.Context, req *pb.UploadLogChunkRequest) (*pb.Empty, error) {
+	lease, err := s.dispatcher.Get(req.LeaseId, runnerFromContext(ctx))
+	if err != nil {
+		return nil, leaseError(err)
+	}
+	if _, err := lease.Job.Output.Write(req.Data); err != nil {
+		log.Printf("Error writing log of job %v: %v", lease.Job.ID, err)
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// CompleteJob releases a lease held by the calling runner with the result of
+// its job.
+//
+// The request must contain the ID of the lease, whether the job succeeded and
+// the exit code of its last step, and whether it was stopped by a timeout.
+func (s *server) CompleteJob(ctx context.Context, req *pb.CompleteJobRequest) (*pb.Empty, error) {
+	err := s.dispatcher.Complete(req.LeaseId, runnerFromContext(ctx), dispatch.Result{
+		Success:  req.Success,
+		ExitCode: req.ExitCode,
+		TimedOut: req.TimedOut,
+	})
+	if err != nil {
+		return nil, leaseError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+// jobDefinition converts the definition of a leased job to its protobuf form.
+func jobDefinition(job *dispatch.Job) *pb.JobDefinition {
+	definition := &pb.JobDefinition{Name: job.Definition.Name, Env: job.Definition.Env, Timeout: durationProto(job.Definition.Timeout)}
+	for _, step := range job.Definition.Steps {
+		definition.Steps = append(definition.Steps, &pb.StepDefinition{
+			Name:      step.Name,
+			Run:       step.Run,
+			Env:       step.Env,
+			Artifacts: step.Artifacts,
+			Timeout:   durationProto(step.Timeout),
+		})
+	}
+	if job.Definition.Cache != nil {
+		definition.Cache = &pb.CacheDefinition{Key: job.Definition.Cache.Key, Paths: job.Definition.Cache.Paths}
+	}
+	return definition
+}
+
+// durationProto converts a timeout to its protobuf form, omitted when zero.
+func durationProto(timeout time.Duration) *durationpb.Duration {
+	if timeout == 0 {
+		return nil
+	}
+	return durationpb.New(timeout)
+}
+
+// leaseError converts a dispatcher error to a gRPC status error.
+func leaseError(err error) error {
+	if errors.Is(err, dispatch.ErrLeaseNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
+}
+
+// sourceWriter sends the data written to it to a DownloadSource stream, in
+// chunks of sourceChunkSize bytes.
+type sourceWriter struct {
+	stream pb.RunnerService_DownloadSourceServer
+	buffer []byte
+}
+
+func (w *sourceWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+	for len(w.buffer) >= sourceChunkSize {
+		if err := w.stream.Send(&pb.SourceChunk{Data: w.buffer[:sourceChunkSize]}); err != nil {
+			return 0, err
+		}
+		w.buffer = w.buffer[sourceChunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush sends the remaining buffered data.
+func (w *sourceWriter) flush() error {
+	if len(w.buffer) == 0 {
+		return nil
+	}
+	return w.stream.Send(&pb.SourceChunk{Data: w.buffer})
+}
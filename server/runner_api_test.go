@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestSecureConnection(t *testing.T) {
+	if secureConnection(context.Background()) {
+		t.Error("expected a call without a peer not to be secure")
+	}
+	plaintext := peer.NewContext(context.Background(), &peer.Peer{})
+	if secureConnection(plaintext) {
+		t.Error("expected a call without TLS not to be secure")
+	}
+	tls := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}})
+	if !secureConnection(tls) {
+		t.Error("expected a call over TLS to be secure")
+	}
+}
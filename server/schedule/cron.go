@@ -0,0 +1,179 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// macros are the shorthands accepted in place of the five cron fields.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field describes the range of values of a cron field.
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// Cron is a parsed cron expression. Times are evaluated in UTC.
+type Cron struct {
+	minutes, hours, days, months, weekdays uint64
+	// anyDay and anyWeekday record whether the day fields are `*`, since a
+	// day matches either of them when both are restricted.
+	anyDay, anyWeekday bool
+}
+
+// Parse parses a cron expression with the five standard fields (minute, hour,
+// day of month, month and day of week), or one of the @yearly, @monthly,
+// @weekly, @daily and @hourly macros.
+//
+// Each field is `*` or a comma separated list of values and ranges (`1-5`),
+// each optionally followed by a step (`*/15`, `0-30/10`). Sunday is 0, and 7
+// is accepted as an alias of it.
+//
+// Expressions that never match, such as `0 0 31 2 *`, are rejected.
+func Parse(expression string) (*Cron, error) {
+	if macro, ok := macros[strings.TrimSpace(expression)]; ok {
+		expression = macro
+	}
+	parts := strings.Fields(expression)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields", expression, len(fields))
+	}
+
+	var sets [5]uint64
+	for i, part := range parts {
+		max := fields[i].max
+		if i == 4 {
+			max = 7
+		}
+		set, err := parseField(part, fields[i].min, max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in cron expression %q: %w", fields[i].name, expression, err)
+		}
+		sets[i] = set
+	}
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	cron := &Cron{
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   sets[4],
+		anyDay:     parts[2] == "*",
+		anyWeekday: parts[4] == "*",
+	}
+	if cron.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("cron expression %q never matches", expression)
+	}
+	return cron, nil
+}
+
+// Next returns the first time strictly after t that matches the expression,
+// truncated to the minute, or the zero time if it never matches.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Every expression that can match does so at least once in a leap cycle.
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(c.months, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !has(c.hours, t.Hour()):
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !has(c.minutes, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchesDay reports whether the day of t matches the day of month and day of
+// week fields. When both are restricted, matching either is enough.
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := has(c.days, t.Day())
+	weekday := has(c.weekdays, int(t.Weekday()))
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// parseField parses a cron field into a bit set of the values it matches.
+func parseField(expression string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(expression, ",") {
+		rangeExpression, stepExpression, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpression)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepExpression)
+			}
+		}
+
+		start, end := min, max
+		if rangeExpression != "*" {
+			first, last, isRange := strings.Cut(rangeExpression, "-")
+			var err error
+			if start, err = parseValue(first, min, max); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(last, min, max); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = max
+			}
+			if end < start {
+				return 0, fmt.Errorf("invalid range %q", rangeExpression)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			set |= 1 << value
+		}
+	}
+	return set, nil
+}
+
+// parseValue parses a single value of a cron field.
+func parseValue(expression string, min, max int) (int, error) {
+	value, err := strconv.Atoi(expression)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("value %q is not between %d and %d", expression, min, max)
+	}
+	return value, nil
+}
+
+// has reports whether value is in the bit set.
+func has(set uint64, value int) bool {
+	return set&(1<<value) != 0
+}
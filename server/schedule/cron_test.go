@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	from := time.Date(2026, time.October, 16, 22, 30, 15, 0, time.UTC) // a Friday
+	tests := map[string]time.Time{
+		"*/15 * * * *":  time.Date(2026, time.October, 16, 22, 45, 0, 0, time.UTC),
+		"0 2 * * *":     time.Date(2026, time.October, 17, 2, 0, 0, 0, time.UTC),
+		"@daily":        time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC),
+		"0 9 * * 1-5":   time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC),
+		"0 0 * * 7":     time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		"0 0 1 * 0":     time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":    time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"30 22 16 10 *": time.Date(2027, time.October, 16, 22, 30, 0, 0, time.UTC),
+	}
+
+	for expression, want := range tests {
+		t.Run(expression, func(t *testing.T) {
+			cron, err := Parse(expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := cron.Next(from); !got.Equal(want) {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@often", "0 0 31 2 *", "0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("expected %q to be rejected", expression)
+		}
+	}
+}
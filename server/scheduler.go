@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/schedule"
+)
+
+// scheduleTriggerUser is recorded as the user who triggered scheduled builds.
+const scheduleTriggerUser = "schedule"
+
+// startScheduler queues the builds of scheduled pipelines, checking the
+// schedules at the start of every minute until ctx is cancelled.
+func (s *server) startScheduler(ctx context.Context) {
+	go func() {
+		for {
+			now := time.Now().UTC()
+			timer := time.NewTimer(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			s.fireSchedules(time.Now().UTC().Truncate(time.Minute))
+		}
+	}()
+}
+
+// fireSchedules queues a build for every schedule that is due at now.
+//
+// The schedules are read from the pipeline at the head of the default branch
+// of each repository.
+func (s *server) fireSchedules(now time.Time) {
+	repos, err := s.repositorieStore.ListRepositories()
+	if err != nil {
+		log.Printf("Error listing repositories: %v", err)
+		return
+	}
+
+	for _, repo := range repos.Repositories {
+		repoPath := getRepoPath(repo.Name)
+		defaultBranch, err := git.DefaultBranch(repoPath)
+		if err != nil {
+			continue
+		}
+		definition, err := loadBranchPipeline(repoPath, defaultBranch)
+		if err != nil {
+			if !errors.Is(err, pipeline.ErrNotFound) {
+				log.Printf("Error loading pipeline of %v: %v", repo.Name, err)
+			}
+			continue
+		}
+
+		for _, trigger := range definition.On.Schedule {
+			cron, err := schedule.Parse(trigger.Cron)
+			if err != nil {
+				continue
+			}
+			branches := trigger.Branches
+			if len(branches) == 0 {
+				branches = []string{defaultBranch}
+			}
+			for _, branch := range branches {
+				s.fireSchedule(repo, branch, trigger.Cron, cron, now)
+			}
+		}
+	}
+}
+
+// fireSchedule queues a build of a branch if its schedule is due at now.
+//
+// A schedule is due when it matches a time after the last time it fired, so a
+// schedule missed while the server was down fires once at the next check. The
+// firing time is recorded before the build is queued, so that a restart never
+// fires the same schedule twice. A schedule seen for the first time is only due
+// if it matches now, and a schedule that never matches is never due.
+func (s *server) fireSchedule(repo *pb.RepositoryResponse, branch, expression string, cron *schedule.Cron, now time.Time) {
+	lastFired, seen, err := s.scheduleStore.GetLastFired(repo.Id, branch, expression)
+	if err != nil {
+		return
+	}
+	if !seen {
+		lastFired = now.Add(-time.Minute)
+	}
+	if next := cron.Next(lastFired); next.IsZero() || next.After(now) {
+		if !seen {
+			s.scheduleStore.SetLastFired(repo.Id, branch, expression, lastFired)
+		}
+		return
+	}
+
+	if err := s.scheduleStore.SetLastFired(repo.Id, branch, expression, now); err != nil {
+		return
+	}
+	log.Printf("Schedule %q of %v is due for branch %v", expression, repo.Name, branch)
+	if err := s.enqueueScheduledBuild(repo, branch); err != nil {
+		log.Printf("Error queuing scheduled build of %v on %v: %v", repo.Name, branch, err)
+	}
+}
+
+// enqueueScheduledBuild queues a build of the head of a repository branch, with
+// the default values of the pipeline parameters.
+func (s *server) enqueueScheduledBuild(repo *pb.RepositoryResponse, branch string) error {
+	repoPath := getRepoPath(repo.Name)
+	commitHash, err := git.ResolveRevision(repoPath, "refs/heads/"+branch)
+	if err != nil {
+		return err
+	}
+	definition, err := pipeline.Load(repoPath, commitHash)
+	if err != nil {
+		return err
+	}
+	parameters, err := definition.ResolveParameters(nil)
+	if err != nil {
+		return err
+	}
+
+	build, err := s.enqueueBuild(repo, &pb.BuildResponse{
+		CommitHash:  commitHash,
+		Branch:      branch,
+		TriggerUser: scheduleTriggerUser,
+		Parameters:  parameters,
+	}, definition, false)
+	if err != nil {
+		return err
+	}
+	log.Printf("Scheduled build %v queued for %v at %v", build.Id, repo.Name, commitHash)
+	return nil
+}
+
+// loadBranchPipeline loads the pipeline at the head of a repository branch.
+func loadBranchPipeline(repoPath, branch string) (*pipeline.Pipeline, error) {
+	commitHash, err := git.ResolveRevision(repoPath, "refs/heads/"+branch)
+	if err != nil {
+		return nil, err
+	}
+	return pipeline.Load(repoPath, commitHash)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/schedule"
+)
+
+const scheduleBranch = "main"
+
+// scheduleStore is an in-memory store.ScheduleStore recording every firing
+// time set.
+type scheduleStore struct {
+	lastFired map[string]time.Time
+	writes    []time.Time
+}
+
+func (s *scheduleStore) CreateTable() error {
+	return nil
+}
+
+func (s *scheduleStore) GetLastFired(repositoryId, branch, cron string) (time.Time, bool, error) {
+	lastFired, ok := s.lastFired[repositoryId+" "+branch+" "+cron]
+	return lastFired, ok, nil
+}
+
+func (s *scheduleStore) SetLastFired(repositoryId, branch, cron string, firedAt time.Time) error {
+	s.lastFired[repositoryId+" "+branch+" "+cron] = firedAt
+	s.writes = append(s.writes, firedAt)
+	return nil
+}
+
+// newScheduleServer returns a server with an in-memory schedule store, and a
+// repository that does not exist on disk, so that due schedules fail to queue
+// their build after recording that they fired.
+func newScheduleServer() (*server, *scheduleStore, *pb.RepositoryResponse) {
+	schedules := &scheduleStore{lastFired: map[string]time.Time{}}
+	return &server{scheduleStore: schedules}, schedules, &pb.RepositoryResponse{Id: "repository", Name: "missing-repository"}
+}
+
+// fires runs fireSchedule at now, and reports whether the schedule fired, which
+// is when it records now as its firing time.
+func fires(s *server, schedules *scheduleStore, repo *pb.RepositoryResponse, expression string, cron *schedule.Cron, now time.Time) bool {
+	writes := len(schedules.writes)
+	s.fireSchedule(repo, scheduleBranch, expression, cron, now)
+	return len(schedules.writes) > writes && schedules.writes[len(schedules.writes)-1].Equal(now)
+}
+
+func TestFireScheduleFiresOncePerMatch(t *testing.T) {
+	s, schedules, repo := newScheduleServer()
+	const expression = "0 * * * *"
+	cron, err := schedule.Parse(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+
+	if fires(s, schedules, repo, expression, cron, start) {
+		t.Fatal("expected a new schedule not to fire when it does not match now")
+	}
+	nextHour := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if !fires(s, schedules, repo, expression, cron, nextHour) {
+		t.Fatal("expected the schedule to fire when it matches")
+	}
+	if fires(s, schedules, repo, expression, cron, nextHour) {
+		t.Fatal("expected the schedule not to fire twice for the same match")
+	}
+	if fires(s, schedules, repo, expression, cron, nextHour.Add(time.Minute)) {
+		t.Fatal("expected the schedule not to fire before its next match")
+	}
+}
+
+func TestFireScheduleCatchesUpMissedMatchesOnce(t *testing.T) {
+	s, schedules, repo := newScheduleServer()
+	const expression = "0 * * * *"
+	cron, err := schedule.Parse(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	if !fires(s, schedules, repo, expression, cron, start) {
+		t.Fatal("expected a new schedule to fire when it matches now")
+	}
+
+	// The server was down through the 08:00, 09:00 and 10:00 matches.
+	restart := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	if !fires(s, schedules, repo, expression, cron, restart) {
+		t.Fatal("expected the missed matches to fire at the next check")
+	}
+	if fires(s, schedules, repo, expression, cron, restart.Add(time.Minute)) {
+		t.Fatal("expected the missed matches to fire a single build")
+	}
+}
+
+func TestFireScheduleNeverFiresUnsatisfiableSchedules(t *testing.T) {
+	if _, err := schedule.Parse("0 0 31 2 *"); err == nil {
+		t.Fatal("expected an expression that never matches to be rejected")
+	}
+
+	s, schedules, repo := newScheduleServer()
+	const expression = "0 0 31 2 *"
+	// A zero cron matches no time, as an unsatisfiable expression would.
+	cron := &schedule.Cron{}
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if fires(s, schedules, repo, expression, cron, now.Add(time.Duration(i)*time.Minute)) {
+			t.Fatalf("expected a schedule that never matches not to fire, fired at check %d", i)
+		}
+	}
+}
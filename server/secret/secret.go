@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// keyInfo binds the keys derived from the master key to the encryption of
+// secrets, so that the same master key never yields the same key elsewhere.
+const keyInfo = "ophelia-ci secrets"
+
+var (
+	// ErrNoMasterKey is returned when secrets are used while the server has no
+	// master key configured.
+	ErrNoMasterKey = errors.New("secrets require a master key in the server configuration")
+
+	// namePattern matches the valid secret names, which are environment
+	// variable names.
+	namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// reservedNames are the variables set by the executor for every step.
+	reservedNames = []string{"PATH", "HOME", "CI"}
+)
+
+// Cipher encrypts and decrypts secret values with AES-256-GCM, using a key
+// derived from the master key of the server.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a Cipher whose key is derived from masterKey.
+func NewCipher(masterKey string) (*Cipher, error) {
+	if masterKey == "" {
+		return nil, ErrNoMasterKey
+	}
+	key, err := hkdf.Key(sha256.New, []byte(masterKey), nil, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt encrypts a secret value, returning a random nonce followed by the
+// ciphertext.
+//
+// The ciphertext is bound to scope, such as the repository and name of the
+// secret, and cannot be decrypted with another one. This prevents a stored
+// value from being moved to another secret.
+func (c *Cipher) Encrypt(value []byte, scope string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, value, []byte(scope)), nil
+}
+
+// Decrypt decrypts a value returned by Encrypt for the same scope.
+func (c *Cipher) Decrypt(ciphertext []byte, scope string) ([]byte, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("invalid secret ciphertext")
+	}
+	nonce, sealed := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+	value, err := c.aead.Open(nil, nonce, sealed, []byte(scope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return value, nil
+}
+
+// ValidateName checks that a secret name is a valid environment variable name
+// that does not override the variables set by the executor.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("secret name %q must only contain letters, digits and underscores, and not start with a digit", name)
+	}
+	upper := strings.ToUpper(name)
+	if strings.HasPrefix(upper, "OPHELIA_CI") {
+		return fmt.Errorf("secret name %q must not start with OPHELIA_CI", name)
+	}
+	for _, reserved := range reservedNames {
+		if upper == reserved {
+			return fmt.Errorf("secret name %q is reserved", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	cipher, err := NewCipher("master key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := cipher.Encrypt([]byte("deploy token"), "repo/TOKEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(ciphertext, []byte("deploy token")) {
+		t.Fatal("the value is stored in clear")
+	}
+
+	value, err := cipher.Decrypt(ciphertext, "repo/TOKEN")
+	if err != nil || string(value) != "deploy token" {
+		t.Fatalf("unexpected value %q: %v", value, err)
+	}
+	if _, err := cipher.Decrypt(ciphertext, "other/TOKEN"); err == nil {
+		t.Fatal("expected a value moved to another secret not to decrypt")
+	}
+
+	other, _ := NewCipher("another master key")
+	if _, err := other.Decrypt(ciphertext, "repo/TOKEN"); err == nil {
+		t.Fatal("expected another master key not to decrypt the value")
+	}
+
+	if _, err := NewCipher(""); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey, got %v", err)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"DEPLOY_TOKEN":    true,
+		"_private":        true,
+		"1TOKEN":          false,
+		"DEPLOY-TOKEN":    false,
+		"":                false,
+		"PATH":            false,
+		"ophelia_ci_job":  false,
+		"OPHELIA_CI_JOB2": false,
+	} {
+		if err := ValidateName(name); (err == nil) != valid {
+			t.Errorf("unexpected validation of %q: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/secret"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SetSecret sets a secret of a repository, replacing any previous value.
+//
+// The value is encrypted with a key derived from the master key of the server
+// before it is stored, and is never returned by the API. It is only exposed to
+// the jobs of the repository, as an environment variable named after the
+// secret.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the repository name, the secret name and its
+//     value.
+//
+// Returns:
+//   - *pb.Empty: An empty response.
+//   - error: An error if the secret is invalid or could not be stored.
+func (s *server) SetSecret(ctx context.Context, req *pb.SetSecretRequest) (*pb.Empty, error) {
+	log.Printf("Setting secret %v of repository %v", req.Name, req.Repository)
+	if s.secrets == nil {
+		return nil, status.Error(codes.FailedPrecondition, secret.ErrNoMasterKey.Error())
+	}
+	if err := secret.ValidateName(req.Name); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.Value == "" {
+		return nil, status.Error(codes.InvalidArgument, "secret value is required")
+	}
+	repo, err := s.namedRepository(req.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.secrets.Encrypt([]byte(req.Value), secretScope(repo.Id, req.Name))
+	if err != nil {
+		log.Printf("Error encrypting secret: %v", err)
+		return nil, err
+	}
+	err = s.secretStore.SetSecret(store.Secret{
+		RepositoryId: repo.Id,
+		Name:         req.Name,
+		Value:        value,
+		UpdatedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+// ListSecretNames lists the names of the secrets of a repository, sorted by
+// name. Their values are never returned.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the repository name.
+//
+// Returns:
+//   - *pb.ListSecretNamesResponse: The names of the secrets.
+//   - error: An error if the secrets could not be listed.
+func (s *server) ListSecretNames(ctx context.Context, req *pb.ListSecretNamesRequest) (*pb.ListSecretNamesResponse, error) {
+	log.Printf("Listing secrets of repository %v", req.Repository)
+	repo, err := s.namedRepository(req.Repository)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := s.secretStore.ListSecrets(repo.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.ListSecretNamesResponse{}
+	for _, secret := range secrets {
+		response.Names = append(response.Names, secret.Name)
+	}
+	return response, nil
+}
+
+// DeleteSecret deletes a secret of a repository.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the repository name and the secret name.
+//
+// Returns:
+//   - *pb.Empty: An empty response.
+//   - error: An error if the secret does not exist or could not be deleted.
+func (s *server) DeleteSecret(ctx context.Context, req *pb.DeleteSecretRequest) (*pb.Empty, error) {
+	log.Printf("Deleting secret %v of repository %v", req.Name, req.Repository)
+	repo, err := s.namedRepository(req.Repository)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := s.secretStore.DeleteSecret(repo.Id, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, status.Errorf(codes.NotFound, "secret %s not found in %s", req.Name, repo.Name)
+	}
+	return &pb.Empty{}, nil
+}
+
+// namedRepository gets a repository by name, for the methods managing its
+// secrets or roles.
+func (s *server) namedRepository(name string) (*pb.RepositoryResponse, error) {
+	repo, err := s.repositorieStore.GetRepositoryByName(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "repository %s not found", name)
+	}
+	if err != nil {
+		log.Printf("Error getting repository: %v", err)
+		return nil, err
+	}
+	return repo, nil
+}
+
+// buildSecrets decrypts the secrets of a repository, to be exposed to the jobs
+// of its builds.
+//
+// Returns the values of the secrets by name, or nil if the repository has no
+// secrets.
+func (s *server) buildSecrets(repositoryId string) (map[string]string, error) {
+	secrets, err := s.secretStore.ListSecrets(repositoryId)
+	if err != nil || len(secrets) == 0 {
+		return nil, err
+	}
+	if s.secrets == nil {
+		return nil, secret.ErrNoMasterKey
+	}
+
+	values := make(map[string]string, len(secrets))
+	for _, stored := range secrets {
+		value, err := s.secrets.Decrypt(stored.Value, secretScope(repositoryId, stored.Name))
+		if err != nil {
+			return nil, err
+		}
+		values[stored.Name] = string(value)
+	}
+	return values, nil
+}
+
+// secretScope returns the scope the value of a secret is encrypted for, so
+// that it can only be decrypted as the secret it was set for.
+func secretScope(repositoryId, name string) string {
+	return repositoryId + "/" + name
+}
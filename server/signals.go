@@ -2,24 +2,43 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strings"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/git"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/pipeline"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// CommitSignal is a gRPC service method that sends a signal to the server when a commit is pushed to a repository.
+// CommitSignal is a gRPC service method that sends a signal to the server when a ref of a repository
+// is updated by a push.
+//
+// The pipeline definition file is read from the repository at the pushed commit,
+// parsed and validated, and a build is added to the build queue if the push
+// matches the branch, tag and path filters of the pipeline. Path filters are matched
+// against the files changed since the old revision of the ref. If coalescing is
+// enabled, the queued builds of the same branch are cancelled. Commits without a
+// pipeline definition file only update the repository, while invalid definitions are
+// reported back to the caller. Deleting a branch cancels its queued builds. The
+// old and new commit hashes must be full object ids, as they are passed to Git.
 //
 // Parameters:
 //   - ctx: The context for the request, which carries deadlines, cancellation signals,
 //     and other request-scoped values.
-//   - req: The request containing the repository name and commit hash.
+//   - req: The request containing the repository name, the updated ref with its old
+//     and new commit hashes, whether it was deleted and who pushed it.
 //
 // Returns:
 //   - *pb.Empty: An empty response message indicating the signal was sent successfully.
 //   - error: An error if there is an issue sending the signal.
 func (s *server) CommitSignal(ctx context.Context, req *pb.CommitRequest) (*pb.Empty, error) {
-	// Improve this method in roadmap
 	log.Printf("Commit signal with request: %v", req)
+	if !git.IsObjectId(req.CommitHash) || (req.OldRevision != "" && !git.IsObjectId(req.OldRevision)) {
+		return nil, status.Error(codes.InvalidArgument, "the commit hashes must be full object ids")
+	}
 	repo, err := s.repositorieStore.GetRepositoryByName(req.Repository)
 	if err != nil {
 		log.Printf("Error getting repository: %v", err)
@@ -37,5 +56,108 @@ func (s *server) CommitSignal(ctx context.Context, req *pb.CommitRequest) (*pb.E
 		return nil, err
 	}
 
+	if branch, ok := strings.CutPrefix(req.Ref, "refs/heads/"); ok {
+		req.Branch, req.Tag = branch, ""
+	} else if tag, ok := strings.CutPrefix(req.Ref, "refs/tags/"); ok {
+		req.Branch, req.Tag = "", tag
+	}
+
+	if req.Deleted {
+		log.Printf("Ref %v of %v was deleted", req.Ref, repo.Name)
+		if req.Branch != "" {
+			s.cancelQueuedBuilds(repo, req.Branch)
+		}
+		return &pb.Empty{}, nil
+	}
+	log.Printf("Ref %v of %v was %v at %v", req.Ref, repo.Name, pushKind(getRepoPath(repo.Name), req), req.CommitHash)
+
+	definition, err := pipeline.Load(getRepoPath(repo.Name), req.CommitHash)
+	if errors.Is(err, pipeline.ErrNotFound) {
+		log.Printf("No pipeline definition found for %v at %v", repo.Name, req.CommitHash)
+		return &pb.Empty{}, nil
+	}
+	if err != nil {
+		log.Printf("Error loading pipeline: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pipeline definition: %v", err)
+	}
+	log.Printf("Pipeline %q loaded for %v at %v with %d jobs", definition.Name, repo.Name, req.CommitHash, len(definition.Jobs()))
+
+	triggered, err := definition.On.MatchesPush(pipeline.Push{
+		Branch: req.Branch,
+		Tag:    req.Tag,
+		ChangedFiles: func() ([]string, error) {
+			return git.ChangedFiles(getRepoPath(repo.Name), req.OldRevision, req.CommitHash)
+		},
+	})
+	if err != nil {
+		log.Printf("Error matching push triggers: %v", err)
+		return nil, err
+	}
+	if !triggered {
+		log.Printf("Push of %v at %v does not match the triggers of the pipeline, no build queued", repo.Name, req.CommitHash)
+		return &pb.Empty{}, nil
+	}
+
+	parameters, err := definition.ResolveParameters(nil)
+	if err != nil {
+		log.Printf("Error resolving parameters: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameters: %v", err)
+	}
+
+	build, err := s.enqueueBuild(repo, &pb.BuildResponse{
+		CommitHash:  req.CommitHash,
+		Branch:      req.Branch,
+		Tag:         req.Tag,
+		TriggerUser: triggerUser(ctx, req),
+		Parameters:  parameters,
+	}, definition, s.coalesceBuilds)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Build %v queued for %v at %v", build.Id, repo.Name, req.CommitHash)
+
 	return &pb.Empty{}, nil
 }
+
+// triggerUser returns the user a build queued by a commit signal is credited
+// to. The pusher sent with the signal is only trusted from post-receive hooks,
+// which run after the server authenticated the push, and the authenticated
+// caller is credited otherwise.
+func triggerUser(ctx context.Context, req *pb.CommitRequest) string {
+	if hookFromContext(ctx) != "" {
+		return req.Pusher
+	}
+	return usernameFromContext(ctx)
+}
+
+// pushKind describes how a push updated a ref: "created" when the ref did not
+// exist before, "force-pushed" when its old commit is not an ancestor of the
+// new one, and "updated" otherwise.
+func pushKind(repoPath string, req *pb.CommitRequest) string {
+	if strings.Trim(req.OldRevision, "0") == "" {
+		return "created"
+	}
+	if req.Tag == "" {
+		isAncestor, err := git.IsAncestor(repoPath, req.OldRevision, req.CommitHash)
+		if err == nil && !isAncestor {
+			return "force-pushed"
+		}
+	}
+	return "updated"
+}
+
+// updateHooks replaces the post-receive hook of every repository with the
+// current one, so that repositories created by older versions of the server
+// send complete commit signals.
+func (s *server) updateHooks() {
+	repos, err := s.repositorieStore.ListRepositories()
+	if err != nil {
+		log.Printf("Error listing repositories: %v", err)
+		return
+	}
+	for _, repo := range repos.Repositories {
+		if err := git.UpdatePostReceiveHook(getRepoPath(repo.Name)); err != nil {
+			log.Printf("Error updating hook of %v: %v", repo.Name, err)
+		}
+	}
+}
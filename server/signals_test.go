@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+)
+
+func TestTriggerUserOnlyTrustsPusherFromHooks(t *testing.T) {
+	req := &pb.CommitRequest{Repository: "project", Pusher: "mallory"}
+
+	hook := context.WithValue(context.Background(), hookContextKey{}, "project")
+	if user := triggerUser(hook, req); user != "mallory" {
+		t.Errorf("expected the pusher sent by the hook, got %q", user)
+	}
+
+	user := context.WithValue(context.Background(), usernameContextKey{}, "alice")
+	if user := triggerUser(user, req); user != "alice" {
+		t.Errorf("expected the authenticated user, got %q", user)
+	}
+}
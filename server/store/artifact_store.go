@@ -0,0 +1,223 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Artifact is the record of the artifacts archive of a job.
+type Artifact struct {
+	JobId        string
+	BuildId      string
+	RepositoryId string
+	Job          string
+	Size         int64
+	CreatedAt    time.Time
+}
+
+type ArtifactStore interface {
+	CreateTable() error
+	CreateArtifact(artifact Artifact) error
+	ListArtifacts(buildId string) ([]Artifact, error)
+	ListArtifactsBefore(repositoryId string, before time.Time) ([]Artifact, error)
+	DeleteArtifact(jobId string) error
+	GetRetention(repositoryId string) (int, bool, error)
+	SetRetention(repositoryId string, days int) error
+}
+
+type SQLArtifactStore struct {
+	db *sql.DB
+}
+
+const artifactColumns = "job_id, build_id, repository_id, job, size, created_at"
+
+// NewSQLArtifactStore creates a new SQLArtifactStore given a database connection.
+//
+// If the artifacts and artifact retention tables do not exist in the database,
+// they will be created.
+//
+// The function will log a fatal error if there is an issue creating the tables.
+func NewSQLArtifactStore(db *sql.DB) *SQLArtifactStore {
+	store := &SQLArtifactStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create artifacts tables: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the artifacts and artifact retention tables in the SQLite database if they do not exist.
+//
+// The artifacts table has the following columns:
+// - job_id: the ID of the job that produced the artifacts, which is the primary key
+// - build_id: the ID of the build of the job
+// - repository_id: the ID of the built repository
+// - job: the name of the job
+// - size: the size of the artifacts archive in bytes
+// - created_at: the timestamp when the artifacts were stored
+//
+// The artifact_retention table has the following columns:
+// - repository_id: the ID of the repository, which is the primary key
+// - days: the number of days the artifacts of the repository are kept
+//
+// Returns an error if there is an issue creating the tables.
+func (s *SQLArtifactStore) CreateTable() error {
+	log.Println("Creating artifacts table...")
+	query := `
+        CREATE TABLE IF NOT EXISTS artifacts (
+            job_id TEXT PRIMARY KEY,
+            build_id TEXT NOT NULL,
+            repository_id TEXT NOT NULL,
+            job TEXT NOT NULL,
+            size INTEGER NOT NULL,
+            created_at INTEGER NOT NULL
+        );
+    `
+	_, err := s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating artifacts table:", err)
+		return err
+	}
+
+	log.Println("Creating artifact retention table...")
+	query = `
+        CREATE TABLE IF NOT EXISTS artifact_retention (
+            repository_id TEXT PRIMARY KEY,
+            days INTEGER NOT NULL
+        );
+    `
+	_, err = s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating artifact retention table:", err)
+		return err
+	}
+	return nil
+}
+
+// CreateArtifact records the artifacts archive of a job, replacing any previous
+// record of the job.
+//
+// Parameters:
+// - artifact: The artifacts record.
+//
+// Returns an error if there is an issue recording the artifacts.
+func (s *SQLArtifactStore) CreateArtifact(artifact Artifact) error {
+	query := "INSERT OR REPLACE INTO artifacts (" + artifactColumns + ") VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := s.db.Exec(query, artifact.JobId, artifact.BuildId, artifact.RepositoryId, artifact.Job, artifact.Size, artifact.CreatedAt.Unix())
+	if err != nil {
+		log.Println("Error inserting artifact:", err)
+	}
+	return err
+}
+
+// ListArtifacts lists the artifacts of a build, in the order they were stored.
+//
+// Parameters:
+// - buildId: The ID of the build whose artifacts are listed.
+//
+// Returns:
+// - []Artifact: The artifacts of the build.
+// - error: An error if there is an issue listing artifacts.
+func (s *SQLArtifactStore) ListArtifacts(buildId string) ([]Artifact, error) {
+	return s.listArtifacts("SELECT "+artifactColumns+" FROM artifacts WHERE build_id = ? ORDER BY created_at, rowid", buildId)
+}
+
+// ListArtifactsBefore lists the artifacts of a repository stored before the
+// given time.
+//
+// Parameters:
+// - repositoryId: The ID of the repository whose artifacts are listed.
+// - before: The time the artifacts were stored before.
+//
+// Returns:
+// - []Artifact: The artifacts of the repository.
+// - error: An error if there is an issue listing artifacts.
+func (s *SQLArtifactStore) ListArtifactsBefore(repositoryId string, before time.Time) ([]Artifact, error) {
+	query := "SELECT " + artifactColumns + " FROM artifacts WHERE repository_id = ? AND created_at < ? ORDER BY created_at, rowid"
+	return s.listArtifacts(query, repositoryId, before.Unix())
+}
+
+// listArtifacts runs a query selecting artifactColumns.
+func (s *SQLArtifactStore) listArtifacts(query string, args ...any) ([]Artifact, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error listing artifacts:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var artifacts []Artifact
+	for rows.Next() {
+		var artifact Artifact
+		var createdAt int64
+		err := rows.Scan(&artifact.JobId, &artifact.BuildId, &artifact.RepositoryId, &artifact.Job, &artifact.Size, &createdAt)
+		if err != nil {
+			log.Println("Error scanning artifact:", err)
+			return nil, err
+		}
+		artifact.CreatedAt = time.Unix(createdAt, 0)
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, rows.Err()
+}
+
+// DeleteArtifact deletes the artifacts record of a job.
+//
+// Parameters:
+// - jobId: The ID of the job whose artifacts record is deleted.
+//
+// Returns an error if there is an issue deleting the record.
+func (s *SQLArtifactStore) DeleteArtifact(jobId string) error {
+	_, err := s.db.Exec("DELETE FROM artifacts WHERE job_id = ?", jobId)
+	if err != nil {
+		log.Println("Error deleting artifact:", err)
+	}
+	return err
+}
+
+// GetRetention gets the number of days the artifacts of a repository are kept.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+//
+// Returns:
+// - int: The number of days the artifacts are kept.
+// - bool: Whether the repository has its own retention policy.
+// - error: An error if there is an issue retrieving the policy.
+func (s *SQLArtifactStore) GetRetention(repositoryId string) (int, bool, error) {
+	var days int
+	err := s.db.QueryRow("SELECT days FROM artifact_retention WHERE repository_id = ?", repositoryId).Scan(&days)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		log.Println("Error getting artifact retention:", err)
+		return 0, false, err
+	}
+	return days, true, nil
+}
+
+// SetRetention sets the number of days the artifacts of a repository are kept.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+// - days: The number of days the artifacts are kept.
+//
+// Returns an error if there is an issue recording the policy.
+func (s *SQLArtifactStore) SetRetention(repositoryId string, days int) error {
+	query := `
+        INSERT INTO artifact_retention (repository_id, days) VALUES (?, ?)
+        ON CONFLICT (repository_id) DO UPDATE SET days = excluded.days
+    `
+	_, err := s.db.Exec(query, repositoryId, days)
+	if err != nil {
+		log.Println("Error recording artifact retention:", err)
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// ErrBootstrapped is returned by CompleteBootstrap when the bootstrap of the
+// server has already been completed.
+var ErrBootstrapped = errors.New("the server has already been bootstrapped")
+
+type BootstrapStore interface {
+	CreateTable() error
+	Bootstrapped() (bool, error)
+	CompleteBootstrap(username string) error
+	ResetBootstrap() error
+}
+
+type SQLBootstrapStore struct {
+	db *sql.DB
+}
+
+// NewSQLBootstrapStore creates a new SQLBootstrapStore given a database
+// connection.
+//
+// If the bootstrap table does not exist in the database, it will be created.
+//
+// The function will log a fatal error if there is an issue creating the table.
+func NewSQLBootstrapStore(db *sql.DB) *SQLBootstrapStore {
+	store := &SQLBootstrapStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create bootstrap table: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the bootstrap table in the SQLite database if it does
+// not exist.
+//
+// The table holds at most one row, recording that the first administrator of
+// the server was created, with the following columns:
+// - id: always 1, which is the primary key
+// - username: the username of the first administrator
+// - completed_at: the timestamp when the bootstrap was completed
+//
+// When the table is created for a server that already has users, the bootstrap
+// is recorded as completed, as those users were created with the unique key.
+//
+// Returns an error if there is an issue creating the table.
+func (s *SQLBootstrapStore) CreateTable() error {
+	log.Println("Creating bootstrap table...")
+	var existing int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'bootstrap'").Scan(&existing)
+	if err != nil {
+		log.Println("Error checking bootstrap table:", err)
+		return err
+	}
+
+	query := `
+        CREATE TABLE IF NOT EXISTS bootstrap (
+            id INTEGER PRIMARY KEY CHECK (id = 1),
+            username TEXT NOT NULL,
+            completed_at INTEGER NOT NULL
+        )
+    `
+	if _, err := s.db.Exec(query); err != nil {
+		log.Println("Error creating bootstrap table:", err)
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var users int
+	err = s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'").Scan(&users)
+	if err != nil || users == 0 {
+		return err
+	}
+	_, err = s.db.Exec("INSERT INTO bootstrap (id, username, completed_at) SELECT 1, '', ? WHERE EXISTS (SELECT 1 FROM users)", time.Now().Unix())
+	if err != nil {
+		log.Println("Error recording the bootstrap of existing users:", err)
+	}
+	return err
+}
+
+// Bootstrapped reports whether the first administrator of the server was
+// created.
+//
+// Returns:
+// - bool: True if the bootstrap is completed.
+// - error: An error if there is an issue reading the bootstrap.
+func (s *SQLBootstrapStore) Bootstrapped() (bool, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM bootstrap").Scan(&count)
+	if err != nil {
+		log.Println("Error reading bootstrap:", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CompleteBootstrap records that the first administrator of the server was
+// created, so that the unique key login is disabled from then on.
+//
+// Parameters:
+// - username: The username of the administrator.
+//
+// Returns:
+// - error: ErrBootstrapped if the bootstrap was already completed, or an error
+// if there is an issue recording it.
+func (s *SQLBootstrapStore) CompleteBootstrap(username string) error {
+	result, err := s.db.Exec("INSERT INTO bootstrap (id, username, completed_at) VALUES (1, ?, ?) ON CONFLICT (id) DO NOTHING", username, time.Now().Unix())
+	if err != nil {
+		log.Println("Error recording bootstrap:", err)
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrBootstrapped
+	}
+	return nil
+}
+
+// ResetBootstrap forgets that the bootstrap was completed, so that the server
+// issues a new unique key when it next starts.
+//
+// Returns an error if there is an issue resetting the bootstrap.
+func (s *SQLBootstrapStore) ResetBootstrap() error {
+	_, err := s.db.Exec("DELETE FROM bootstrap")
+	if err != nil {
+		log.Println("Error resetting bootstrap:", err)
+	}
+	return err
+}
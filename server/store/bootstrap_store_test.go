@@ -0,0 +1,76 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+)
+
+// openDB opens a new SQLite database for a test, which is closed when the test
+// ends.
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "ophelia.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBootstrapStoreCompletesOnce(t *testing.T) {
+	bootstrapStore := NewSQLBootstrapStore(openDB(t))
+
+	if bootstrapped, err := bootstrapStore.Bootstrapped(); err != nil || bootstrapped {
+		t.Fatalf("expected a new server not to be bootstrapped, got %v (%v)", bootstrapped, err)
+	}
+	if err := bootstrapStore.CompleteBootstrap("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if bootstrapped, err := bootstrapStore.Bootstrapped(); err != nil || !bootstrapped {
+		t.Fatalf("expected the server to be bootstrapped, got %v (%v)", bootstrapped, err)
+	}
+	if err := bootstrapStore.CompleteBootstrap("mallory"); !errors.Is(err, ErrBootstrapped) {
+		t.Fatalf("expected ErrBootstrapped for a second bootstrap, got %v", err)
+	}
+
+	if err := bootstrapStore.ResetBootstrap(); err != nil {
+		t.Fatal(err)
+	}
+	if bootstrapped, err := bootstrapStore.Bootstrapped(); err != nil || bootstrapped {
+		t.Fatalf("expected the reset server not to be bootstrapped, got %v (%v)", bootstrapped, err)
+	}
+	if err := bootstrapStore.CompleteBootstrap("bob"); err != nil {
+		t.Fatalf("expected the reset server to be bootstrapped again, got %v", err)
+	}
+}
+
+func TestBootstrapStoreRecordsExistingUsers(t *testing.T) {
+	tests := []struct {
+		name     string
+		users    []string
+		expected bool
+	}{
+		{name: "Without users", expected: false},
+		{name: "With users", users: []string{"alice"}, expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := openDB(t)
+			userStore := NewSQLUserStore(db)
+			for _, username := range test.users {
+				if _, err := userStore.CreateUser(&pb.CreateUserRequest{Username: username}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			bootstrapStore := NewSQLBootstrapStore(db)
+			if bootstrapped, err := bootstrapStore.Bootstrapped(); err != nil || bootstrapped != test.expected {
+				t.Errorf("expected bootstrapped to be %v, got %v (%v)", test.expected, bootstrapped, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,553 @@
+package store
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type BuildStore interface {
+	CreateTable() error
+	CreateBuild(build *pb.BuildResponse) (*pb.BuildResponse, error)
+	GetBuild(id string) (*pb.BuildResponse, error)
+	ListBuilds(repositoryId string) (*pb.ListBuildsResponse, error)
+	ListBuildsByStatus(status pb.BuildStatus) (*pb.ListBuildsResponse, error)
+	UpdateBuildStatus(id string, status pb.BuildStatus) (bool, error)
+	ClaimBuild(id string) (bool, error)
+	CreateJob(job *pb.JobResponse) (*pb.JobResponse, error)
+	ListJobs(buildId string) ([]*pb.JobResponse, error)
+	ListParameters(buildId string) (map[string]string, error)
+	UpdateJobStatus(id string, status pb.BuildStatus, exitCode int32) error
+}
+
+type SQLBuildStore struct {
+	db *sql.DB
+}
+
+const (
+	buildColumns = "id, repository_id, commit_hash, branch, tag, trigger_user, status, created_at, started_at, finished_at"
+	jobColumns   = "id, build_id, stage, name, status, exit_code, started_at, finished_at"
+)
+
+// NewSQLBuildStore creates a new SQLBuildStore given a database connection.
+//
+// If the builds and jobs tables do not exist in the database, they will be created.
+//
+// The function will log a fatal error if there is an issue creating the tables.
+func NewSQLBuildStore(db *sql.DB) *SQLBuildStore {
+	store := &SQLBuildStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create builds tables: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the builds, jobs, job matrix values and build parameters tables in the SQLite database if they do not exist.
+//
+// The builds table has the following columns:
+// - id: the ID of the build, which is the primary key
+// - repository_id: the ID of the built repository
+// - commit_hash: the hash of the built commit
+// - branch: the branch the commit was pushed to
+// - tag: the tag pointing to the commit, if any
+// - trigger_user: the user who triggered the build
+// - status: the status of the build
+// - created_at: the timestamp when the build was queued
+// - started_at: the timestamp when the build started running
+// - finished_at: the timestamp when the build finished
+//
+// The jobs table has the following columns:
+// - id: the ID of the job, which is the primary key
+// - build_id: the ID of the build the job belongs to
+// - stage: the name of the pipeline stage of the job
+// - name: the name of the job
+// - status: the status of the job
+// - exit_code: the exit code of the last step run by the job
+// - started_at: the timestamp when the job started running
+// - finished_at: the timestamp when the job finished
+//
+// The job_matrix table has the following columns:
+// - job_id: the ID of the job expanded from a matrix
+// - name: the name of the matrix axis
+// - value: the value of the axis for the job
+//
+// The build_parameters table has the following columns:
+// - build_id: the ID of the build the parameter belongs to
+// - name: the name of the parameter
+// - value: the value of the parameter
+//
+// Returns an error if there is an issue creating the tables.
+func (s *SQLBuildStore) CreateTable() error {
+	log.Println("Creating builds table...")
+	query := `
+        CREATE TABLE IF NOT EXISTS builds (
+            id TEXT PRIMARY KEY,
+            repository_id TEXT NOT NULL,
+            commit_hash TEXT NOT NULL,
+            branch TEXT,
+            tag TEXT,
+            trigger_user TEXT,
+            status INTEGER NOT NULL,
+            created_at INTEGER,
+            started_at INTEGER,
+            finished_at INTEGER
+        );
+    `
+	_, err := s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating builds table:", err)
+		return err
+	}
+
+	log.Println("Creating jobs table...")
+	query = `
+        CREATE TABLE IF NOT EXISTS jobs (
+            id TEXT PRIMARY KEY,
+            build_id TEXT NOT NULL,
+            stage TEXT,
+            name TEXT NOT NULL,
+            status INTEGER NOT NULL,
+            exit_code INTEGER,
+            started_at INTEGER,
+            finished_at INTEGER
+        );
+    `
+	_, err = s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating jobs table:", err)
+		return err
+	}
+
+	log.Println("Creating job matrix table...")
+	query = `
+        CREATE TABLE IF NOT EXISTS job_matrix (
+            job_id TEXT NOT NULL,
+            name TEXT NOT NULL,
+            value TEXT NOT NULL,
+            PRIMARY KEY (job_id, name)
+        );
+    `
+	_, err = s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating job matrix table:", err)
+		return err
+	}
+
+	log.Println("Creating build parameters table...")
+	query = `
+        CREATE TABLE IF NOT EXISTS build_parameters (
+            build_id TEXT NOT NULL,
+            name TEXT NOT NULL,
+            value TEXT NOT NULL,
+            PRIMARY KEY (build_id, name)
+        );
+    `
+	_, err = s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating build parameters table:", err)
+		return err
+	}
+	return nil
+}
+
+// CreateBuild inserts a new queued build into the database.
+//
+// The ID, status and creation timestamp are set by the store.
+//
+// Parameters:
+// - build: The build to be created.
+//
+// Returns:
+// - *pb.BuildResponse: The created build.
+// - error: An error if there is an issue creating the build.
+func (s *SQLBuildStore) CreateBuild(build *pb.BuildResponse) (*pb.BuildResponse, error) {
+	id := uuid.New().String()
+	now := timestamppb.Now()
+	query := "INSERT INTO builds (" + buildColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, 0, 0)"
+	_, err := s.db.Exec(query, id, build.RepositoryId, build.CommitHash, build.Branch, build.Tag, build.TriggerUser, pb.BuildStatus_QUEUED, now.Seconds)
+	log.Printf("Inserting build %v of commit %v into database...\n", id, build.CommitHash)
+	if err != nil {
+		log.Println("Error inserting build:", err)
+		return nil, err
+	}
+	for name, value := range build.Parameters {
+		_, err := s.db.Exec("INSERT INTO build_parameters (build_id, name, value) VALUES (?, ?, ?)", id, name, value)
+		if err != nil {
+			log.Println("Error inserting build parameter:", err)
+			return nil, err
+		}
+	}
+	return &pb.BuildResponse{
+		Id:           id,
+		RepositoryId: build.RepositoryId,
+		CommitHash:   build.CommitHash,
+		Branch:       build.Branch,
+		Tag:          build.Tag,
+		TriggerUser:  build.TriggerUser,
+		Status:       pb.BuildStatus_QUEUED,
+		CreatedAt:    now,
+		Parameters:   build.Parameters,
+	}, nil
+}
+
+// GetBuild gets a build by ID, along with its jobs and parameters.
+//
+// Parameters:
+// - id: The ID of the build to retrieve.
+//
+// Returns:
+// - *pb.BuildResponse: The build information.
+// - error: An error if there is an issue retrieving the build.
+func (s *SQLBuildStore) GetBuild(id string) (*pb.BuildResponse, error) {
+	query := "SELECT " + buildColumns + " FROM builds WHERE id = ?"
+	log.Printf("Getting build with id %v from database...\n", id)
+	build, err := scanBuild(s.db.QueryRow(query, id))
+	if err != nil {
+		log.Println("Error getting build:", err)
+		return nil, err
+	}
+	build.Jobs, err = s.ListJobs(id)
+	if err != nil {
+		return nil, err
+	}
+	build.Parameters, err = s.ListParameters(id)
+	if err != nil {
+		return nil, err
+	}
+	return build, nil
+}
+
+// ListBuilds lists the builds of a repository, newest first.
+//
+// If repositoryId is empty, the builds of every repository are listed.
+//
+// Parameters:
+// - repositoryId: The ID of the repository whose builds are listed.
+//
+// Returns:
+// - *pb.ListBuildsResponse: The list of builds, without their jobs.
+// - error: An error if there is an issue listing builds.
+func (s *SQLBuildStore) ListBuilds(repositoryId string) (*pb.ListBuildsResponse, error) {
+	query := "SELECT " + buildColumns + " FROM builds WHERE ? = '' OR repository_id = ? ORDER BY created_at DESC, rowid DESC"
+	log.Println("Getting builds from database...")
+	rows, err := s.db.Query(query, repositoryId, repositoryId)
+	if err != nil {
+		log.Println("Error listing builds:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	builds := &pb.ListBuildsResponse{}
+	for rows.Next() {
+		build, err := scanBuild(rows)
+		if err != nil {
+			log.Println("Error scanning build:", err)
+			return nil, err
+		}
+		builds.Builds = append(builds.Builds, build)
+	}
+	return builds, rows.Err()
+}
+
+// ListBuildsByStatus lists the builds of every repository with the given
+// status, oldest first.
+//
+// Parameters:
+// - status: The status of the builds to list.
+//
+// Returns:
+// - *pb.ListBuildsResponse: The list of builds, without their jobs.
+// - error: An error if there is an issue listing builds.
+func (s *SQLBuildStore) ListBuildsByStatus(status pb.BuildStatus) (*pb.ListBuildsResponse, error) {
+	query := "SELECT " + buildColumns + " FROM builds WHERE status = ? ORDER BY created_at, rowid"
+	rows, err := s.db.Query(query, status)
+	if err != nil {
+		log.Println("Error listing builds:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	builds := &pb.ListBuildsResponse{}
+	for rows.Next() {
+		build, err := scanBuild(rows)
+		if err != nil {
+			log.Println("Error scanning build:", err)
+			return nil, err
+		}
+		builds.Builds = append(builds.Builds, build)
+	}
+	return builds, rows.Err()
+}
+
+// ClaimBuild marks a queued build as running.
+//
+// The update only happens if the build is still queued, so that a build
+// cancelled while waiting in the queue is never started.
+//
+// Parameters:
+// - id: The ID of the build to claim.
+//
+// Returns:
+// - bool: Whether the build was queued and is now running.
+// - error: An error if there is an issue updating the build.
+func (s *SQLBuildStore) ClaimBuild(id string) (bool, error) {
+	query := "UPDATE builds SET status = ?, started_at = ? WHERE id = ? AND status = ?"
+	result, err := s.db.Exec(query, pb.BuildStatus_RUNNING, time.Now().Unix(), id, pb.BuildStatus_QUEUED)
+	if err != nil {
+		log.Println("Error claiming build:", err)
+		return false, err
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+// UpdateBuildStatus sets the status of a build.
+//
+// The start timestamp is recorded when the build starts running, and the finish
+// timestamp when it reaches a final status. A build that already reached a
+// final status keeps it, so that a cancelled build is not reported as finished
+// by its last job.
+//
+// Parameters:
+// - id: The ID of the build to update.
+// - status: The new status of the build.
+//
+// Returns:
+// - bool: Whether the build was not finished yet and was updated.
+// - error: An error if there is an issue updating the build.
+func (s *SQLBuildStore) UpdateBuildStatus(id string, status pb.BuildStatus) (bool, error) {
+	query, args := statusUpdate("builds", id, status, nil)
+	result, err := s.db.Exec(query, args...)
+	log.Printf("Updating build %v to status %v in database...\n", id, status)
+	if err != nil {
+		log.Println("Error updating build:", err)
+		return false, err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated == 1, nil
+}
+
+// CreateJob inserts a new queued job of a build into the database.
+//
+// Parameters:
+// - job: The job to be created, with its build ID, stage, name and matrix values.
+//
+// Returns:
+// - *pb.JobResponse: The created job.
+// - error: An error if there is an issue creating the job.
+func (s *SQLBuildStore) CreateJob(job *pb.JobResponse) (*pb.JobResponse, error) {
+	id := uuid.New().String()
+	query := "INSERT INTO jobs (" + jobColumns + ") VALUES (?, ?, ?, ?, ?, 0, 0, 0)"
+	_, err := s.db.Exec(query, id, job.BuildId, job.Stage, job.Name, pb.BuildStatus_QUEUED)
+	log.Printf("Inserting job %v of build %v into database...\n", job.Name, job.BuildId)
+	if err != nil {
+		log.Println("Error inserting job:", err)
+		return nil, err
+	}
+	for name, value := range job.Matrix {
+		_, err := s.db.Exec("INSERT INTO job_matrix (job_id, name, value) VALUES (?, ?, ?)", id, name, value)
+		if err != nil {
+			log.Println("Error inserting job matrix value:", err)
+			return nil, err
+		}
+	}
+	return &pb.JobResponse{
+		Id:      id,
+		BuildId: job.BuildId,
+		Stage:   job.Stage,
+		Name:    job.Name,
+		Status:  pb.BuildStatus_QUEUED,
+		Matrix:  job.Matrix,
+	}, nil
+}
+
+// ListJobs lists the jobs of a build in the order they were created, along with
+// the matrix values of the jobs expanded from a matrix.
+//
+// Parameters:
+// - buildId: The ID of the build whose jobs are listed.
+//
+// Returns:
+// - []*pb.JobResponse: The jobs of the build.
+// - error: An error if there is an issue listing jobs.
+func (s *SQLBuildStore) ListJobs(buildId string) ([]*pb.JobResponse, error) {
+	query := "SELECT " + jobColumns + " FROM jobs WHERE build_id = ? ORDER BY rowid"
+	rows, err := s.db.Query(query, buildId)
+	if err != nil {
+		log.Println("Error listing jobs:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*pb.JobResponse
+	for rows.Next() {
+		var job pb.JobResponse
+		var startedAt, finishedAt int64
+		err := rows.Scan(&job.Id, &job.BuildId, &job.Stage, &job.Name, &job.Status, &job.ExitCode, &startedAt, &finishedAt)
+		if err != nil {
+			log.Println("Error scanning job:", err)
+			return nil, err
+		}
+		job.StartedAt = optionalTimestamp(startedAt)
+		job.FinishedAt = optionalTimestamp(finishedAt)
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.loadMatrices(buildId, jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// loadMatrices sets the matrix values of the jobs of a build.
+func (s *SQLBuildStore) loadMatrices(buildId string, jobs []*pb.JobResponse) error {
+	query := "SELECT m.job_id, m.name, m.value FROM job_matrix m JOIN jobs j ON j.id = m.job_id WHERE j.build_id = ?"
+	rows, err := s.db.Query(query, buildId)
+	if err != nil {
+		log.Println("Error listing job matrix values:", err)
+		return err
+	}
+	defer rows.Close()
+
+	byId := make(map[string]*pb.JobResponse, len(jobs))
+	for _, job := range jobs {
+		byId[job.Id] = job
+	}
+	for rows.Next() {
+		var jobId, name, value string
+		if err := rows.Scan(&jobId, &name, &value); err != nil {
+			log.Println("Error scanning job matrix value:", err)
+			return err
+		}
+		if job, ok := byId[jobId]; ok {
+			if job.Matrix == nil {
+				job.Matrix = make(map[string]string)
+			}
+			job.Matrix[name] = value
+		}
+	}
+	return rows.Err()
+}
+
+// ListParameters lists the parameters a build was triggered with.
+//
+// Parameters:
+// - buildId: The ID of the build whose parameters are listed.
+//
+// Returns:
+// - map[string]string: The value of each parameter of the build.
+// - error: An error if there is an issue listing parameters.
+func (s *SQLBuildStore) ListParameters(buildId string) (map[string]string, error) {
+	rows, err := s.db.Query("SELECT name, value FROM build_parameters WHERE build_id = ?", buildId)
+	if err != nil {
+		log.Println("Error listing build parameters:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	parameters := make(map[string]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			log.Println("Error scanning build parameter:", err)
+			return nil, err
+		}
+		parameters[name] = value
+	}
+	return parameters, rows.Err()
+}
+
+// UpdateJobStatus sets the status and exit code of a job.
+//
+// The start timestamp is recorded when the job starts running, and the finish
+// timestamp when it reaches a final status. A job that already reached a final
+// status keeps it.
+//
+// Parameters:
+// - id: The ID of the job to update.
+// - status: The new status of the job.
+// - exitCode: The exit code of the last step run by the job.
+//
+// Returns:
+// - error: An error if there is an issue updating the job.
+func (s *SQLBuildStore) UpdateJobStatus(id string, status pb.BuildStatus, exitCode int32) error {
+	query, args := statusUpdate("jobs", id, status, &exitCode)
+	_, err := s.db.Exec(query, args...)
+	if err != nil {
+		log.Println("Error updating job:", err)
+		return err
+	}
+	return nil
+}
+
+// statusUpdate builds the query updating the status of a build or job row that
+// has not reached a final status yet, along with the timestamp matching the new
+// status.
+func statusUpdate(table, id string, status pb.BuildStatus, exitCode *int32) (string, []any) {
+	query := "UPDATE " + table + " SET status = ?"
+	args := []any{status}
+	if exitCode != nil {
+		query += ", exit_code = ?"
+		args = append(args, *exitCode)
+	}
+	switch {
+	case status == pb.BuildStatus_RUNNING:
+		query += ", started_at = ?"
+		args = append(args, time.Now().Unix())
+	case IsFinalStatus(status):
+		query += ", finished_at = ?"
+		args = append(args, time.Now().Unix())
+	}
+	query += " WHERE id = ? AND status IN (?, ?)"
+	return query, append(args, id, pb.BuildStatus_QUEUED, pb.BuildStatus_RUNNING)
+}
+
+// IsFinalStatus reports whether a build or job with the given status is finished.
+func IsFinalStatus(status pb.BuildStatus) bool {
+	return status != pb.BuildStatus_QUEUED && status != pb.BuildStatus_RUNNING
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanBuild reads a build row selected with buildColumns.
+func scanBuild(row scanner) (*pb.BuildResponse, error) {
+	var build pb.BuildResponse
+	var createdAt, startedAt, finishedAt int64
+	err := row.Scan(&build.Id, &build.RepositoryId, &build.CommitHash, &build.Branch, &build.Tag, &build.TriggerUser, &build.Status, &createdAt, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+	build.CreatedAt = timestamppb.New(time.Unix(createdAt, 0))
+	build.StartedAt = optionalTimestamp(startedAt)
+	build.FinishedAt = optionalTimestamp(finishedAt)
+	return &build, nil
+}
+
+// optionalTimestamp converts a unix timestamp column to a protobuf timestamp,
+// where zero means that the timestamp is not set.
+func optionalTimestamp(seconds int64) *timestamppb.Timestamp {
+	if seconds == 0 {
+		return nil
+	}
+	return timestamppb.New(time.Unix(seconds, 0))
+}
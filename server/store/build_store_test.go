@@ -0,0 +1,207 @@
+package store
+
+import (
+	"maps"
+	"testing"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+)
+
+func TestBuildStoreCreatesAndGetsBuilds(t *testing.T) {
+	buildStore := NewSQLBuildStore(openDB(t))
+	parameters := map[string]string{"target": "production"}
+
+	build, err := buildStore.CreateBuild(&pb.BuildResponse{
+		RepositoryId: "repository",
+		CommitHash:   "abc123",
+		Branch:       "main",
+		Tag:          "v1.0.0",
+		TriggerUser:  "alice",
+		Parameters:   parameters,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if build.Id == "" || build.Status != pb.BuildStatus_QUEUED || build.CreatedAt == nil {
+		t.Fatalf("expected a new queued build, got %v", build)
+	}
+
+	stored, err := buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.RepositoryId != "repository" || stored.CommitHash != "abc123" || stored.Branch != "main" || stored.Tag != "v1.0.0" || stored.TriggerUser != "alice" {
+		t.Errorf("unexpected build %v", stored)
+	}
+	if stored.Status != pb.BuildStatus_QUEUED || stored.StartedAt != nil || stored.FinishedAt != nil {
+		t.Errorf("expected the build to be queued, got %v", stored)
+	}
+	if !maps.Equal(stored.Parameters, parameters) {
+		t.Errorf("expected the parameters %v, got %v", parameters, stored.Parameters)
+	}
+	if len(stored.Jobs) != 0 {
+		t.Errorf("expected no jobs, got %v", stored.Jobs)
+	}
+
+	if _, err := buildStore.GetBuild("missing"); err == nil {
+		t.Error("expected an error getting a missing build")
+	}
+}
+
+func TestBuildStoreListsBuilds(t *testing.T) {
+	buildStore := NewSQLBuildStore(openDB(t))
+	ids := map[string][]string{}
+	for _, repositoryId := range []string{"first", "second", "first"} {
+		build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: repositoryId, CommitHash: "abc123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[repositoryId] = append(ids[repositoryId], build.Id)
+	}
+
+	builds, err := buildStore.ListBuilds("first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builds.Builds) != 2 || builds.Builds[0].Id != ids["first"][1] || builds.Builds[1].Id != ids["first"][0] {
+		t.Errorf("expected the builds of first newest first, got %v", builds.Builds)
+	}
+	if builds, err := buildStore.ListBuilds(""); err != nil || len(builds.Builds) != 3 {
+		t.Errorf("expected the builds of every repository, got %v (%v)", builds, err)
+	}
+
+	if _, err := buildStore.UpdateBuildStatus(ids["second"][0], pb.BuildStatus_SUCCESS); err != nil {
+		t.Fatal(err)
+	}
+	queued, err := buildStore.ListBuildsByStatus(pb.BuildStatus_QUEUED)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queued.Builds) != 2 || queued.Builds[0].Id != ids["first"][0] || queued.Builds[1].Id != ids["first"][1] {
+		t.Errorf("expected the queued builds oldest first, got %v", queued.Builds)
+	}
+}
+
+func TestBuildStoreUpdatesBuildStatus(t *testing.T) {
+	buildStore := NewSQLBuildStore(openDB(t))
+	build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: "repository", CommitHash: "abc123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claimed, err := buildStore.ClaimBuild(build.Id); err != nil || !claimed {
+		t.Fatalf("expected the queued build to be claimed, got %v (%v)", claimed, err)
+	}
+	if claimed, err := buildStore.ClaimBuild(build.Id); err != nil || claimed {
+		t.Fatalf("expected a running build not to be claimed again, got %v (%v)", claimed, err)
+	}
+	stored, err := buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != pb.BuildStatus_RUNNING || stored.StartedAt == nil || stored.FinishedAt != nil {
+		t.Errorf("expected the build to be running, got %v", stored)
+	}
+
+	if updated, err := buildStore.UpdateBuildStatus(build.Id, pb.BuildStatus_FAILED); err != nil || !updated {
+		t.Fatalf("expected the running build to fail, got %v (%v)", updated, err)
+	}
+	stored, err = buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != pb.BuildStatus_FAILED || stored.FinishedAt == nil {
+		t.Errorf("expected the build to have failed, got %v", stored)
+	}
+}
+
+func TestBuildStoreKeepsFinalStatuses(t *testing.T) {
+	buildStore := NewSQLBuildStore(openDB(t))
+	build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: "repository", CommitHash: "abc123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := buildStore.CreateJob(&pb.JobResponse{BuildId: build.Id, Stage: "test", Name: "unit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated, err := buildStore.UpdateBuildStatus(build.Id, pb.BuildStatus_CANCELLED); err != nil || !updated {
+		t.Fatalf("expected the queued build to be cancelled, got %v (%v)", updated, err)
+	}
+	for _, status := range []pb.BuildStatus{pb.BuildStatus_SUCCESS, pb.BuildStatus_RUNNING, pb.BuildStatus_QUEUED} {
+		if updated, err := buildStore.UpdateBuildStatus(build.Id, status); err != nil || updated {
+			t.Errorf("expected the cancelled build not to become %v, got %v (%v)", status, updated, err)
+		}
+	}
+	if claimed, err := buildStore.ClaimBuild(build.Id); err != nil || claimed {
+		t.Errorf("expected the cancelled build not to be claimed, got %v (%v)", claimed, err)
+	}
+
+	if err := buildStore.UpdateJobStatus(job.Id, pb.BuildStatus_CANCELLED, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := buildStore.UpdateJobStatus(job.Id, pb.BuildStatus_SUCCESS, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != pb.BuildStatus_CANCELLED || stored.Jobs[0].Status != pb.BuildStatus_CANCELLED {
+		t.Errorf("expected the build and its job to stay cancelled, got %v", stored)
+	}
+}
+
+func TestBuildStoreCreatesJobs(t *testing.T) {
+	buildStore := NewSQLBuildStore(openDB(t))
+	build, err := buildStore.CreateBuild(&pb.BuildResponse{RepositoryId: "repository", CommitHash: "abc123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matrix := map[string]string{"go": "1.24", "os": "linux"}
+	lint, err := buildStore.CreateJob(&pb.JobResponse{BuildId: build.Id, Stage: "test", Name: "lint"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit, err := buildStore.CreateJob(&pb.JobResponse{BuildId: build.Id, Stage: "test", Name: "unit", Matrix: matrix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lint.Id == "" || lint.Status != pb.BuildStatus_QUEUED {
+		t.Fatalf("expected a new queued job, got %v", lint)
+	}
+
+	if err := buildStore.UpdateJobStatus(lint.Id, pb.BuildStatus_RUNNING, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := buildStore.UpdateJobStatus(unit.Id, pb.BuildStatus_FAILED, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := buildStore.ListJobs(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0].Id != lint.Id || jobs[1].Id != unit.Id {
+		t.Fatalf("expected the jobs in the order they were created, got %v", jobs)
+	}
+	if jobs[0].Status != pb.BuildStatus_RUNNING || jobs[0].StartedAt == nil || jobs[0].FinishedAt != nil || jobs[0].Matrix != nil {
+		t.Errorf("expected the lint job to be running, got %v", jobs[0])
+	}
+	if jobs[1].Status != pb.BuildStatus_FAILED || jobs[1].ExitCode != 2 || jobs[1].FinishedAt == nil || !maps.Equal(jobs[1].Matrix, matrix) {
+		t.Errorf("expected the unit job to have failed with its matrix, got %v", jobs[1])
+	}
+
+	stored, err := buildStore.GetBuild(build.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored.Jobs) != 2 {
+		t.Errorf("expected the build to have its jobs, got %v", stored.Jobs)
+	}
+	if jobs, err := buildStore.ListJobs("missing"); err != nil || len(jobs) != 0 {
+		t.Errorf("expected no jobs for a missing build, got %v (%v)", jobs, err)
+	}
+}
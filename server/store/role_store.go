@@ -0,0 +1,243 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type RoleStore interface {
+	CreateTable() error
+	SetUserRole(userId string, role pb.Role) error
+	GetUserRole(userId string) (pb.Role, error)
+	SetRepositoryRole(repositoryId, userId string, role pb.Role) error
+	GetRepositoryRole(repositoryId, userId string) (pb.Role, error)
+	DeleteRepositoryRole(repositoryId, userId string) (bool, error)
+	DeleteUserRoles(userId string) error
+	DeleteRepositoryRoles(repositoryId string) error
+}
+
+type SQLRoleStore struct {
+	db *sql.DB
+}
+
+// NewSQLRoleStore creates a new SQLRoleStore given a database connection.
+//
+// If the user_roles and repository_roles tables do not exist in the database,
+// they will be created.
+//
+// The function will log a fatal error if there is an issue creating the tables.
+func NewSQLRoleStore(db *sql.DB) *SQLRoleStore {
+	store := &SQLRoleStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create roles tables: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the user_roles and repository_roles tables in the SQLite
+// database if they do not exist.
+//
+// The user_roles table holds the global roles of users, and has the following
+// columns:
+// - user_id: the ID of the user, which is the primary key
+// - role: the global role of the user
+// - updated_at: the timestamp when the role was last set
+//
+// The repository_roles table holds the roles granted to users on a repository,
+// and has the following columns:
+// - repository_id: the ID of the repository
+// - user_id: the ID of the user
+// - role: the role of the user on the repository
+// - updated_at: the timestamp when the role was last granted
+//
+// When the user_roles table is created, the users that already exist are made
+// administrators, as every user could call every method before roles existed.
+//
+// Returns an error if there is an issue creating the tables.
+func (s *SQLRoleStore) CreateTable() error {
+	log.Println("Creating roles tables...")
+	var existing int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'user_roles'").Scan(&existing)
+	if err != nil {
+		log.Println("Error checking roles tables:", err)
+		return err
+	}
+
+	query := `
+        CREATE TABLE IF NOT EXISTS user_roles (
+            user_id TEXT PRIMARY KEY,
+            role INTEGER NOT NULL,
+            updated_at INTEGER NOT NULL
+        );
+        CREATE TABLE IF NOT EXISTS repository_roles (
+            repository_id TEXT NOT NULL,
+            user_id TEXT NOT NULL,
+            role INTEGER NOT NULL,
+            updated_at INTEGER NOT NULL,
+            PRIMARY KEY (repository_id, user_id)
+        );
+    `
+	if _, err := s.db.Exec(query); err != nil {
+		log.Println("Error creating roles tables:", err)
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var users int
+	err = s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'").Scan(&users)
+	if err != nil || users == 0 {
+		return err
+	}
+	_, err = s.db.Exec("INSERT INTO user_roles (user_id, role, updated_at) SELECT id, ?, ? FROM users", pb.Role_ADMIN, time.Now().Unix())
+	if err != nil {
+		log.Println("Error making existing users administrators:", err)
+	}
+	return err
+}
+
+// SetUserRole sets the global role of a user, replacing any previous role.
+// Setting the NONE role removes the global role of the user.
+//
+// Parameters:
+// - userId: The ID of the user.
+// - role: The global role of the user.
+//
+// Returns an error if there is an issue recording the role.
+func (s *SQLRoleStore) SetUserRole(userId string, role pb.Role) error {
+	var err error
+	if role == pb.Role_NONE {
+		_, err = s.db.Exec("DELETE FROM user_roles WHERE user_id = ?", userId)
+	} else {
+		query := `
+            INSERT INTO user_roles (user_id, role, updated_at) VALUES (?, ?, ?)
+            ON CONFLICT (user_id) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at
+        `
+		_, err = s.db.Exec(query, userId, role, time.Now().Unix())
+	}
+	if err != nil {
+		log.Println("Error recording user role:", err)
+	}
+	return err
+}
+
+// GetUserRole gets the global role of a user.
+//
+// Parameters:
+// - userId: The ID of the user.
+//
+// Returns:
+// - pb.Role: The global role of the user, or NONE if it has none.
+// - error: An error if there is an issue reading the role.
+func (s *SQLRoleStore) GetUserRole(userId string) (pb.Role, error) {
+	return s.getRole("SELECT role FROM user_roles WHERE user_id = ?", userId)
+}
+
+// SetRepositoryRole grants a role on a repository to a user, replacing any
+// role previously granted to them on the repository.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+// - userId: The ID of the user.
+// - role: The role of the user on the repository.
+//
+// Returns an error if there is an issue recording the role.
+func (s *SQLRoleStore) SetRepositoryRole(repositoryId, userId string, role pb.Role) error {
+	query := `
+        INSERT INTO repository_roles (repository_id, user_id, role, updated_at) VALUES (?, ?, ?, ?)
+        ON CONFLICT (repository_id, user_id) DO UPDATE SET role = excluded.role, updated_at = excluded.updated_at
+    `
+	_, err := s.db.Exec(query, repositoryId, userId, role, time.Now().Unix())
+	if err != nil {
+		log.Println("Error recording repository role:", err)
+	}
+	return err
+}
+
+// GetRepositoryRole gets the role granted to a user on a repository.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+// - userId: The ID of the user.
+//
+// Returns:
+// - pb.Role: The role of the user on the repository, or NONE if it has none.
+// - error: An error if there is an issue reading the role.
+func (s *SQLRoleStore) GetRepositoryRole(repositoryId, userId string) (pb.Role, error) {
+	return s.getRole("SELECT role FROM repository_roles WHERE repository_id = ? AND user_id = ?", repositoryId, userId)
+}
+
+// DeleteRepositoryRole revokes the role granted to a user on a repository.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+// - userId: The ID of the user.
+//
+// Returns:
+// - bool: Whether the user had a role on the repository.
+// - error: An error if there is an issue deleting the role.
+func (s *SQLRoleStore) DeleteRepositoryRole(repositoryId, userId string) (bool, error) {
+	result, err := s.db.Exec("DELETE FROM repository_roles WHERE repository_id = ? AND user_id = ?", repositoryId, userId)
+	if err != nil {
+		log.Println("Error deleting repository role:", err)
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
+
+// DeleteUserRoles deletes the global role of a user and the roles granted to
+// them on every repository.
+//
+// Parameters:
+// - userId: The ID of the user.
+//
+// Returns an error if there is an issue deleting the roles.
+func (s *SQLRoleStore) DeleteUserRoles(userId string) error {
+	if _, err := s.db.Exec("DELETE FROM user_roles WHERE user_id = ?", userId); err != nil {
+		log.Println("Error deleting user roles:", err)
+		return err
+	}
+	_, err := s.db.Exec("DELETE FROM repository_roles WHERE user_id = ?", userId)
+	if err != nil {
+		log.Println("Error deleting user roles:", err)
+	}
+	return err
+}
+
+// DeleteRepositoryRoles deletes the roles granted on a repository.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+//
+// Returns an error if there is an issue deleting the roles.
+func (s *SQLRoleStore) DeleteRepositoryRoles(repositoryId string) error {
+	_, err := s.db.Exec("DELETE FROM repository_roles WHERE repository_id = ?", repositoryId)
+	if err != nil {
+		log.Println("Error deleting repository roles:", err)
+	}
+	return err
+}
+
+// getRole reads a single role with query, returning NONE when there is no row.
+func (s *SQLRoleStore) getRole(query string, args ...any) (pb.Role, error) {
+	var role int32
+	err := s.db.QueryRow(query, args...).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pb.Role_NONE, nil
+	}
+	if err != nil {
+		log.Println("Error reading role:", err)
+		return pb.Role_NONE, err
+	}
+	return pb.Role(role), nil
+}
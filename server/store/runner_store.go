@@ -0,0 +1,167 @@
+package store
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type RunnerStore interface {
+	CreateTable() error
+	RegisterRunner(name string, labels []string) (*pb.RunnerResponse, error)
+	GetRunner(id string) (*pb.RunnerResponse, error)
+	ListRunners() ([]*pb.RunnerResponse, error)
+	UpdateLastSeen(id string) error
+}
+
+type SQLRunnerStore struct {
+	db *sql.DB
+}
+
+// NewSQLRunnerStore creates a new SQLRunnerStore given a database connection.
+//
+// If the runners table does not exist in the database, it will be created.
+//
+// The function will log a fatal error if there is an issue creating the table.
+func NewSQLRunnerStore(db *sql.DB) *SQLRunnerStore {
+	store := &SQLRunnerStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create runners table: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the runners table in the SQLite database if it does not exist.
+//
+// The runners table has the following columns:
+// - id: the ID of the runner, which is the primary key
+// - name: the unique name of the runner
+// - labels: the comma separated labels advertised by the runner
+// - last_seen: the timestamp of the last call made by the runner
+//
+// Returns an error if there is an issue creating the table.
+func (s *SQLRunnerStore) CreateTable() error {
+	log.Println("Creating runners table...")
+	query := `
+        CREATE TABLE IF NOT EXISTS runners (
+            id TEXT PRIMARY KEY,
+            name TEXT NOT NULL UNIQUE,
+            labels TEXT NOT NULL,
+            last_seen INTEGER NOT NULL
+        );
+    `
+	_, err := s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating runners table:", err)
+		return err
+	}
+	return nil
+}
+
+// RegisterRunner records a runner with the given name and labels.
+//
+// Runners are identified by their name, so a runner registering again keeps
+// its ID and has its labels replaced.
+//
+// Parameters:
+// - name: The name of the runner.
+// - labels: The labels advertised by the runner.
+//
+// Returns:
+// - *pb.RunnerResponse: The registered runner.
+// - error: An error if there is an issue registering the runner.
+func (s *SQLRunnerStore) RegisterRunner(name string, labels []string) (*pb.RunnerResponse, error) {
+	log.Printf("Registering runner %v with labels %v in database...\n", name, labels)
+	query := `
+        INSERT INTO runners (id, name, labels, last_seen) VALUES (?, ?, ?, ?)
+        ON CONFLICT (name) DO UPDATE SET labels = excluded.labels, last_seen = excluded.last_seen
+    `
+	_, err := s.db.Exec(query, uuid.New().String(), name, strings.Join(labels, ","), time.Now().Unix())
+	if err != nil {
+		log.Println("Error registering runner:", err)
+		return nil, err
+	}
+
+	row := s.db.QueryRow("SELECT id, name, labels, last_seen FROM runners WHERE name = ?", name)
+	return scanRunner(row)
+}
+
+// GetRunner gets a runner by ID.
+//
+// Parameters:
+// - id: The ID of the runner to retrieve.
+//
+// Returns:
+// - *pb.RunnerResponse: The runner information.
+// - error: An error if there is an issue retrieving the runner.
+func (s *SQLRunnerStore) GetRunner(id string) (*pb.RunnerResponse, error) {
+	row := s.db.QueryRow("SELECT id, name, labels, last_seen FROM runners WHERE id = ?", id)
+	runner, err := scanRunner(row)
+	if err != nil {
+		log.Println("Error getting runner:", err)
+		return nil, err
+	}
+	return runner, nil
+}
+
+// ListRunners lists all registered runners, ordered by name.
+//
+// Returns:
+// - []*pb.RunnerResponse: The registered runners.
+// - error: An error if there is an issue listing the runners.
+func (s *SQLRunnerStore) ListRunners() ([]*pb.RunnerResponse, error) {
+	rows, err := s.db.Query("SELECT id, name, labels, last_seen FROM runners ORDER BY name")
+	if err != nil {
+		log.Println("Error listing runners:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runners []*pb.RunnerResponse
+	for rows.Next() {
+		runner, err := scanRunner(rows)
+		if err != nil {
+			log.Println("Error scanning runner:", err)
+			return nil, err
+		}
+		runners = append(runners, runner)
+	}
+	return runners, rows.Err()
+}
+
+// UpdateLastSeen records that a runner has just called the server.
+//
+// Parameters:
+// - id: The ID of the runner.
+//
+// Returns an error if there is an issue updating the runner.
+func (s *SQLRunnerStore) UpdateLastSeen(id string) error {
+	_, err := s.db.Exec("UPDATE runners SET last_seen = ? WHERE id = ?", time.Now().Unix(), id)
+	if err != nil {
+		log.Println("Error updating runner:", err)
+	}
+	return err
+}
+
+// scanRunner scans a runner row selected with its id, name, labels and last_seen.
+func scanRunner(row scanner) (*pb.RunnerResponse, error) {
+	runner := &pb.RunnerResponse{}
+	var labels string
+	var lastSeen int64
+	if err := row.Scan(&runner.Id, &runner.Name, &labels, &lastSeen); err != nil {
+		return nil, err
+	}
+	if labels != "" {
+		runner.Labels = strings.Split(labels, ",")
+	}
+	runner.LastSeen = optionalTimestamp(lastSeen)
+	return runner, nil
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type ScheduleStore interface {
+	CreateTable() error
+	GetLastFired(repositoryId, branch, cron string) (time.Time, bool, error)
+	SetLastFired(repositoryId, branch, cron string, firedAt time.Time) error
+}
+
+type SQLScheduleStore struct {
+	db *sql.DB
+}
+
+// NewSQLScheduleStore creates a new SQLScheduleStore given a database connection.
+//
+// If the schedules table does not exist in the database, it will be created.
+//
+// The function will log a fatal error if there is an issue creating the table.
+func NewSQLScheduleStore(db *sql.DB) *SQLScheduleStore {
+	store := &SQLScheduleStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create schedules table: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the schedules table in the SQLite database if it does not exist.
+//
+// The schedules table has the following columns:
+// - repository_id: the ID of the repository whose pipeline declares the schedule
+// - branch: the branch built by the schedule
+// - cron: the cron expression of the schedule
+// - last_fired: the timestamp of the last time the schedule queued a build
+//
+// Returns an error if there is an issue creating the table.
+func (s *SQLScheduleStore) CreateTable() error {
+	log.Println("Creating schedules table...")
+	query := `
+        CREATE TABLE IF NOT EXISTS schedules (
+            repository_id TEXT NOT NULL,
+            branch TEXT NOT NULL,
+            cron TEXT NOT NULL,
+            last_fired INTEGER NOT NULL,
+            PRIMARY KEY (repository_id, branch, cron)
+        );
+    `
+	_, err := s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating schedules table:", err)
+		return err
+	}
+	return nil
+}
+
+// GetLastFired gets the last time a schedule queued a build.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+// - branch: The branch built by the schedule.
+// - cron: The cron expression of the schedule.
+//
+// Returns:
+// - time.Time: The last time the schedule fired.
+// - bool: Whether the schedule was seen before.
+// - error: An error if there is an issue retrieving the schedule.
+func (s *SQLScheduleStore) GetLastFired(repositoryId, branch, cron string) (time.Time, bool, error) {
+	query := "SELECT last_fired FROM schedules WHERE repository_id = ? AND branch = ? AND cron = ?"
+	var lastFired int64
+	err := s.db.QueryRow(query, repositoryId, branch, cron).Scan(&lastFired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		log.Println("Error getting schedule:", err)
+		return time.Time{}, false, err
+	}
+	return time.Unix(lastFired, 0).UTC(), true, nil
+}
+
+// SetLastFired records the last time a schedule queued a build.
+//
+// Parameters:
+// - repositoryId: The ID of the repository.
+// - branch: The branch built by the schedule.
+// - cron: The cron expression of the schedule.
+// - firedAt: The time the schedule fired.
+//
+// Returns an error if there is an issue recording the schedule.
+func (s *SQLScheduleStore) SetLastFired(repositoryId, branch, cron string, firedAt time.Time) error {
+	query := `
+        INSERT INTO schedules (repository_id, branch, cron, last_fired) VALUES (?, ?, ?, ?)
+        ON CONFLICT (repository_id, branch, cron) DO UPDATE SET last_fired = excluded.last_fired
+    `
+	_, err := s.db.Exec(query, repositoryId, branch, cron, firedAt.Unix())
+	if err != nil {
+		log.Println("Error recording schedule:", err)
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Secret is an encrypted secret of a repository.
+type Secret struct {
+	RepositoryId string
+	Name         string
+	Value        []byte
+	UpdatedAt    time.Time
+}
+
+type SecretStore interface {
+	CreateTable() error
+	SetSecret(secret Secret) error
+	ListSecrets(repositoryId string) ([]Secret, error)
+	DeleteSecret(repositoryId, name string) (bool, error)
+	DeleteSecrets(repositoryId string) error
+}
+
+type SQLSecretStore struct {
+	db *sql.DB
+}
+
+// NewSQLSecretStore creates a new SQLSecretStore given a database connection.
+//
+// If the secrets table does not exist in the database, it will be created.
+//
+// The function will log a fatal error if there is an issue creating the table.
+func NewSQLSecretStore(db *sql.DB) *SQLSecretStore {
+	store := &SQLSecretStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create secrets table: %v", err)
+	}
+	return store
+}
+
+// CreateTable creates the secrets table in the SQLite database if it does not exist.
+//
+// The table has the following columns:
+// - repository_id: the ID of the repository the secret belongs to
+// - name: the name of the secret, unique in the repository
+// - value: the encrypted value of the secret
+// - updated_at: the timestamp when the secret was last set
+//
+// Returns an error if there is an issue creating the table.
+func (s *SQLSecretStore) CreateTable() error {
+	log.Println("Creating secrets table...")
+	query := `
+        CREATE TABLE IF NOT EXISTS secrets (
+            repository_id TEXT NOT NULL,
+            name TEXT NOT NULL,
+            value BLOB NOT NULL,
+            updated_at INTEGER NOT NULL,
+            PRIMARY KEY (repository_id, name)
+        );
+    `
+	_, err := s.db.Exec(query)
+	if err != nil {
+		log.Println("Error creating secrets table:", err)
+	}
+	return err
+}
+
+// SetSecret records an encrypted secret, replacing the value of any secret of
+// the repository with the same name.
+//
+// Parameters:
+// - secret: The secret, whose value is already encrypted.
+//
+// Returns an error if there is an issue recording the secret.
+func (s *SQLSecretStore) SetSecret(secret Secret) error {
+	query := `
+        INSERT INTO secrets (repository_id, name, value, updated_at) VALUES (?, ?, ?, ?)
+        ON CONFLICT (repository_id, name) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+    `
+	_, err := s.db.Exec(query, secret.RepositoryId, secret.Name, secret.Value, secret.UpdatedAt.Unix())
+	if err != nil {
+		log.Println("Error recording secret:", err)
+	}
+	return err
+}
+
+// ListSecrets lists the encrypted secrets of a repository, sorted by name.
+//
+// Parameters:
+// - repositoryId: The ID of the repository whose secrets are listed.
+//
+// Returns:
+// - []Secret: The secrets of the repository.
+// - error: An error if there is an issue listing secrets.
+func (s *SQLSecretStore) ListSecrets(repositoryId string) ([]Secret, error) {
+	rows, err := s.db.Query("SELECT repository_id, name, value, updated_at FROM secrets WHERE repository_id = ? ORDER BY name", repositoryId)
+	if err != nil {
+		log.Println("Error listing secrets:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var secrets []Secret
+	for rows.Next() {
+		var secret Secret
+		var updatedAt int64
+		if err := rows.Scan(&secret.RepositoryId, &secret.Name, &secret.Value, &updatedAt); err != nil {
+			log.Println("Error scanning secret:", err)
+			return nil, err
+		}
+		secret.UpdatedAt = time.Unix(updatedAt, 0)
+		secrets = append(secrets, secret)
+	}
+	return secrets, rows.Err()
+}
+
+// DeleteSecret deletes a secret of a repository.
+//
+// Parameters:
+// - repositoryId: The ID of the repository the secret belongs to.
+// - name: The name of the secret.
+//
+// Returns:
+// - bool: Whether the secret existed.
+// - error: An error if there is an issue deleting the secret.
+func (s *SQLSecretStore) DeleteSecret(repositoryId, name string) (bool, error) {
+	result, err := s.db.Exec("DELETE FROM secrets WHERE repository_id = ? AND name = ?", repositoryId, name)
+	if err != nil {
+		log.Println("Error deleting secret:", err)
+		return false, err
+	}
+	deleted, err := result.RowsAffected()
+	return deleted > 0, err
+}
+
+// DeleteSecrets deletes all the secrets of a repository.
+//
+// Parameters:
+// - repositoryId: The ID of the repository whose secrets are deleted.
+//
+// Returns an error if there is an issue deleting the secrets.
+func (s *SQLSecretStore) DeleteSecrets(repositoryId string) error {
+	_, err := s.db.Exec("DELETE FROM secrets WHERE repository_id = ?", repositoryId)
+	if err != nil {
+		log.Println("Error deleting secrets:", err)
+	}
+	return err
+}
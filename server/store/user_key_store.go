@@ -0,0 +1,330 @@
+package store
+
+import (
+	"cmp"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	_ "github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrKeyExists is returned by AddKey when the user already has the key.
+var ErrKeyExists = errors.New("the user already has this key")
+
+type UserKeyStore interface {
+	CreateTable() error
+	AddKey(userId string, key *pb.UserKey, publicKey string) error
+	ReplaceKeys(userId string, key *pb.UserKey, publicKey string) error
+	ListKeys(userId string) ([]*pb.UserKey, error)
+	ActivePublicKeys(userId string) ([]string, error)
+	RemoveKey(userId, fingerprint string) (bool, error)
+	DeleteUserKeys(userId string) error
+}
+
+type SQLUserKeyStore struct {
+	db *sql.DB
+}
+
+// NewSQLUserKeyStore creates a new SQLUserKeyStore given a database connection.
+//
+// If the user_keys table does not exist in the database, it will be created.
+//
+// The function will log a fatal error if there is an issue creating the table.
+func NewSQLUserKeyStore(db *sql.DB) *SQLUserKeyStore {
+	store := &SQLUserKeyStore{
+		db: db,
+	}
+	err := store.CreateTable()
+	if err != nil {
+		log.Fatalf("Failed to create user keys table: %v", err)
+	}
+	return store
+}
+
+// NewUserKey parses a public key in the authorized_keys format, and returns
+// its description.
+//
+// Parameters:
+// - publicKey: The public key, such as the content of an id_ed25519.pub file.
+// - name: The name of the key, which defaults to its comment.
+//
+// Returns:
+// - *pb.UserKey: The fingerprint, type, comment and name of the key.
+// - error: An error if the public key cannot be parsed.
+func NewUserKey(publicKey, name string) (*pb.UserKey, error) {
+	key, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	return &pb.UserKey{
+		Fingerprint: ssh.FingerprintSHA256(key),
+		Type:        key.Type(),
+		Comment:     comment,
+		Name:        cmp.Or(name, comment),
+	}, nil
+}
+
+// CreateTable creates the user_keys table in the SQLite database if it does not
+// exist.
+//
+// The user_keys table has the following columns:
+// - user_id: the ID of the user
+// - fingerprint: the SHA256 fingerprint of the key
+// - key_type: the type of the key, such as ssh-ed25519
+// - comment: the comment of the key
+// - name: the name of the key
+// - public_key: the key in the authorized_keys format
+// - created_at: the timestamp when the key was added
+// - expires_at: the timestamp when the key expires, or 0 if it never does
+//
+// When the table is created, the public keys of the existing users are added
+// to it, as users had a single key before.
+//
+// Returns an error if there is an issue creating the table.
+func (s *SQLUserKeyStore) CreateTable() error {
+	log.Println("Creating user keys table...")
+	var existing int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'user_keys'").Scan(&existing)
+	if err != nil {
+		log.Println("Error checking user keys table:", err)
+		return err
+	}
+
+	query := `
+        CREATE TABLE IF NOT EXISTS user_keys (
+            user_id TEXT NOT NULL,
+            fingerprint TEXT NOT NULL,
+            key_type TEXT NOT NULL,
+            comment TEXT NOT NULL,
+            name TEXT NOT NULL,
+            public_key TEXT NOT NULL,
+            created_at INTEGER NOT NULL,
+            expires_at INTEGER NOT NULL,
+            PRIMARY KEY (user_id, fingerprint)
+        )
+    `
+	if _, err := s.db.Exec(query); err != nil {
+		log.Println("Error creating user keys table:", err)
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+	return s.addExistingKeys()
+}
+
+// addExistingKeys adds the public keys stored in the users table to the
+// user_keys table. The keys that cannot be parsed are skipped.
+func (s *SQLUserKeyStore) addExistingKeys() error {
+	var users int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'").Scan(&users)
+	if err != nil || users == 0 {
+		return err
+	}
+	rows, err := s.db.Query("SELECT id, username, public_key FROM users WHERE public_key IS NOT NULL AND public_key != ''")
+	if err != nil {
+		log.Println("Error reading the public keys of users:", err)
+		return err
+	}
+	type existingKey struct{ userId, username, publicKey string }
+	var existing []existingKey
+	for rows.Next() {
+		var key existingKey
+		if err := rows.Scan(&key.userId, &key.username, &key.publicKey); err != nil {
+			rows.Close()
+			return err
+		}
+		existing = append(existing, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, key := range existing {
+		userKey, err := NewUserKey(key.publicKey, "")
+		if err != nil {
+			log.Printf("Skipping the invalid public key of user %v: %v", key.username, err)
+			continue
+		}
+		if err := s.AddKey(key.userId, userKey, key.publicKey); err != nil && !errors.Is(err, ErrKeyExists) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddKey adds a public key to a user.
+//
+// Parameters:
+// - userId: The ID of the user.
+// - key: The description of the key, as returned by NewUserKey, with its
+// optional expiry.
+// - publicKey: The key in the authorized_keys format.
+//
+// Returns:
+// - error: ErrKeyExists if the user already has the key, or an error if there
+// is an issue adding it.
+func (s *SQLUserKeyStore) AddKey(userId string, key *pb.UserKey, publicKey string) error {
+	return addKey(s.db, userId, key, publicKey)
+}
+
+// ReplaceKeys replaces every public key of a user with a single key, so that
+// the previous keys are rejected from then on.
+//
+// Parameters:
+// - userId: The ID of the user.
+// - key: The description of the key, as returned by NewUserKey.
+// - publicKey: The key in the authorized_keys format.
+//
+// Returns an error if there is an issue replacing the keys, in which case the
+// keys of the user are left unchanged.
+func (s *SQLUserKeyStore) ReplaceKeys(userId string, key *pb.UserKey, publicKey string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM user_keys WHERE user_id = ?", userId); err != nil {
+		log.Println("Error removing user keys:", err)
+		return err
+	}
+	if err := addKey(tx, userId, key, publicKey); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// executor is a database or a transaction that statements are executed on.
+type executor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// addKey adds a public key to a user with db. See AddKey.
+func addKey(db executor, userId string, key *pb.UserKey, publicKey string) error {
+	var expiresAt int64
+	if key.ExpiresAt != nil {
+		expiresAt = key.ExpiresAt.AsTime().Unix()
+	}
+	createdAt := time.Now().Unix()
+	key.CreatedAt = timestamppb.New(time.Unix(createdAt, 0))
+	query := `
+        INSERT INTO user_keys (user_id, fingerprint, key_type, comment, name, public_key, created_at, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (user_id, fingerprint) DO NOTHING
+    `
+	result, err := db.Exec(query, userId, key.Fingerprint, key.Type, key.Comment, key.Name, strings.TrimSpace(publicKey), createdAt, expiresAt)
+	if err != nil {
+		log.Println("Error adding user key:", err)
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrKeyExists
+	}
+	return nil
+}
+
+// ListKeys lists the public keys of a user, including the expired ones, in the
+// order they were added.
+//
+// Parameters:
+// - userId: The ID of the user.
+//
+// Returns:
+// - []*pb.UserKey: The keys of the user, without their username.
+// - error: An error if there is an issue listing the keys.
+func (s *SQLUserKeyStore) ListKeys(userId string) ([]*pb.UserKey, error) {
+	query := `
+        SELECT fingerprint, key_type, comment, name, created_at, expires_at FROM user_keys
+        WHERE user_id = ? ORDER BY created_at, name
+    `
+	rows, err := s.db.Query(query, userId)
+	if err != nil {
+		log.Println("Error listing user keys:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*pb.UserKey{}
+	for rows.Next() {
+		key := &pb.UserKey{}
+		var createdAt, expiresAt int64
+		if err := rows.Scan(&key.Fingerprint, &key.Type, &key.Comment, &key.Name, &createdAt, &expiresAt); err != nil {
+			log.Println("Error scanning user key:", err)
+			return nil, err
+		}
+		key.CreatedAt = timestamppb.New(time.Unix(createdAt, 0))
+		key.ExpiresAt = optionalTimestamp(expiresAt)
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// ActivePublicKeys gets the public keys of a user that have not expired.
+//
+// Parameters:
+// - userId: The ID of the user.
+//
+// Returns:
+// - []string: The keys in the authorized_keys format.
+// - error: An error if there is an issue reading the keys.
+func (s *SQLUserKeyStore) ActivePublicKeys(userId string) ([]string, error) {
+	query := "SELECT public_key FROM user_keys WHERE user_id = ? AND (expires_at = 0 OR expires_at > ?)"
+	rows, err := s.db.Query(query, userId, time.Now().Unix())
+	if err != nil {
+		log.Println("Error reading user keys:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var publicKeys []string
+	for rows.Next() {
+		var publicKey string
+		if err := rows.Scan(&publicKey); err != nil {
+			return nil, err
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, rows.Err()
+}
+
+// RemoveKey removes a public key of a user.
+//
+// Parameters:
+// - userId: The ID of the user.
+// - fingerprint: The SHA256 fingerprint of the key.
+//
+// Returns:
+// - bool: True if the user had the key.
+// - error: An error if there is an issue removing the key.
+func (s *SQLUserKeyStore) RemoveKey(userId, fingerprint string) (bool, error) {
+	result, err := s.db.Exec("DELETE FROM user_keys WHERE user_id = ? AND fingerprint = ?", userId, fingerprint)
+	if err != nil {
+		log.Println("Error removing user key:", err)
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// DeleteUserKeys removes every public key of a user, when the user is deleted.
+//
+// Parameters:
+// - userId: The ID of the user.
+//
+// Returns an error if there is an issue removing the keys.
+func (s *SQLUserKeyStore) DeleteUserKeys(userId string) error {
+	_, err := s.db.Exec("DELETE FROM user_keys WHERE user_id = ?", userId)
+	if err != nil {
+		log.Println("Error removing user keys:", err)
+	}
+	return err
+}
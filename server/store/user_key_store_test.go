@@ -0,0 +1,174 @@
+package store
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newPublicKey returns a new public key in the authorized_keys format, with its
+// description.
+func newPublicKey(t *testing.T, comment string) (string, *pb.UserKey) {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshKey, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshKey))) + " " + comment
+	key, err := NewUserKey(publicKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return publicKey, key
+}
+
+func TestNewUserKey(t *testing.T) {
+	publicKey, key := newPublicKey(t, "alice@laptop")
+	if key.Type != "ssh-ed25519" || key.Comment != "alice@laptop" || key.Name != "alice@laptop" || !strings.HasPrefix(key.Fingerprint, "SHA256:") {
+		t.Errorf("unexpected key description %v", key)
+	}
+	if named, err := NewUserKey(publicKey, "laptop"); err != nil || named.Name != "laptop" {
+		t.Errorf("expected the key to be named laptop, got %v (%v)", named, err)
+	}
+	if _, err := NewUserKey("ssh-ed25519 invalid", ""); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+}
+
+func TestUserKeyStoreAddsListsAndRemovesKeys(t *testing.T) {
+	keyStore := NewSQLUserKeyStore(openDB(t))
+	laptop, laptopKey := newPublicKey(t, "alice@laptop")
+	desktop, desktopKey := newPublicKey(t, "alice@desktop")
+
+	if err := keyStore.AddKey("alice", laptopKey, laptop); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStore.AddKey("alice", desktopKey, desktop); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStore.AddKey("bob", desktopKey, desktop); err != nil {
+		t.Fatalf("expected another user to add the same key, got %v", err)
+	}
+
+	keys, err := keyStore.ListKeys("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprints := []string{}
+	for _, key := range keys {
+		fingerprints = append(fingerprints, key.Fingerprint)
+	}
+	slices.Sort(fingerprints)
+	expected := []string{laptopKey.Fingerprint, desktopKey.Fingerprint}
+	slices.Sort(expected)
+	if !slices.Equal(fingerprints, expected) {
+		t.Fatalf("expected the keys %v, got %v", expected, fingerprints)
+	}
+
+	if removed, err := keyStore.RemoveKey("alice", laptopKey.Fingerprint); err != nil || !removed {
+		t.Fatalf("expected the laptop key to be removed, got %v (%v)", removed, err)
+	}
+	if removed, err := keyStore.RemoveKey("alice", laptopKey.Fingerprint); err != nil || removed {
+		t.Fatalf("expected a removed key not to be removed again, got %v (%v)", removed, err)
+	}
+	if publicKeys, err := keyStore.ActivePublicKeys("alice"); err != nil || !slices.Equal(publicKeys, []string{desktop}) {
+		t.Errorf("expected only the desktop key of alice, got %v (%v)", publicKeys, err)
+	}
+	if publicKeys, err := keyStore.ActivePublicKeys("bob"); err != nil || !slices.Equal(publicKeys, []string{desktop}) {
+		t.Errorf("expected the key of bob to be kept, got %v (%v)", publicKeys, err)
+	}
+
+	if err := keyStore.DeleteUserKeys("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if keys, err := keyStore.ListKeys("alice"); err != nil || len(keys) != 0 {
+		t.Errorf("expected alice to have no keys, got %v (%v)", keys, err)
+	}
+}
+
+func TestUserKeyStoreRejectsDuplicateKeys(t *testing.T) {
+	keyStore := NewSQLUserKeyStore(openDB(t))
+	publicKey, key := newPublicKey(t, "alice@laptop")
+
+	if err := keyStore.AddKey("alice", key, publicKey); err != nil {
+		t.Fatal(err)
+	}
+	renamed, err := NewUserKey(publicKey, "renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStore.AddKey("alice", renamed, publicKey); !errors.Is(err, ErrKeyExists) {
+		t.Fatalf("expected ErrKeyExists, got %v", err)
+	}
+	if keys, err := keyStore.ListKeys("alice"); err != nil || len(keys) != 1 || keys[0].Name != "alice@laptop" {
+		t.Errorf("expected the first key to be kept, got %v (%v)", keys, err)
+	}
+}
+
+func TestUserKeyStoreSkipsExpiredKeys(t *testing.T) {
+	keyStore := NewSQLUserKeyStore(openDB(t))
+	expired, expiredKey := newPublicKey(t, "alice@old-laptop")
+	active, activeKey := newPublicKey(t, "alice@laptop")
+	expiredKey.ExpiresAt = timestamppb.New(time.Now().Add(-time.Hour))
+	activeKey.ExpiresAt = timestamppb.New(time.Now().Add(time.Hour))
+
+	if err := keyStore.AddKey("alice", expiredKey, expired); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStore.AddKey("alice", activeKey, active); err != nil {
+		t.Fatal(err)
+	}
+	if publicKeys, err := keyStore.ActivePublicKeys("alice"); err != nil || !slices.Equal(publicKeys, []string{active}) {
+		t.Errorf("expected only the active key, got %v (%v)", publicKeys, err)
+	}
+	if keys, err := keyStore.ListKeys("alice"); err != nil || len(keys) != 2 {
+		t.Errorf("expected the expired key to be listed, got %v (%v)", keys, err)
+	}
+}
+
+func TestUserKeyStoreReplacesKeys(t *testing.T) {
+	keyStore := NewSQLUserKeyStore(openDB(t))
+	laptop, laptopKey := newPublicKey(t, "alice@laptop")
+	desktop, desktopKey := newPublicKey(t, "alice@desktop")
+
+	if err := keyStore.AddKey("alice", laptopKey, laptop); err != nil {
+		t.Fatal(err)
+	}
+	if err := keyStore.ReplaceKeys("alice", desktopKey, desktop); err != nil {
+		t.Fatal(err)
+	}
+	if publicKeys, err := keyStore.ActivePublicKeys("alice"); err != nil || !slices.Equal(publicKeys, []string{desktop}) {
+		t.Errorf("expected only the replacing key, got %v (%v)", publicKeys, err)
+	}
+}
+
+func TestUserKeyStoreAddsExistingUserKeys(t *testing.T) {
+	db := openDB(t)
+	userStore := NewSQLUserStore(db)
+	publicKey, key := newPublicKey(t, "alice@laptop")
+	user, err := userStore.CreateUser(&pb.CreateUserRequest{Username: "alice", PublicKey: publicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := userStore.CreateUser(&pb.CreateUserRequest{Username: "bob", PublicKey: "invalid"}); err != nil {
+		t.Fatal(err)
+	}
+
+	keyStore := NewSQLUserKeyStore(db)
+	keys, err := keyStore.ListKeys(user.Id)
+	if err != nil || len(keys) != 1 || keys[0].Fingerprint != key.Fingerprint {
+		t.Errorf("expected the key of alice to be added, got %v (%v)", keys, err)
+	}
+}
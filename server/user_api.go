@@ -5,13 +5,19 @@ import (
 	"log"
 
 	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // CreateUser creates a new user with the given information.
 //
 // The request must contain the username and public key of the user to be created.
 // The username is used to identify the user.
-// The public key is used to store the user's public key.
+// The public key is the first key of the user, and more are added with AddUserKey.
+//
+// The request may contain the global role of the user, which otherwise only
+// has the roles granted to them on repositories.
 //
 // The response will contain the created user information.
 //
@@ -25,11 +31,26 @@ import (
 // - error: An error if there is an issue creating the user.
 func (s *server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
 	log.Printf("Creating user with request: %v", req)
+	if _, ok := pb.Role_name[int32(req.Role)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid role %v", req.Role)
+	}
+	key, err := store.NewUserKey(req.PublicKey, "")
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
 	response, err := s.userStore.CreateUser(req)
 	if err != nil {
 		log.Printf("Error creating user: %v", err)
 		return nil, err
 	}
+	if err := s.userKeyStore.AddKey(response.Id, key, req.PublicKey); err != nil {
+		return nil, err
+	}
+	if req.Role != pb.Role_NONE {
+		if err := s.roleStore.SetUserRole(response.Id, req.Role); err != nil {
+			return nil, err
+		}
+	}
 	return response, err
 }
 
@@ -37,7 +58,8 @@ func (s *server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb
 //
 // The request must contain the user ID, username and public key.
 // The ID is used to identify the user to be updated.
-// The username and public key are used to update the user information.
+// The username and public key are used to update the user information. A public
+// key replaces every key of the user, while an empty one leaves them unchanged.
 //
 // The response will contain the user information.
 //
@@ -51,11 +73,23 @@ func (s *server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb
 // - error: An error if there is an issue updating the user.
 func (s *server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
 	log.Printf("Updating user with request: %v", req)
+	var key *pb.UserKey
+	if req.PublicKey != "" {
+		var err error
+		if key, err = store.NewUserKey(req.PublicKey, ""); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+		}
+	}
 	response, err := s.userStore.UpdateUser(req)
 	if err != nil {
 		log.Printf("Error updating user: %v", err)
 		return nil, err
 	}
+	if key != nil {
+		if err := s.userKeyStore.ReplaceKeys(req.Id, key, req.PublicKey); err != nil {
+			return nil, err
+		}
+	}
 	return response, err
 }
 
@@ -106,7 +140,8 @@ func (s *server) GetUser(ctx context.Context, req *pb.GetUserRequest) (response
 
 // DeleteUser deletes a user by ID.
 //
-// The request must contain the ID of the user to be deleted.
+// The request must contain the ID of the user to be deleted. The roles and keys
+// of the user are deleted with it.
 //
 // The response will contain an empty message on success.
 func (s *server) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.Empty, error) {
@@ -116,5 +151,11 @@ func (s *server) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb
 		log.Printf("Error deleting user: %v", err)
 		return nil, err
 	}
+	if err := s.roleStore.DeleteUserRoles(req.Id); err != nil {
+		log.Printf("Error deleting roles of user: %v", err)
+	}
+	if err := s.userKeyStore.DeleteUserKeys(req.Id); err != nil {
+		log.Printf("Error deleting keys of user: %v", err)
+	}
 	return &pb.Empty{}, nil
 }
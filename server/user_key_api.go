@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/permission"
+	"github.com/EdmilsonRodrigues/ophelia-ci/server/store"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AddUserKey adds a public key to a user, so that they can authenticate with
+// any of their keys, such as one per machine.
+//
+// Users manage their own keys, and administrators the keys of every user.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the username, the public key and its optional
+//     name and expiry.
+//
+// Returns:
+//   - *pb.UserKey: The fingerprint, type, comment, name and expiry of the key.
+//   - error: An error if the user does not exist, the key is invalid, expired or
+//     already added.
+func (s *server) AddUserKey(ctx context.Context, req *pb.AddUserKeyRequest) (*pb.UserKey, error) {
+	log.Printf("Adding key %v to user %v", req.Name, req.Username)
+	user, err := s.keyOwner(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	key, err := store.NewUserKey(req.PublicKey, req.Name)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid public key: %v", err)
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.AsTime().After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "the key has already expired")
+		}
+		key.ExpiresAt = req.ExpiresAt
+	}
+
+	err = s.userKeyStore.AddKey(user.Id, key, req.PublicKey)
+	if errors.Is(err, store.ErrKeyExists) {
+		return nil, status.Errorf(codes.AlreadyExists, "%s already has the key %s", user.Username, key.Fingerprint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	key.Username = user.Username
+	return key, nil
+}
+
+// ListUserKeys lists the public keys of a user, including the expired ones.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the username.
+//
+// Returns:
+//   - *pb.ListUserKeysResponse: The keys of the user, in the order they were
+//     added.
+//   - error: An error if the user does not exist.
+func (s *server) ListUserKeys(ctx context.Context, req *pb.ListUserKeysRequest) (*pb.ListUserKeysResponse, error) {
+	log.Printf("Listing keys of user %v", req.Username)
+	user, err := s.keyOwner(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s.userKeyStore.ListKeys(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		key.Username = user.Username
+	}
+	return &pb.ListUserKeysResponse{Keys: keys}, nil
+}
+
+// RemoveUserKey removes a public key of a user, which is rejected from then on.
+//
+// Parameters:
+//   - ctx: The context for the request.
+//   - req: The request containing the username and the SHA256 fingerprint of
+//     the key.
+//
+// Returns:
+//   - *pb.Empty: An empty response.
+//   - error: An error if the user does not exist or does not have the key.
+func (s *server) RemoveUserKey(ctx context.Context, req *pb.RemoveUserKeyRequest) (*pb.Empty, error) {
+	log.Printf("Removing key %v of user %v", req.Fingerprint, req.Username)
+	user, err := s.keyOwner(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.userKeyStore.RemoveKey(user.Id, req.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, status.Errorf(codes.NotFound, "%s has no key %s", user.Username, req.Fingerprint)
+	}
+	return &pb.Empty{}, nil
+}
+
+// keyOwner gets the user whose keys are managed by username, checking that the
+// caller is that user or an administrator.
+func (s *server) keyOwner(ctx context.Context, username string) (*pb.UserResponse, error) {
+	if caller := usernameFromContext(ctx); caller != "" && caller != username {
+		role, err := s.userRole(caller, "")
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to read roles")
+		}
+		if !permission.Allows(role, pb.Role_ADMIN) {
+			return nil, status.Error(codes.PermissionDenied, "only administrators manage the keys of other users")
+		}
+	}
+	return s.roleUser(username)
+}
+
+// userPublicKeys gets the public keys of a user that have not expired.
+//
+// Returns:
+// - []ssh.PublicKey: The keys of the user, which are none if the user does not
+// exist.
+// - error: An error if there is an issue reading the keys.
+func (s *server) userPublicKeys(username string) ([]ssh.PublicKey, error) {
+	user, err := s.userStore.GetUserByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys, err := s.userKeyStore.ActivePublicKeys(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]ssh.PublicKey, 0, len(publicKeys))
+	for _, publicKey := range publicKeys {
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+		if err != nil {
+			log.Printf("Skipping invalid key of user %v: %v", username, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
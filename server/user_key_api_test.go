@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/EdmilsonRodrigues/ophelia-ci"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// asUser returns a context authenticated as username.
+func asUser(username string) context.Context {
+	return context.WithValue(context.Background(), usernameContextKey{}, username)
+}
+
+// createUsers creates users with the given global roles.
+func createUsers(t *testing.T, s *server, roles map[string]pb.Role) {
+	t.Helper()
+	for username, role := range roles {
+		user, err := s.userStore.CreateUser(&pb.CreateUserRequest{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.roleStore.SetUserRole(user.Id, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUserKeysAreManagedByTheirUser(t *testing.T) {
+	s := newUserServer(t)
+	createUsers(t, s, map[string]pb.Role{"alice": pb.Role_DEVELOPER})
+	publicKey := newPublicKey(t, "alice@laptop")
+
+	key, err := s.AddUserKey(asUser("alice"), &pb.AddUserKeyRequest{Username: "alice", PublicKey: publicKey, Name: "laptop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Username != "alice" || key.Name != "laptop" {
+		t.Errorf("unexpected key %v", key)
+	}
+	if _, err := s.AddUserKey(asUser("alice"), &pb.AddUserKeyRequest{Username: "alice", PublicKey: publicKey}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists for a duplicate key, got %v", err)
+	}
+
+	keys, err := s.ListUserKeys(asUser("alice"), &pb.ListUserKeysRequest{Username: "alice"})
+	if err != nil || len(keys.Keys) != 1 || keys.Keys[0].Fingerprint != key.Fingerprint {
+		t.Fatalf("expected the key of alice, got %v (%v)", keys, err)
+	}
+
+	if _, err := s.RemoveUserKey(asUser("alice"), &pb.RemoveUserKeyRequest{Username: "alice", Fingerprint: key.Fingerprint}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RemoveUserKey(asUser("alice"), &pb.RemoveUserKeyRequest{Username: "alice", Fingerprint: key.Fingerprint}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for a removed key, got %v", err)
+	}
+}
+
+func TestUserKeysOfOtherUsers(t *testing.T) {
+	s := newUserServer(t)
+	createUsers(t, s, map[string]pb.Role{
+		"alice":   pb.Role_DEVELOPER,
+		"mallory": pb.Role_MAINTAINER,
+		"root":    pb.Role_ADMIN,
+	})
+	key, err := s.AddUserKey(asUser("alice"), &pb.AddUserKeyRequest{Username: "alice", PublicKey: newPublicKey(t, "alice@laptop")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ListUserKeys(asUser("mallory"), &pb.ListUserKeysRequest{Username: "alice"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied listing the keys of another user, got %v", err)
+	}
+	if _, err := s.RemoveUserKey(asUser("mallory"), &pb.RemoveUserKeyRequest{Username: "alice", Fingerprint: key.Fingerprint}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied removing the key of another user, got %v", err)
+	}
+	if _, err := s.AddUserKey(asUser("mallory"), &pb.AddUserKeyRequest{Username: "alice", PublicKey: newPublicKey(t, "mallory")}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied adding a key to another user, got %v", err)
+	}
+	if keys, err := s.ListUserKeys(asUser("alice"), &pb.ListUserKeysRequest{Username: "alice"}); err != nil || len(keys.Keys) != 1 {
+		t.Fatalf("expected the key of alice to be kept, got %v (%v)", keys, err)
+	}
+
+	if keys, err := s.ListUserKeys(asUser("root"), &pb.ListUserKeysRequest{Username: "alice"}); err != nil || len(keys.Keys) != 1 {
+		t.Errorf("expected administrators to list the keys of alice, got %v (%v)", keys, err)
+	}
+	if _, err := s.RemoveUserKey(asUser("root"), &pb.RemoveUserKeyRequest{Username: "alice", Fingerprint: key.Fingerprint}); err != nil {
+		t.Errorf("expected administrators to remove the key of alice, got %v", err)
+	}
+}